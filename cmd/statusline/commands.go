@@ -0,0 +1,290 @@
+// Package main provides the entry point for the status-line CLI tool.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Command names.
+const (
+	// commandRender renders the status line from stdin.
+	commandRender string = "render"
+	// commandVersion prints the version.
+	commandVersion string = "version"
+	// commandConfig manages the config file.
+	commandConfig string = "config"
+	// commandDoctor diagnoses the environment.
+	commandDoctor string = "doctor"
+	// commandPreview renders sample data.
+	commandPreview string = "preview"
+	// commandUpdate installs the latest release.
+	commandUpdate string = "update"
+	// commandUsage reports recorded API usage.
+	commandUsage string = "usage"
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(args []string) int
+}
+
+// commands returns every subcommand in help order.
+//
+// Returns:
+//   - []command: subcommands
+func commands() []command {
+	// Return command table
+	return []command{
+		{name: commandRender, args: "[flags]", summary: "Render the status line from Claude Code JSON on stdin (default)", run: runRender},
+		{name: commandPreview, args: "[flags]", summary: "Render sample data to try themes, icons and layouts", run: runPreview},
+		{name: commandConfig, args: "<command> [flags]", summary: "Create, print or check the config file", run: runConfig},
+		{name: commandDoctor, args: "", summary: "Report configuration and environment diagnostics", run: runDoctor},
+		{name: commandUsage, args: "<command> [flags]", summary: "Report API usage recorded by the status line", run: runUsage},
+		{name: commandUpdate, args: "[flags]", summary: "Install the latest release now", run: runUpdate},
+		{name: commandVersion, args: "", summary: "Print the version", run: runVersion},
+	}
+}
+
+// lookupCommand finds a subcommand by name.
+// Nested commands are named with their parent, as in "config init".
+//
+// Params:
+//   - name: command name
+//
+// Returns:
+//   - command: matching command
+//   - bool: false when no command has this name
+func lookupCommand(name string) (command, bool) {
+	// Scan the command tables
+	for _, cmd := range append(commands(), nestedCommands()...) {
+		// Check name
+		if cmd.name == name {
+			// Return match
+			return cmd, true
+		}
+	}
+	// Return not found
+	return command{}, false
+}
+
+// subcommands returns the commands nested under a command.
+//
+// Params:
+//   - parent: parent command name
+//
+// Returns:
+//   - []command: nested commands, empty when there are none
+func subcommands(parent string) []command {
+	var subs []command
+	// Keep commands named after the parent
+	for _, cmd := range nestedCommands() {
+		// Check parent prefix
+		if strings.HasPrefix(cmd.name, parent+" ") {
+			subs = append(subs, cmd)
+		}
+	}
+	// Return nested commands
+	return subs
+}
+
+// nestedCommands returns every command nested under another one.
+//
+// Returns:
+//   - []command: nested commands of every group
+func nestedCommands() []command {
+	// Return nested command tables
+	return append(configCommands(), usageCommands()...)
+}
+
+// runGroup dispatches to a command nested under a parent, as in "config init".
+//
+// Params:
+//   - parent: parent command name
+//   - args: nested command and its arguments
+//
+// Returns:
+//   - int: process exit code
+func runGroup(parent string, args []string) int {
+	flags := newFlagSet(parent)
+	// Stop on help or invalid flags before a nested command
+	if ok, code := parseFlags(flags, args); !ok {
+		// Return parse status
+		return code
+	}
+	// A nested command is required
+	if flags.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Error: missing %s subcommand\n\n", parent)
+		flags.Usage()
+		// Return usage status
+		return exitUsage
+	}
+	cmd, ok := lookupCommand(parent + " " + flags.Arg(0))
+	// Reject unknown nested commands
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown %s subcommand %q\n\n", parent, flags.Arg(0))
+		flags.Usage()
+		// Return usage status
+		return exitUsage
+	}
+	// Return nested command status
+	return cmd.run(flags.Args()[1:])
+}
+
+// printUsage writes the tool help.
+//
+// Params:
+//   - w: destination
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: status-line [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command, renders the status line from stdin.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	// List each command
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "status-line help <command>" for the flags of a command.`)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit codes: 0 success, 1 failure, 2 invalid arguments.")
+}
+
+// runHelp prints the tool help or the help of one command.
+//
+// Params:
+//   - args: optional command name
+//
+// Returns:
+//   - int: process exit code
+func runHelp(args []string) int {
+	// Without a topic print the tool help
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		// Return success
+		return exitOK
+	}
+	cmd, ok := lookupCommand(args[0])
+	// Reject unknown topics
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n\n", args[0])
+		printUsage(os.Stderr)
+		// Return usage status
+		return exitUsage
+	}
+	// Return command help status
+	return cmd.run(append(args[1:], "-help"))
+}
+
+// newFlagSet creates the flag set of a command with its help text.
+// Flag errors and the help following them are written to stderr; help asked
+// for with -help is written to stdout by parseFlags.
+//
+// Params:
+//   - name: command name, possibly nested ("config init")
+//
+// Returns:
+//   - *flag.FlagSet: empty flag set
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() {
+		w := flags.Output()
+		cmd, _ := lookupCommand(name)
+		fmt.Fprintf(w, "Usage: status-line %s %s\n", name, cmd.args)
+		// Describe known commands
+		if cmd.summary != "" {
+			fmt.Fprintf(w, "\n%s\n", cmd.summary)
+		}
+		// List nested commands
+		if subs := subcommands(name); len(subs) > 0 {
+			fmt.Fprintln(w, "\nCommands:")
+			// Describe each nested command
+			for _, sub := range subs {
+				fmt.Fprintf(w, "  %-9s %s\n", strings.TrimPrefix(sub.name, name+" "), sub.summary)
+			}
+		}
+		// List flags when the command has some
+		if hasFlags(flags) {
+			fmt.Fprintln(w, "\nFlags:")
+			flags.PrintDefaults()
+		}
+	}
+	// Return configured flag set
+	return flags
+}
+
+// hasFlags reports whether a flag set defines any flag.
+//
+// Params:
+//   - flags: flag set to inspect
+//
+// Returns:
+//   - bool: true when at least one flag is defined
+func hasFlags(flags *flag.FlagSet) bool {
+	found := false
+	flags.VisitAll(func(*flag.Flag) { found = true })
+	// Return result
+	return found
+}
+
+// parseFlags parses command arguments and maps failures to exit codes.
+// The help is printed once parsing tells whether it was asked for: on
+// stdout for -help, on stderr after a flag error.
+//
+// Params:
+//   - flags: command flag set
+//   - args: command arguments
+//
+// Returns:
+//   - bool: true when the command should run
+//   - int: exit code to return when it should not
+func parseFlags(flags *flag.FlagSet, args []string) (bool, int) {
+	usage := flags.Usage
+	// Hold the help back until the destination is known
+	flags.Usage = func() {}
+	err := flags.Parse(args)
+	flags.Usage = usage
+	// Help was requested
+	if errors.Is(err, flag.ErrHelp) {
+		flags.SetOutput(os.Stdout)
+		flags.Usage()
+		// Return success
+		return false, exitOK
+	}
+	// Invalid flags were reported by the flag set
+	if err != nil {
+		flags.Usage()
+		// Return usage status
+		return false, exitUsage
+	}
+	// Run the command
+	return true, exitOK
+}
+
+// rejectArgs reports unexpected positional arguments.
+//
+// Params:
+//   - flags: parsed command flag set
+//
+// Returns:
+//   - bool: true when there are none
+func rejectArgs(flags *flag.FlagSet) bool {
+	// Accept commands without positional arguments
+	if flags.NArg() == 0 {
+		// Return accepted
+		return true
+	}
+	fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", flags.Arg(0))
+	flags.Usage()
+	// Return rejected
+	return false
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureOutput runs fn with stdout and stderr redirected to pipes.
+func captureOutput(t *testing.T, fn func()) (string, string) {
+	t.Helper()
+	stdout, stderr := os.Stdout, os.Stderr
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout, os.Stderr = outW, errW
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	outC, errC := make(chan string), make(chan string)
+	go func() { data, _ := io.ReadAll(outR); outC <- string(data) }()
+	go func() { data, _ := io.ReadAll(errR); errC <- string(data) }()
+	fn()
+	outW.Close()
+	errW.Close()
+	return <-outC, <-errC
+}
+
+func TestRun_Help(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{name: "help command", args: []string{"help"}, wantCode: exitOK, wantStdout: "Usage: status-line [command]"},
+		{name: "long help flag", args: []string{"--help"}, wantCode: exitOK, wantStdout: "Usage: status-line [command]"},
+		{name: "short help flag", args: []string{"-h"}, wantCode: exitOK, wantStdout: "Usage: status-line [command]"},
+		{name: "help topic", args: []string{"help", "doctor"}, wantCode: exitOK, wantStdout: "Usage: status-line doctor"},
+		{name: "nested help topic", args: []string{"help", "config", "init"}, wantCode: exitOK, wantStdout: "Usage: status-line config init"},
+		{name: "command help flag", args: []string{"preview", "-help"}, wantCode: exitOK, wantStdout: "Usage: status-line preview"},
+		{name: "group help flag", args: []string{"config", "--help"}, wantCode: exitOK, wantStdout: "Usage: status-line config"},
+		{name: "invalid flag", args: []string{"render", "-bogus"}, wantCode: exitUsage, wantStderr: "Usage: status-line render"},
+		{name: "unknown topic", args: []string{"help", "bogus"}, wantCode: exitUsage, wantStderr: "Usage: status-line [command]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var code int
+			stdout, stderr := captureOutput(t, func() { code = run(tt.args) })
+			if code != tt.wantCode {
+				t.Errorf("run(%q) = %d, want %d", tt.args, code, tt.wantCode)
+			}
+			if !strings.Contains(stdout, tt.wantStdout) || (tt.wantStdout != "" && stderr != "") {
+				t.Errorf("run(%q) stdout = %q, stderr = %q, want help on stdout", tt.args, stdout, stderr)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) || (tt.wantStderr != "" && stdout != "") {
+				t.Errorf("run(%q) stdout = %q, stderr = %q, want help on stderr", tt.args, stdout, stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Package main provides the entry point for the status-line CLI tool.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Config subcommand names.
+const (
+	// commandConfigInit writes the default config file.
+	commandConfigInit string = commandConfig + " init"
+	// commandConfigShow prints the effective configuration.
+	commandConfigShow string = commandConfig + " show"
+	// commandConfigValidate checks the config file.
+	commandConfigValidate string = commandConfig + " validate"
+)
+
+// configCommands returns the subcommands of config.
+//
+// Returns:
+//   - []command: config subcommands
+func configCommands() []command {
+	// Return command table
+	return []command{
+		{name: commandConfigInit, args: "[flags]", summary: "Write the default configuration to the config file", run: runConfigInit},
+		{name: commandConfigShow, args: "", summary: "Print the effective configuration, after environment overrides, as JSON", run: runConfigShow},
+		{name: commandConfigValidate, args: "", summary: "Check the config file and report every problem", run: runConfigValidate},
+	}
+}
+
+// runConfig dispatches to a config subcommand.
+//
+// Params:
+//   - args: subcommand and its arguments
+//
+// Returns:
+//   - int: process exit code
+func runConfig(args []string) int {
+	// Return subcommand status
+	return runGroup(commandConfig, args)
+}
+
+// runConfigInit writes the default configuration file.
+//
+// Params:
+//   - args: command arguments
+//
+// Returns:
+//   - int: process exit code
+func runConfigInit(args []string) int {
+	flags := newFlagSet(commandConfigInit)
+	force := flags.Bool("force", false, "overwrite an existing config file")
+	// Stop on help or invalid flags
+	if ok, code := parseFlags(flags, args); !ok {
+		// Return parse status
+		return code
+	}
+	// Stop on positional arguments
+	if !rejectArgs(flags) {
+		// Return usage status
+		return exitUsage
+	}
+
+	loader := newConfigLoader()
+	err := loader.Init(*force)
+	// Explain how to replace an existing file
+	if errors.Is(err, fs.ErrExist) {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists, use --force to overwrite it\n", loader.Path())
+		// Return failure status
+		return exitFailure
+	}
+	// Report write errors
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		// Return failure status
+		return exitFailure
+	}
+	fmt.Println("Wrote", loader.Path())
+	// Return success
+	return exitOK
+}
+
+// runConfigShow prints the effective configuration as JSON.
+//
+// Params:
+//   - args: command arguments
+//
+// Returns:
+//   - int: process exit code
+func runConfigShow(args []string) int {
+	flags := newFlagSet(commandConfigShow)
+	// Stop on help or invalid flags
+	if ok, code := parseFlags(flags, args); !ok {
+		// Return parse status
+		return code
+	}
+	// Stop on positional arguments
+	if !rejectArgs(flags) {
+		// Return usage status
+		return exitUsage
+	}
+
+	cfg, err := newConfigLoader().Load()
+	// Do not pass defaults off as the configuration
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		// Return failure status
+		return exitFailure
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	// Check for encoding errors
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		// Return failure status
+		return exitFailure
+	}
+	fmt.Println(string(data))
+	// Return success
+	return exitOK
+}
+
+// runConfigValidate checks the config file, its theme and environment overrides.
+//
+// Params:
+//   - args: command arguments
+//
+// Returns:
+//   - int: process exit code
+func runConfigValidate(args []string) int {
+	flags := newFlagSet(commandConfigValidate)
+	// Stop on help or invalid flags
+	if ok, code := parseFlags(flags, args); !ok {
+		// Return parse status
+		return code
+	}
+	// Stop on positional arguments
+	if !rejectArgs(flags) {
+		// Return usage status
+		return exitUsage
+	}
+
+	loader := newConfigLoader()
+	// Report every problem found
+	if _, err := loader.Load(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		// Return failure status
+		return exitFailure
+	}
+	// Mention when defaults are in use
+	if _, err := os.Stat(loader.Path()); err != nil {
+		fmt.Printf("No config file at %s, defaults are valid\n", loader.Path())
+		// Return success
+		return exitOK
+	}
+	fmt.Printf("%s is valid\n", loader.Path())
+	// Return success
+	return exitOK
+}
@@ -0,0 +1,323 @@
+// Package main provides the entry point for the status-line CLI tool.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/florent/status-line/internal/adapter/git"
+	"github.com/florent/status-line/internal/adapter/history"
+	"github.com/florent/status-line/internal/adapter/mcp"
+	"github.com/florent/status-line/internal/adapter/system"
+	"github.com/florent/status-line/internal/adapter/taskwarrior"
+	"github.com/florent/status-line/internal/adapter/terminal"
+	"github.com/florent/status-line/internal/adapter/transcript"
+	"github.com/florent/status-line/internal/adapter/updater"
+	"github.com/florent/status-line/internal/adapter/usage"
+	"github.com/florent/status-line/internal/domain/model"
+	"github.com/florent/status-line/internal/presentation/renderer"
+)
+
+// Doctor step outcomes.
+const (
+	// outcomeOK marks a step that succeeded.
+	outcomeOK string = "ok"
+	// outcomeMissing marks a file or command that does not exist.
+	outcomeMissing string = "missing"
+	// outcomeFailed marks a step that failed.
+	outcomeFailed string = "failed"
+	// durationPrecision rounds reported durations.
+	durationPrecision time.Duration = 100 * time.Microsecond
+)
+
+// probe is a provider checked by the doctor command.
+// A zero timeout means the status line does not wait for the provider.
+// The note tells what running the provider may write.
+type probe struct {
+	name     string
+	segments []string
+	timeout  time.Duration
+	note     string
+	run      func(t *model.Trace) (string, error)
+}
+
+// runDoctor reports the configuration, terminal and every data provider.
+// Each provider runs with a trace, so the files, commands and requests it
+// tried are listed with their errors, how long it took and what it would show.
+//
+// Params:
+//   - args: command arguments
+//
+// Returns:
+//   - int: process exit code, exitFailure when the config is invalid
+func runDoctor(args []string) int {
+	flags := newFlagSet(commandDoctor)
+	// Stop on help or invalid flags
+	if ok, code := parseFlags(flags, args); !ok {
+		// Return parse status
+		return code
+	}
+	// Stop on positional arguments
+	if !rejectArgs(flags) {
+		// Return usage status
+		return exitUsage
+	}
+
+	code := exitOK
+	fmt.Println("status-line", versionString())
+
+	loader := newConfigLoader()
+	cfg, err := loader.Load()
+	fmt.Println("config:  ", loader.Path())
+	// Report config problems
+	if err != nil {
+		fmt.Println("  error: ", err)
+		code = exitFailure
+	}
+
+	term := terminal.NewProvider().Info()
+	fmt.Printf("terminal: width %d, color depth %s (using %s)\n", term.Width, term.ColorDepth, cfg.EffectiveColorDepth(term.ColorDepth))
+
+	// Check each provider
+	for _, p := range probes(cfg) {
+		fmt.Println()
+		reportProbe(cfg, p)
+	}
+	// Return overall status
+	return code
+}
+
+// probes returns the providers checked by the doctor command.
+//
+// Params:
+//   - cfg: user configuration
+//
+// Returns:
+//   - []probe: providers in layout order
+func probes(cfg model.Config) []probe {
+	dir, _ := os.Getwd()
+	ctx := context.Background()
+	// Return provider checks
+	return []probe{
+		{name: model.ProviderGit, segments: []string{model.SegmentGit, model.SegmentChanges}, timeout: cfg.Timeouts.Git.Std(), run: func(t *model.Trace) (string, error) {
+			repo := git.NewRepository()
+			repo.SetTrace(t)
+			data := model.StatusLineData{Git: repo.Status(ctx)}
+			// Query the configured changes source; session counts come from Claude Code only
+			switch {
+			// Changes since the base branch
+			case cfg.Changes.Source == model.ChangesSourceBranch:
+				data.Changes = repo.BranchDiffStats(ctx, cfg.Changes.BaseBranch)
+			// Uncommitted changes
+			case cfg.Changes.ShowsGit():
+				data.Changes = repo.DiffStats(ctx)
+			}
+			// Return rendered segments
+			return previewSegments(cfg, data, model.SegmentGit, model.SegmentChanges), nil
+		}},
+		{name: model.ProviderUsage, segments: []string{model.SegmentModel, model.SegmentWeekly}, timeout: cfg.Usage.Timeout.Std(), note: "like the status line, refreshes the usage cache and renews an expired OAuth token, writing it back to its credential source", run: func(t *model.Trace) (string, error) {
+			p := usage.NewProvider(cfg.Usage)
+			p.SetTrace(t)
+			data, err := p.Usage(ctx)
+			// Let a background refresh finish recording its steps
+			p.Wait()
+			// Nothing to show without data
+			if err != nil {
+				// Return fetch error
+				return "", err
+			}
+			shows := fmt.Sprintf("session %d%%, weekly %d%%", data.Session.Utilization, data.Weekly.Utilization)
+			// List the other windows
+			for _, w := range data.Windows {
+				shows += fmt.Sprintf(", %s %d%%", w.Name, w.Usage.Utilization)
+			}
+			// Flag data served from an outdated cache
+			if data.Weekly.Stale {
+				shows += " (stale cache)"
+			}
+			// Report the last refresh failure served from the cache
+			if data.Status != "" {
+				shows += " (" + data.Status.Label() + ")"
+			}
+			// Return utilization summary
+			return shows, nil
+		}},
+		{name: model.ProviderHistory, segments: []string{model.SegmentSparkline}, timeout: cfg.Timeouts.Render.Std(), run: func(t *model.Trace) (string, error) {
+			store := history.NewStore(cfg.History)
+			store.SetTrace(t)
+			samples, err := store.History(ctx, time.Now().Add(-cfg.History.SparklineSpan.Std()))
+			// Nothing to show without a ledger
+			if err != nil {
+				// Return read error
+				return "", err
+			}
+			data := model.StatusLineData{History: samples}
+			// Return rendered segment
+			return previewSegments(cfg, data, model.SegmentSparkline), nil
+		}},
+		{name: model.ProviderTranscript, segments: []string{model.SegmentModel, model.SegmentActivity}, timeout: cfg.Timeouts.Transcript.Std(), run: func(t *model.Trace) (string, error) {
+			r := transcript.NewReader()
+			r.SetTrace(t)
+			// Claude Code sends no session here, use the one written last
+			session, err := r.LatestSession(dir)
+			// Nothing to show without a transcript
+			if err != nil {
+				// Return lookup error
+				return "", err
+			}
+			stats, err := r.Stats(ctx, session)
+			// Nothing to show without a summary
+			if err != nil {
+				// Return read error
+				return "", err
+			}
+			tokens := stats.Tokens
+			shows := fmt.Sprintf("session %s: %d tokens in context (input %d, output %d, cache read %d, cache write %d), %d compactions",
+				session.ID, tokens.ContextTokens(), tokens.Input, tokens.Output, tokens.CacheRead, tokens.CacheCreation, stats.Compactions)
+			// Add the segment showing the current tool call
+			if activity := previewSegments(cfg, model.StatusLineData{Transcript: stats}, model.SegmentActivity); activity != "" {
+				shows += ", " + activity
+			}
+			// Return token and compaction summary
+			return shows, nil
+		}},
+		{name: model.ProviderMCP, segments: []string{model.SegmentMCP}, timeout: cfg.Timeouts.MCP.Std(), run: func(t *model.Trace) (string, error) {
+			p := mcp.NewProvider(dir)
+			p.SetTrace(t)
+			data := model.StatusLineData{MCP: p.Servers(ctx)}
+			// Return rendered segment
+			return previewSegments(cfg, data, model.SegmentMCP), nil
+		}},
+		{name: model.ProviderTaskwarrior, segments: []string{model.SegmentTasks}, timeout: cfg.Timeouts.Taskwarrior.Std(), run: func(t *model.Trace) (string, error) {
+			p := taskwarrior.NewProvider(cfg.Taskwarrior)
+			p.SetTrace(t)
+			data := model.StatusLineData{Taskwarrior: p.Info(ctx)}
+			// Return rendered segment
+			return previewSegments(cfg, data, model.SegmentTasks), nil
+		}},
+		{name: "updater", segments: []string{model.SegmentUpdate}, run: func(t *model.Trace) (string, error) {
+			u := updater.NewUpdater(version, cfg.Update)
+			u.SetTrace(t)
+			// Look up without recording the check, which would delay the status line's own
+			info, err := u.Lookup()
+			// Nothing to show without a version
+			if err != nil {
+				// Return lookup error
+				return "", err
+			}
+			data := model.StatusLineData{Update: model.UpdateInfo{Available: info.Available, Version: info.Version}}
+			// Return rendered segment
+			return previewSegments(cfg, data, model.SegmentUpdate), nil
+		}},
+	}
+}
+
+// reportProbe runs a provider and prints what it tried and what it would show.
+//
+// Params:
+//   - cfg: user configuration
+//   - p: provider check
+func reportProbe(cfg model.Config, p probe) {
+	var trace model.Trace
+	start := time.Now()
+	shows, err := p.run(&trace)
+	elapsed := time.Since(start).Round(durationPrecision)
+
+	fmt.Printf("%s (%s)\n", p.name, elapsed)
+	// Warn when the status line would stop waiting before the provider is done
+	if limit := min(p.timeout, cfg.Timeouts.Render.Std()); p.timeout > 0 && elapsed > limit {
+		fmt.Printf("  note     slower than its %s deadline, the status line shows it empty\n", limit)
+	}
+	// List segments missing from the layout
+	for _, id := range p.segments {
+		// Check layout
+		if !cfg.SegmentEnabled(id) {
+			fmt.Printf("  note     segment %q is not in the layout and is never collected\n", id)
+		}
+	}
+	// Warn about what the provider may write
+	if p.note != "" {
+		fmt.Printf("  note     %s\n", p.note)
+	}
+	seen := make(map[model.TraceStep]bool, len(trace.Steps))
+	// List every distinct attempt
+	for _, step := range trace.Steps {
+		key := model.TraceStep{Kind: step.Kind, Target: step.Target}
+		// Skip files read again by another lookup
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		fmt.Printf("  %-8s %-8s %s\n", stepOutcome(step.Err), step.Kind, step.Target)
+		// Detail failures
+		if step.Err != nil && !isMissing(step.Err) {
+			fmt.Printf("  %-8s %-8s %s\n", "", "", step.Err)
+		}
+	}
+	// Report the provider error
+	if err != nil {
+		fmt.Printf("  error    %s\n", err)
+	}
+	// Report what the segments would show
+	if shows == "" {
+		shows = "(nothing)"
+	}
+	fmt.Printf("  shows    %s\n", shows)
+}
+
+// stepOutcome classifies a trace step error.
+//
+// Params:
+//   - err: step error
+//
+// Returns:
+//   - string: outcomeOK, outcomeMissing or outcomeFailed
+func stepOutcome(err error) string {
+	// Successful step
+	if err == nil {
+		// Return success
+		return outcomeOK
+	}
+	// Absent file or binary
+	if isMissing(err) {
+		// Return missing
+		return outcomeMissing
+	}
+	// Return failure
+	return outcomeFailed
+}
+
+// isMissing reports whether err means a file or binary does not exist.
+//
+// Params:
+//   - err: step error
+//
+// Returns:
+//   - bool: true for missing files and binaries not on PATH
+func isMissing(err error) bool {
+	// Return whether either sentinel matches
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, exec.ErrNotFound)
+}
+
+// previewSegments renders segments alone, as plain text, from provider data.
+//
+// Params:
+//   - cfg: user configuration
+//   - data: data collected by the provider
+//   - ids: segments to render
+//
+// Returns:
+//   - string: rendered segments, empty when they are hidden
+func previewSegments(cfg model.Config, data model.StatusLineData, ids ...string) string {
+	cfg.Layout = model.Layout{ids}
+	data.Icons = cfg.Icons
+	data.System = system.NewProvider().Info()
+	// Return rendered line without surrounding space
+	return strings.TrimSpace(renderer.NewPlain(cfg).Render(data))
+}
@@ -2,140 +2,134 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
 	"os"
+	"strings"
 
-	"github.com/florent/status-line/internal/adapter/git"
-	"github.com/florent/status-line/internal/adapter/mcp"
-	"github.com/florent/status-line/internal/adapter/system"
-	"github.com/florent/status-line/internal/adapter/taskwarrior"
-	"github.com/florent/status-line/internal/adapter/terminal"
-	"github.com/florent/status-line/internal/adapter/updater"
-	"github.com/florent/status-line/internal/adapter/usage"
-	"github.com/florent/status-line/internal/application"
-	"github.com/florent/status-line/internal/domain/model"
+	"github.com/florent/status-line/internal/adapter/config"
 	"github.com/florent/status-line/internal/presentation/renderer"
 )
 
+// Process exit codes.
+const (
+	// exitOK reports success.
+	exitOK int = 0
+	// exitFailure reports a command that ran and failed.
+	exitFailure int = 1
+	// exitUsage reports invalid arguments.
+	exitUsage int = 2
+)
+
 // version is set at build time via ldflags.
 // Empty value means development build (no auto-update).
 var version string
 
 // main is the entry point of the application.
-// It reads JSON input from stdin, builds the service, and outputs the status line.
+// It dispatches to a subcommand; without one it renders the status line
+// from stdin, so the binary keeps working as a Claude Code status line filter.
 //
 // Returns:
-//   - void: exits with code 1 on error
+//   - void: exits with exitOK, exitFailure or exitUsage
 func main() {
-	// Handle version flag before anything else
-	if len(os.Args) > 1 {
-		arg := os.Args[1]
-		if arg == "-v" || arg == "--version" {
-			printVersion()
-			return
-		}
-	}
+	// Exit with the command status
+	os.Exit(run(os.Args[1:]))
+}
 
-	input, err := readInput()
-	// Check for input reading errors
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+// run dispatches the command line to a subcommand.
+//
+// Params:
+//   - args: command line arguments without the program name
+//
+// Returns:
+//   - int: process exit code
+func run(args []string) int {
+	// No arguments renders from stdin
+	if len(args) == 0 {
+		// Return render status
+		return runRender(args)
 	}
 
-	// Check for updates (returns info about available update)
-	updateInfo := checkForUpdate()
-
-	// Generate and output status line with update notification
-	svc := buildService(input.WorkingDir())
-	fmt.Print(svc.GenerateWithUpdate(input, updateInfo))
+	arg := args[0]
+	// Handle global flags and flags of the default command
+	if strings.HasPrefix(arg, "-") {
+		// Select by flag
+		switch arg {
+		// Version flags predate subcommands
+		case "-v", "--version":
+			// Return version status
+			return runVersion(args[1:])
+		// Global help
+		case "-h", "-help", "--help":
+			printUsage(os.Stdout)
+			// Return success
+			return exitOK
+		}
+		// Return render status with its flags
+		return runRender(args)
+	}
 
-	// Download update if available (after output is displayed)
-	downloadUpdate(updateInfo)
-}
+	// Help for the tool or one command
+	if arg == "help" {
+		// Return help status
+		return runHelp(args[1:])
+	}
 
-// checkForUpdate checks if an update is available.
-// Returns update info for display in status line.
-//
-// Returns:
-//   - model.UpdateInfo: information about available update
-func checkForUpdate() model.UpdateInfo {
-	u := updater.NewUpdater(version)
-	info := u.CheckForUpdate()
-	// Convert updater.UpdateInfo to model.UpdateInfo
-	return model.UpdateInfo{
-		Available: info.Available,
-		Version:   info.Version,
+	cmd, ok := lookupCommand(arg)
+	// Reject unknown commands
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n\n", arg)
+		printUsage(os.Stderr)
+		// Return usage status
+		return exitUsage
 	}
+	// Return command status
+	return cmd.run(args[1:])
 }
 
-// downloadUpdate downloads and applies the update if available.
-// Silently ignores errors to avoid disrupting normal operation.
+// runVersion prints the version information.
+// If version is empty (development build), it prints "dev".
 //
 // Params:
-//   - info: update information from checkForUpdate
-func downloadUpdate(info model.UpdateInfo) {
-	// Skip if no update available
-	if !info.Available {
-		// No update to download
-		return
+//   - args: command arguments
+//
+// Returns:
+//   - int: process exit code
+func runVersion(args []string) int {
+	flags := newFlagSet(commandVersion)
+	// Stop on help or invalid flags
+	if ok, code := parseFlags(flags, args); !ok {
+		// Return parse status
+		return code
 	}
-	u := updater.NewUpdater(version)
-	// Ignore errors - update is best-effort
-	_ = u.DownloadUpdate(info.Version)
-}
-
-// printVersion prints the version information and exits.
-// If version is empty (development build), it prints "dev".
-func printVersion() {
-	v := version
-	if v == "" {
-		v = "dev"
+	// Stop on positional arguments
+	if !rejectArgs(flags) {
+		// Return usage status
+		return exitUsage
 	}
-	fmt.Println("status-line", v)
+	fmt.Println("status-line", versionString())
+	// Return success
+	return exitOK
 }
 
-// readInput reads and parses JSON input from stdin.
+// versionString returns the build version, "dev" for development builds.
 //
 // Returns:
-//   - *model.Input: parsed input data
-//   - error: reading or parsing error if any
-func readInput() (*model.Input, error) {
-	data, err := io.ReadAll(os.Stdin)
-	// Check for stdin read errors
-	if err != nil {
-		// Return wrapped error for context
-		return nil, fmt.Errorf("reading stdin: %w", err)
-	}
-
-	var input model.Input
-	// Check for JSON parsing errors
-	if err := json.Unmarshal(data, &input); err != nil {
-		// Return wrapped error for context
-		return nil, fmt.Errorf("parsing JSON: %w", err)
+//   - string: version to display
+func versionString() string {
+	// Development builds have no version
+	if version == "" {
+		// Return placeholder
+		return "dev"
 	}
-	// Return successfully parsed input
-	return &input, nil
+	// Return build version
+	return version
 }
 
-// buildService creates and wires all dependencies for the status line service.
-//
-// Params:
-//   - projectDir: the project directory for MCP config lookup
+// newConfigLoader creates the loader for the default config location.
 //
 // Returns:
-//   - *application.StatusLineService: fully configured service instance
-func buildService(projectDir string) *application.StatusLineService {
-	deps := application.ServiceDeps{
-		Git:         git.NewRepository(),
-		System:      system.NewProvider(),
-		Terminal:    terminal.NewProvider(),
-		MCP:         mcp.NewProvider(projectDir),
-		Taskwarrior: taskwarrior.NewProvider(),
-		Usage:       usage.NewProvider(),
-	}
-	// Return service with all adapters injected
-	return application.NewStatusLineService(deps, renderer.NewPowerline())
+//   - *config.Loader: loader validating against the built-in segments
+func newConfigLoader() *config.Loader {
+	// Return loader for the default path
+	return config.NewLoader(renderer.DefaultRegistry().IDs())
 }
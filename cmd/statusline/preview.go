@@ -0,0 +1,144 @@
+// Package main provides the entry point for the status-line CLI tool.
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/florent/status-line/internal/adapter/system"
+	"github.com/florent/status-line/internal/adapter/terminal"
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Sample values shown by the preview command.
+const (
+	// previewTimeFormat matches the status line clock format.
+	previewTimeFormat string = "15:04:05"
+	// previewSessionReset is the time until the sample session window resets.
+	previewSessionReset time.Duration = 2 * time.Hour
+	// previewWeeklyReset is the time until the sample weekly window resets.
+	previewWeeklyReset time.Duration = 3 * 24 * time.Hour
+	// previewHistoryStep is the time between two sample history readings.
+	previewHistoryStep time.Duration = 30 * time.Minute
+	// previewSessionWindow is the length of the sample session windows.
+	previewSessionWindow time.Duration = 5 * time.Hour
+)
+
+// runPreview renders sample data with the current configuration.
+// Every segment has content, so themes, icons and layouts can be tried without Claude Code.
+//
+// Params:
+//   - args: command arguments
+//
+// Returns:
+//   - int: process exit code
+func runPreview(args []string) int {
+	flags := newFlagSet(commandPreview)
+	format := formatFlag(flags)
+	width := flags.Int("width", 0, "terminal width in columns (default: detected)")
+	// Stop on help or invalid flags
+	if ok, code := parseFlags(flags, args); !ok {
+		// Return parse status
+		return code
+	}
+	// Stop on positional arguments
+	if !rejectArgs(flags) {
+		// Return usage status
+		return exitUsage
+	}
+
+	cfg, err := newConfigLoader().Load()
+	// Preview the defaults, but say why
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
+	out, ok := newRenderer(cfg, *format)
+	// Reject unknown formats
+	if !ok {
+		// Return usage status
+		return exitUsage
+	}
+
+	data := sampleData(cfg)
+	// Override the detected width when asked to
+	if *width > 0 {
+		data.Terminal.Width = *width
+	}
+	fmt.Print(out.Render(data))
+	// Return success
+	return exitOK
+}
+
+// sampleData returns status line data with every segment populated.
+// System and terminal information are detected so the preview matches this machine.
+//
+// Params:
+//   - cfg: user configuration
+//
+// Returns:
+//   - model.StatusLineData: sample data
+func sampleData(cfg model.Config) model.StatusLineData {
+	now := time.Now()
+	session := model.NewSessionUsage(42, now.Add(previewSessionReset))
+	data := model.StatusLineData{
+		Model:    model.ModelInfo{ID: "claude-opus-4-5", Name: "Opus", Version: "4.5"},
+		Progress: session.Progress(),
+		Session:  session,
+		Usage:    model.NewWeeklyUsage(35, now.Add(previewWeeklyReset)),
+		UsageWindows: model.UsageWindows{
+			{Name: "seven_day_opus", Usage: model.NewWeeklyUsage(20, now.Add(previewWeeklyReset))},
+		},
+		Icons:    cfg.Icons,
+		Git:      model.GitStatus{Branch: "main", Modified: 2, Untracked: 1},
+		System:   system.NewProvider().Info(),
+		Terminal: terminal.NewProvider().Info(),
+		Dir:      "~/projects/status-line",
+		Time:     now.Format(previewTimeFormat),
+		Cost:     model.SessionCost{USD: 1.84, Duration: model.Duration(72 * time.Minute), APIDuration: model.Duration(18 * time.Minute)},
+		Transcript: model.TranscriptStats{
+			Activity: model.Activity{Tool: "Edit", Target: "internal/presentation/renderer/powerline.go", Kind: model.ActivityTargetPath, Agents: 2},
+		},
+		MCP: model.MCPServers{
+			{Name: "github", Enabled: true},
+			{Name: "slack", Enabled: false},
+		},
+		Taskwarrior: model.TaskwarriorInfo{
+			Installed: true,
+			Projects:  []model.TaskwarriorProject{{Name: "status-line", Pending: 3, Completed: 5}},
+		},
+		Update:  model.UpdateInfo{Available: true, Version: "v9.9.9"},
+		History: sampleHistory(now, cfg.History.SparklineSpan.Std()),
+	}
+	// Fill the changes sources the config shows
+	if cfg.Changes.ShowsGit() {
+		data.Changes = model.CodeChanges{Added: 120, Removed: 14}
+	}
+	// Add the counts reported by Claude Code
+	if cfg.Changes.ShowsSession() {
+		data.SessionChanges = model.CodeChanges{Added: 48, Removed: 6}
+	}
+	// Return populated data
+	return data
+}
+
+// sampleHistory returns session readings climbing through 5-hour windows.
+//
+// Params:
+//   - now: time of the last reading
+//   - span: period covered
+//
+// Returns:
+//   - model.UsageHistory: readings every previewHistoryStep, oldest first
+func sampleHistory(now time.Time, span time.Duration) model.UsageHistory {
+	var history model.UsageHistory
+	start := now.Add(-span)
+	// Add one reading per step
+	for at := start; !at.After(now); at = at.Add(previewHistoryStep) {
+		// Usage grows within each window and drops at the reset
+		elapsed := at.Sub(start) % previewSessionWindow
+		history = append(history, model.UsageSample{Time: at, Session: int(elapsed * 100 / previewSessionWindow), Weekly: 35})
+	}
+	// Return readings
+	return history
+}
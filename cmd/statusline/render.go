@@ -0,0 +1,209 @@
+// Package main provides the entry point for the status-line CLI tool.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/florent/status-line/internal/adapter/git"
+	"github.com/florent/status-line/internal/adapter/history"
+	"github.com/florent/status-line/internal/adapter/mcp"
+	"github.com/florent/status-line/internal/adapter/system"
+	"github.com/florent/status-line/internal/adapter/taskwarrior"
+	"github.com/florent/status-line/internal/adapter/terminal"
+	"github.com/florent/status-line/internal/adapter/transcript"
+	"github.com/florent/status-line/internal/adapter/updater"
+	"github.com/florent/status-line/internal/adapter/usage"
+	"github.com/florent/status-line/internal/application"
+	"github.com/florent/status-line/internal/domain/model"
+	"github.com/florent/status-line/internal/domain/port"
+	"github.com/florent/status-line/internal/presentation/renderer"
+)
+
+// runRender reads Claude Code JSON from stdin and outputs the status line.
+// Config errors are reported as warnings so the status line is never hidden.
+//
+// Params:
+//   - args: command arguments
+//
+// Returns:
+//   - int: process exit code
+func runRender(args []string) int {
+	flags := newFlagSet(commandRender)
+	format := formatFlag(flags)
+	// Stop on help or invalid flags
+	if ok, code := parseFlags(flags, args); !ok {
+		// Return parse status
+		return code
+	}
+	// Stop on positional arguments
+	if !rejectArgs(flags) {
+		// Return usage status
+		return exitUsage
+	}
+
+	// Load configuration (defaults are returned alongside any error)
+	cfg, err := newConfigLoader().Load()
+	// Report config errors without hiding the status line
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
+
+	out, ok := newRenderer(cfg, *format)
+	// Reject unknown formats before doing any work
+	if !ok {
+		// Return usage status
+		return exitUsage
+	}
+
+	input, err := readInput()
+	// Check for input reading errors
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		// Return failure status
+		return exitFailure
+	}
+
+	// Check for updates (returns info about available update)
+	updateInfo := checkForUpdate(cfg)
+
+	// Generate and output status line with update notification
+	usageProvider := usage.NewProvider(cfg.Usage)
+	svc := buildService(cfg, input.Session().ProjectDir, usageProvider, out)
+	fmt.Print(svc.GenerateWithUpdate(context.Background(), input, updateInfo))
+
+	// Let a usage refresh started by this render fill the cache (after output is displayed)
+	usageProvider.Wait()
+
+	// Download update if available (after output is displayed)
+	downloadUpdate(cfg, updateInfo)
+	// Return success
+	return exitOK
+}
+
+// formatFlag defines the --format flag on a command.
+//
+// Params:
+//   - flags: command flag set
+//
+// Returns:
+//   - *string: parsed format name
+func formatFlag(flags *flag.FlagSet) *string {
+	// Return flag value
+	return flags.String("format", renderer.FormatANSI, "output format: "+strings.Join(renderer.Formats(), ", "))
+}
+
+// newRenderer creates the renderer for a format, reporting unknown names.
+//
+// Params:
+//   - cfg: user configuration
+//   - format: output format name
+//
+// Returns:
+//   - port.Renderer: renderer for the format
+//   - bool: false when the format is unknown
+func newRenderer(cfg model.Config, format string) (port.Renderer, bool) {
+	out, ok := renderer.NewFormatRenderer(cfg, format)
+	// Report unknown formats
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q, want one of %s\n", format, strings.Join(renderer.Formats(), ", "))
+	}
+	// Return renderer
+	return out, ok
+}
+
+// checkForUpdate checks if an update is available.
+// Returns update info for display in status line.
+//
+// Params:
+//   - cfg: user configuration (update segment toggle and check interval)
+//
+// Returns:
+//   - model.UpdateInfo: information about available update
+func checkForUpdate(cfg model.Config) model.UpdateInfo {
+	// Skip update checks when the update segment is disabled
+	if !cfg.SegmentEnabled(model.SegmentUpdate) {
+		// No update info
+		return model.UpdateInfo{}
+	}
+	u := updater.NewUpdater(version, cfg.Update)
+	info := u.CheckForUpdate()
+	// Convert updater.UpdateInfo to model.UpdateInfo
+	return model.UpdateInfo{
+		Available: info.Available,
+		Version:   info.Version,
+	}
+}
+
+// downloadUpdate downloads and applies the update if available.
+// Silently ignores errors to avoid disrupting normal operation.
+//
+// Params:
+//   - cfg: user configuration
+//   - info: update information from checkForUpdate
+func downloadUpdate(cfg model.Config, info model.UpdateInfo) {
+	// Skip if no update available
+	if !info.Available {
+		// No update to download
+		return
+	}
+	u := updater.NewUpdater(version, cfg.Update)
+	// Ignore errors - update is best-effort
+	_ = u.DownloadUpdate(info.Version)
+}
+
+// readInput reads and parses JSON input from stdin.
+//
+// Returns:
+//   - *model.Input: parsed input data
+//   - error: reading or parsing error if any
+func readInput() (*model.Input, error) {
+	data, err := io.ReadAll(os.Stdin)
+	// Check for stdin read errors
+	if err != nil {
+		// Return wrapped error for context
+		return nil, fmt.Errorf("reading stdin: %w", err)
+	}
+
+	var input model.Input
+	// Check for JSON parsing errors
+	if err := json.Unmarshal(data, &input); err != nil {
+		// Return wrapped error for context
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+	// Return successfully parsed input
+	return &input, nil
+}
+
+// buildService creates and wires all dependencies for the status line service.
+//
+// Params:
+//   - cfg: user configuration
+//   - projectDir: the project directory for MCP config lookup
+//   - usageProvider: usage provider, kept by the caller to wait for its refresh
+//   - out: renderer for the selected output format
+//
+// Returns:
+//   - *application.StatusLineService: fully configured service instance
+func buildService(cfg model.Config, projectDir string, usageProvider port.UsageProvider, out port.Renderer) *application.StatusLineService {
+	deps := application.ServiceDeps{
+		Git:         git.NewRepository(),
+		System:      system.NewProvider(),
+		Terminal:    terminal.NewProvider(),
+		MCP:         mcp.NewProvider(projectDir),
+		Taskwarrior: taskwarrior.NewProvider(cfg.Taskwarrior),
+		Usage:       usageProvider,
+		Transcript:  transcript.NewReader(),
+	}
+	// Keep the usage history unless disabled
+	if cfg.History.Enabled {
+		deps.History = history.NewStore(cfg.History)
+	}
+	// Return service with all adapters injected
+	return application.NewStatusLineService(cfg, deps, out)
+}
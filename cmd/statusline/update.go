@@ -0,0 +1,67 @@
+// Package main provides the entry point for the status-line CLI tool.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/florent/status-line/internal/adapter/updater"
+)
+
+// runUpdate checks for the latest release and installs it.
+// Unlike the check done while rendering, it ignores the check interval and reports errors.
+//
+// Params:
+//   - args: command arguments
+//
+// Returns:
+//   - int: process exit code
+func runUpdate(args []string) int {
+	flags := newFlagSet(commandUpdate)
+	checkOnly := flags.Bool("check", false, "only report whether an update is available")
+	// Stop on help or invalid flags
+	if ok, code := parseFlags(flags, args); !ok {
+		// Return parse status
+		return code
+	}
+	// Stop on positional arguments
+	if !rejectArgs(flags) {
+		// Return usage status
+		return exitUsage
+	}
+
+	cfg, err := newConfigLoader().Load()
+	// Fall back to defaults, but say why
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
+	u := updater.NewUpdater(version, cfg.Update)
+	info, err := u.Latest()
+	// Report lookup errors
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		// Return failure status
+		return exitFailure
+	}
+	// Nothing to install
+	if !info.Available {
+		fmt.Printf("status-line %s is up to date\n", versionString())
+		// Return success
+		return exitOK
+	}
+	// Report without installing
+	if *checkOnly {
+		fmt.Printf("status-line %s is available (current: %s)\n", info.Version, versionString())
+		// Return success
+		return exitOK
+	}
+	// Install the release
+	if err := u.DownloadUpdate(info.Version); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		// Return failure status
+		return exitFailure
+	}
+	fmt.Printf("Updated status-line %s to %s\n", versionString(), info.Version)
+	// Return success
+	return exitOK
+}
@@ -0,0 +1,141 @@
+// Package main provides the entry point for the status-line CLI tool.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/florent/status-line/internal/adapter/history"
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Usage subcommand names.
+const (
+	// commandUsageHistory prints daily and weekly usage tables.
+	commandUsageHistory string = commandUsage + " history"
+)
+
+// Usage history defaults.
+const (
+	// defaultHistoryDays is the number of days listed by default.
+	defaultHistoryDays int = 14
+	// defaultHistoryWeeks is the number of weeks listed by default.
+	defaultHistoryWeeks int = 8
+	// historyDateFormat formats period start dates.
+	historyDateFormat string = "2006-01-02 Mon"
+)
+
+// usageCommands returns the subcommands of usage.
+//
+// Returns:
+//   - []command: usage subcommands
+func usageCommands() []command {
+	// Return command table
+	return []command{
+		{name: commandUsageHistory, args: "[flags]", summary: "Print peak daily and weekly usage from the history ledger", run: runUsageHistory},
+	}
+}
+
+// runUsage dispatches to a usage subcommand.
+//
+// Params:
+//   - args: subcommand and its arguments
+//
+// Returns:
+//   - int: process exit code
+func runUsage(args []string) int {
+	// Return subcommand status
+	return runGroup(commandUsage, args)
+}
+
+// runUsageHistory prints the peak session and weekly usage of recent days and weeks.
+//
+// Params:
+//   - args: command arguments
+//
+// Returns:
+//   - int: process exit code
+func runUsageHistory(args []string) int {
+	flags := newFlagSet(commandUsageHistory)
+	days := flags.Int("days", defaultHistoryDays, "number of days to list")
+	weeks := flags.Int("weeks", defaultHistoryWeeks, "number of weeks to list")
+	// Stop on help or invalid flags
+	if ok, code := parseFlags(flags, args); !ok {
+		// Return parse status
+		return code
+	}
+	// Stop on positional arguments
+	if !rejectArgs(flags) {
+		// Return usage status
+		return exitUsage
+	}
+	// Require at least one period of each kind
+	if *days < 1 || *weeks < 1 {
+		fmt.Fprintln(os.Stderr, "Error: --days and --weeks must be at least 1")
+		// Return usage status
+		return exitUsage
+	}
+
+	cfg, err := newConfigLoader().Load()
+	// Report config errors without hiding the history
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
+	store := history.NewStore(cfg.History)
+	now := time.Now()
+	dayStart := model.StartOfDay(now).AddDate(0, 0, 1-*days)
+	weekStart := model.StartOfWeek(now).AddDate(0, 0, 7*(1-*weeks))
+	since := dayStart
+	// Read back to the earliest period listed
+	if weekStart.Before(since) {
+		since = weekStart
+	}
+	samples, err := store.History(context.Background(), since)
+	// Nothing recorded yet is not a failure
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Println("No usage recorded yet in", store.Path())
+		// Mention why nothing gets recorded
+		if !cfg.History.Enabled {
+			fmt.Println("Recording is off: set history.enabled to true in the config file.")
+		}
+		// Return success
+		return exitOK
+	}
+	// Report read errors
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		// Return failure status
+		return exitFailure
+	}
+
+	fmt.Println("ledger:", store.Path())
+	fmt.Println()
+	printPeriods("Daily peak usage", "day", samples.Since(dayStart).Summarize(model.StartOfDay))
+	fmt.Println()
+	printPeriods("Weekly peak usage", "week of", samples.Since(weekStart).Summarize(model.StartOfWeek))
+	// Return success
+	return exitOK
+}
+
+// printPeriods prints a usage table, one period per row.
+//
+// Params:
+//   - title: table title
+//   - label: header of the period column
+//   - periods: periods to list, oldest first
+func printPeriods(title, label string, periods []model.UsagePeriod) {
+	fmt.Println(title)
+	fmt.Printf("  %-14s %7s %7s %8s\n", label, "session", "weekly", "samples")
+	// List each period
+	for _, p := range periods {
+		fmt.Printf("  %-14s %6d%% %6d%% %8d\n", p.Start.Format(historyDateFormat), p.Session, p.Weekly, p.Samples)
+	}
+	// Mark empty tables
+	if len(periods) == 0 {
+		fmt.Println("  (no samples)")
+	}
+}
@@ -0,0 +1,48 @@
+// Package atomicfile replaces files without ever exposing a partial write.
+// The usage cache, the credentials write-back, the history ledger and the
+// transcript read states are read by concurrent status line invocations, so
+// they share this helper.
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Write writes data to a temporary file next to path and renames it over
+// path, so readers never see a partial file.
+//
+// Params:
+//   - path: destination file
+//   - data: content to write
+//   - perm: permission of the file
+//
+// Returns:
+//   - error: write or rename error if any
+func Write(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	// Check for temp file errors
+	if err != nil {
+		// Return temp file error
+		return err
+	}
+	_, err = tmp.Write(data)
+	// Apply the permission before the file becomes visible
+	if err == nil {
+		err = tmp.Chmod(perm)
+	}
+	// Close before renaming, keeping the first error
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	// Replace the file only with a complete write
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	// Clean up after failures
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	// Return write result
+	return err
+}
@@ -0,0 +1,57 @@
+package atomicfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/florent/status-line/internal/adapter/atomicfile"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		data     string
+		perm     os.FileMode
+		wantErr  bool
+	}{
+		{name: "new file", data: "new", perm: 0o600},
+		{name: "replaces file", existing: "old", data: "new", perm: 0o644},
+		{name: "missing directory", data: "new", perm: 0o600, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "file.json")
+			// Write into a directory that does not exist
+			if tt.wantErr {
+				path = filepath.Join(dir, "missing", "file.json")
+			}
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := atomicfile.Write(path, []byte(tt.data), tt.perm)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Write() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := os.ReadFile(path)
+			if err != nil || string(got) != tt.data {
+				t.Errorf("file = %q (%v), want %q", got, err, tt.data)
+			}
+			info, err := os.Stat(path)
+			if err != nil || info.Mode().Perm() != tt.perm {
+				t.Errorf("file mode = %v (%v), want %v", info.Mode().Perm(), err, tt.perm)
+			}
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != 1 {
+				t.Errorf("directory holds %d entries, want only the file", len(entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+// Package config provides the configuration file adapter.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Config file location constants.
+const (
+	// envConfigPath overrides the config file location.
+	envConfigPath string = "STATUSLINE_CONFIG"
+	// envXDGConfigHome is the XDG base directory for user configuration.
+	envXDGConfigHome string = "XDG_CONFIG_HOME"
+	// defaultConfigHome is the fallback config directory under the home directory.
+	defaultConfigHome string = ".config"
+	// appDirName is the application directory inside the config home.
+	appDirName string = "status-line"
+	// configFileName is the config file name.
+	configFileName string = "config.json"
+	// themesDirName is the directory of user theme files next to the config file.
+	themesDirName string = "themes"
+	// themeFileExt is the extension of theme files.
+	themeFileExt string = ".json"
+	// configDirPerm is the permission of created config directories.
+	configDirPerm os.FileMode = 0755
+	// configFilePerm is the permission of created config files.
+	configFilePerm os.FileMode = 0644
+)
+
+// Loader reads the user configuration file.
+// A missing file is not an error: defaults are used instead.
+type Loader struct {
+	path     string
+	segments []string
+}
+
+// NewLoader creates a loader for the default config file location.
+// The location is $STATUSLINE_CONFIG, then $XDG_CONFIG_HOME/status-line/config.json,
+// then ~/.config/status-line/config.json.
+//
+// Params:
+//   - segments: identifiers of every registered segment, used to validate the layout
+//
+// Returns:
+//   - *Loader: loader for the resolved path
+func NewLoader(segments []string) *Loader {
+	// Return loader with resolved default path
+	return &Loader{path: defaultPath(), segments: segments}
+}
+
+// NewLoaderWithPath creates a loader for an explicit config file.
+//
+// Params:
+//   - path: config file path
+//   - segments: identifiers of every registered segment, used to validate the layout
+//
+// Returns:
+//   - *Loader: loader for the given path
+func NewLoaderWithPath(path string, segments []string) *Loader {
+	// Return loader with explicit path
+	return &Loader{path: path, segments: segments}
+}
+
+// Path returns the config file path this loader reads.
+//
+// Returns:
+//   - string: config file path (empty if it could not be resolved)
+func (l *Loader) Path() string {
+	// Return resolved path
+	return l.path
+}
+
+// Load reads the config file, applies environment overrides and validates the result.
+// Fields missing from the file keep their default values.
+//
+// Returns:
+//   - model.Config: loaded configuration (defaults with env overrides on error)
+//   - error: read, parse or validation error if any
+func (l *Loader) Load() (model.Config, error) {
+	cfg := model.DefaultConfig()
+
+	// Merge file values over defaults
+	if err := l.readFile(&cfg); err != nil {
+		// Return usable defaults alongside the error
+		return model.DefaultConfig().WithEnv(), err
+	}
+	cfg = cfg.WithEnv()
+
+	// Replace a user theme by its base theme and colors
+	if err := l.resolveTheme(&cfg); err != nil {
+		// Return usable defaults alongside the error
+		return model.DefaultConfig().WithEnv(), err
+	}
+
+	// Check resulting configuration
+	if err := cfg.Validate(l.segments); err != nil {
+		// Return usable defaults alongside the error
+		return model.DefaultConfig().WithEnv(), fmt.Errorf("invalid config %s:\n%w", l.displayPath(), err)
+	}
+	// Return validated configuration
+	return cfg, nil
+}
+
+// Init writes the default configuration to the config file.
+// Missing parent directories are created.
+//
+// Params:
+//   - force: overwrite an existing file
+//
+// Returns:
+//   - error: fs.ErrExist if the file exists and force is false, or a write error
+func (l *Loader) Init(force bool) error {
+	// A file location is required
+	if l.path == "" {
+		// Return resolution error
+		return errors.New("config path could not be resolved, set " + envConfigPath)
+	}
+	// Refuse to overwrite unless asked to
+	if _, err := os.Stat(l.path); err == nil && !force {
+		// Return existing file error
+		return fmt.Errorf("%s: %w", l.path, fs.ErrExist)
+	}
+
+	data, err := json.MarshalIndent(model.DefaultConfig(), "", "  ")
+	// Check for encoding errors
+	if err != nil {
+		// Return wrapped encoding error
+		return fmt.Errorf("encoding config: %w", err)
+	}
+	// Create the config directory
+	if err := os.MkdirAll(filepath.Dir(l.path), configDirPerm); err != nil {
+		// Return wrapped directory error
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	// Write the file with a trailing newline
+	if err := os.WriteFile(l.path, append(data, '\n'), configFilePerm); err != nil {
+		// Return wrapped write error
+		return fmt.Errorf("writing config: %w", err)
+	}
+	// Return success
+	return nil
+}
+
+// readFile decodes the config file into cfg.
+// Unknown keys are rejected so typos are reported instead of ignored.
+//
+// Params:
+//   - cfg: configuration to merge file values into
+//
+// Returns:
+//   - error: read or parse error, nil if the file does not exist
+func (l *Loader) readFile(cfg *model.Config) error {
+	// Skip when no path could be resolved
+	if l.path == "" {
+		// Nothing to read
+		return nil
+	}
+	data, err := os.ReadFile(l.path)
+	// Missing file means defaults
+	if errors.Is(err, fs.ErrNotExist) {
+		// Nothing to read
+		return nil
+	}
+	// Check for other read errors
+	if err != nil {
+		// Return wrapped read error
+		return fmt.Errorf("reading config: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	// Check for JSON syntax or type errors
+	if err := dec.Decode(cfg); err != nil {
+		// Return wrapped parse error
+		return fmt.Errorf("parsing config %s: %w", l.displayPath(), err)
+	}
+	// Return success
+	return nil
+}
+
+// resolveTheme loads a user theme file when the configured theme is not built in.
+// The theme name is looked up as themes/<name>.json next to the config file;
+// a value ending in .json is used as a path instead. The file's colors are
+// applied under the colors from the config file, and its base becomes the theme.
+// A missing theme file is left for validation to report.
+//
+// Params:
+//   - cfg: configuration to update
+//
+// Returns:
+//   - error: read or parse error of an existing theme file
+func (l *Loader) resolveTheme(cfg *model.Config) error {
+	// Built-in themes need no file
+	if slices.Contains(model.BuiltinThemes(), cfg.Theme) {
+		// Nothing to resolve
+		return nil
+	}
+	path := l.themePath(cfg.Theme)
+	// Skip when the path cannot be resolved
+	if path == "" {
+		// Nothing to read
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	// Missing file is reported as an unknown theme by validation
+	if errors.Is(err, fs.ErrNotExist) {
+		// Nothing to read
+		return nil
+	}
+	// Check for other read errors
+	if err != nil {
+		// Return wrapped read error
+		return fmt.Errorf("reading theme: %w", err)
+	}
+
+	var theme model.ThemeFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	// Check for JSON syntax or type errors
+	if err := dec.Decode(&theme); err != nil {
+		// Return wrapped parse error
+		return fmt.Errorf("parsing theme %s: %w", path, err)
+	}
+
+	colors := maps.Clone(theme.Colors)
+	// Ensure a map to merge into
+	if colors == nil {
+		colors = make(map[string]string, len(cfg.Colors))
+	}
+	// Config file colors take precedence over theme file colors
+	maps.Copy(colors, cfg.Colors)
+	cfg.Colors = colors
+	cfg.Theme = model.ThemeDefault
+	// Use the declared base theme when set
+	if theme.Base != "" {
+		cfg.Theme = theme.Base
+	}
+	// Return success
+	return nil
+}
+
+// themePath returns the file a user theme is read from.
+//
+// Params:
+//   - name: configured theme name or path
+//
+// Returns:
+//   - string: theme file path, empty if it cannot be resolved
+func (l *Loader) themePath(name string) string {
+	// Use explicit absolute paths as is
+	if strings.HasSuffix(name, themeFileExt) && filepath.IsAbs(name) {
+		// Return absolute path
+		return name
+	}
+	// Relative lookups need the config directory
+	if l.path == "" || name == "" {
+		// Return unresolved
+		return ""
+	}
+	dir := filepath.Dir(l.path)
+	// Resolve relative paths against the config directory
+	if strings.HasSuffix(name, themeFileExt) {
+		// Return relative path
+		return filepath.Join(dir, name)
+	}
+	// Return named theme in the themes directory
+	return filepath.Join(dir, themesDirName, name+themeFileExt)
+}
+
+// displayPath returns the path for error messages.
+//
+// Returns:
+//   - string: config path or placeholder
+func (l *Loader) displayPath() string {
+	// Use placeholder when path is empty
+	if l.path == "" {
+		// Return placeholder
+		return "(defaults)"
+	}
+	// Return configured path
+	return l.path
+}
+
+// defaultPath resolves the default config file location.
+//
+// Returns:
+//   - string: config file path, or empty if no home directory is known
+func defaultPath() string {
+	// Explicit override wins
+	if path := os.Getenv(envConfigPath); path != "" {
+		// Return explicit path
+		return path
+	}
+	// Use XDG config home when set
+	if dir := os.Getenv(envXDGConfigHome); dir != "" {
+		// Return XDG path
+		return filepath.Join(dir, appDirName, configFileName)
+	}
+	home, err := os.UserHomeDir()
+	// Check if home lookup failed
+	if err != nil {
+		// Return empty path when home is unknown
+		return ""
+	}
+	// Return ~/.config path
+	return filepath.Join(home, defaultConfigHome, appDirName, configFileName)
+}
@@ -0,0 +1,238 @@
+package config_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/florent/status-line/internal/adapter/config"
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestNewLoader(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{name: "creates loader"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := config.NewLoader(model.KnownSegments())
+			if l == nil {
+				t.Error("NewLoader() returned nil")
+			}
+		})
+	}
+}
+
+func TestLoader_Load(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		env         map[string]string
+		wantErr     bool
+		wantLayout  model.Layout
+		wantPathLen int
+	}{
+		{
+			name:        "missing file uses defaults",
+			content:     "",
+			wantLayout:  model.DefaultLayout(),
+			wantPathLen: 30,
+		},
+		{
+			name:        "file overrides defaults",
+			content:     `{"layout":[["model","path"]],"path":{"max_length":12}}`,
+			wantLayout:  model.Layout{{"model", "path"}},
+			wantPathLen: 12,
+		},
+		{
+			name:        "env overrides file",
+			content:     `{"layout":[["model","path"]],"path":{"max_length":12}}`,
+			env:         map[string]string{"STATUSLINE_LAYOUT": "git, os; mcp", "STATUSLINE_PATH_MAX_LENGTH": "40"},
+			wantLayout:  model.Layout{{"git", "os"}, {"mcp"}},
+			wantPathLen: 40,
+		},
+		{
+			name:        "unknown field is rejected",
+			content:     `{"layuot":[["model"]]}`,
+			wantErr:     true,
+			wantLayout:  model.DefaultLayout(),
+			wantPathLen: 30,
+		},
+		{
+			name:        "invalid value is rejected",
+			content:     `{"progress":{"style":"dots"}}`,
+			wantErr:     true,
+			wantLayout:  model.DefaultLayout(),
+			wantPathLen: 30,
+		},
+		{
+			name:        "malformed json is rejected",
+			content:     `{"layout":`,
+			wantErr:     true,
+			wantLayout:  model.DefaultLayout(),
+			wantPathLen: 30,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			// Write config file when content is provided
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+					t.Fatalf("writing config: %v", err)
+				}
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := config.NewLoaderWithPath(path, model.KnownSegments()).Load()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.EqualFunc(cfg.Layout, tt.wantLayout, slices.Equal[[]string]) {
+				t.Errorf("Load().Layout = %v, want %v", cfg.Layout, tt.wantLayout)
+			}
+			if cfg.Path.MaxLength != tt.wantPathLen {
+				t.Errorf("Load().Path.MaxLength = %d, want %d", cfg.Path.MaxLength, tt.wantPathLen)
+			}
+		})
+	}
+}
+
+func TestLoader_Load_Theme(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     string
+		themeFile  string
+		themeBody  string
+		wantErr    bool
+		wantTheme  string
+		wantColors map[string]string
+	}{
+		{
+			name:      "built-in theme",
+			config:    `{"theme":"nord"}`,
+			wantTheme: "nord",
+		},
+		{
+			name:       "named theme file",
+			config:     `{"theme":"ocean","colors":{"git.bg":"#000000"}}`,
+			themeFile:  "themes/ocean.json",
+			themeBody:  `{"base":"nord","colors":{"git.bg":"#88c0d0","path.bg":"33"}}`,
+			wantTheme:  "nord",
+			wantColors: map[string]string{"git.bg": "#000000", "path.bg": "33"},
+		},
+		{
+			name:       "relative theme path without base",
+			config:     `{"theme":"mine.json"}`,
+			themeFile:  "mine.json",
+			themeBody:  `{"colors":{"cursor":"196"}}`,
+			wantTheme:  "default",
+			wantColors: map[string]string{"cursor": "196"},
+		},
+		{
+			name:      "missing theme file",
+			config:    `{"theme":"ocean"}`,
+			wantErr:   true,
+			wantTheme: "default",
+		},
+		{
+			name:      "invalid theme color",
+			config:    `{"theme":"ocean"}`,
+			themeFile: "themes/ocean.json",
+			themeBody: `{"colors":{"git.bg":"teal"}}`,
+			wantErr:   true,
+			wantTheme: "default",
+		},
+		{
+			name:      "unknown theme file field",
+			config:    `{"theme":"ocean"}`,
+			themeFile: "themes/ocean.json",
+			themeBody: `{"palette":{}}`,
+			wantErr:   true,
+			wantTheme: "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "config.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+				t.Fatalf("writing config: %v", err)
+			}
+			// Write theme file when provided
+			if tt.themeFile != "" {
+				themePath := filepath.Join(dir, tt.themeFile)
+				if err := os.MkdirAll(filepath.Dir(themePath), 0o700); err != nil {
+					t.Fatalf("creating theme dir: %v", err)
+				}
+				if err := os.WriteFile(themePath, []byte(tt.themeBody), 0o600); err != nil {
+					t.Fatalf("writing theme: %v", err)
+				}
+			}
+
+			cfg, err := config.NewLoaderWithPath(path, model.KnownSegments()).Load()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if cfg.Theme != tt.wantTheme {
+				t.Errorf("Load().Theme = %q, want %q", cfg.Theme, tt.wantTheme)
+			}
+			if len(cfg.Colors) != len(tt.wantColors) {
+				t.Errorf("Load().Colors = %v, want %v", cfg.Colors, tt.wantColors)
+			}
+			for role, want := range tt.wantColors {
+				if got := cfg.Colors[role]; got != want {
+					t.Errorf("Load().Colors[%q] = %q, want %q", role, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoader_Init(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		force    bool
+		wantErr  error
+	}{
+		{name: "creates file and directories"},
+		{name: "keeps existing file", existing: `{"theme":"nord"}`, wantErr: fs.ErrExist},
+		{name: "overwrites with force", existing: `{"theme":"nord"}`, force: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "status-line", "config.json")
+			if tt.existing != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			l := config.NewLoaderWithPath(path, model.KnownSegments())
+			err := l.Init(tt.force)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Init() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			cfg, err := l.Load()
+			if err != nil {
+				t.Fatalf("Load() after Init() error = %v", err)
+			}
+			if cfg.Theme != model.DefaultConfig().Theme || !slices.EqualFunc(cfg.Layout, model.DefaultLayout(), slices.Equal) {
+				t.Errorf("Load() after Init() = %+v, want defaults", cfg)
+			}
+		})
+	}
+}
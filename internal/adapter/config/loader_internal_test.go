@@ -0,0 +1,50 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		explicit string
+		xdg      string
+		want     string
+	}{
+		{name: "explicit override", explicit: "/etc/sl.json", xdg: "/xdg", want: "/etc/sl.json"},
+		{name: "xdg config home", xdg: "/xdg", want: filepath.Join("/xdg", "status-line", "config.json")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envConfigPath, tt.explicit)
+			t.Setenv(envXDGConfigHome, tt.xdg)
+			if got := defaultPath(); got != tt.want {
+				t.Errorf("defaultPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoader_themePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		configPath string
+		theme      string
+		want       string
+	}{
+		{name: "named theme", configPath: "/cfg/config.json", theme: "ocean", want: filepath.Join("/cfg", "themes", "ocean.json")},
+		{name: "relative path", configPath: "/cfg/config.json", theme: "mine.json", want: filepath.Join("/cfg", "mine.json")},
+		{name: "absolute path", configPath: "/cfg/config.json", theme: "/themes/x.json", want: "/themes/x.json"},
+		{name: "absolute path without config", configPath: "", theme: "/themes/x.json", want: "/themes/x.json"},
+		{name: "named theme without config", configPath: "", theme: "ocean", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &Loader{path: tt.configPath}
+			if got := l.themePath(tt.theme); got != tt.want {
+				t.Errorf("themePath(%q) = %q, want %q", tt.theme, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@
 package git
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os/exec"
 	"strings"
 
@@ -18,37 +21,77 @@ const (
 	minNumstatParts int = 2
 	// base10 is the decimal base for parsing digits.
 	base10 int = 10
+	// originHead is the remote reference naming the default branch.
+	originHead string = "refs/remotes/origin/HEAD"
 )
 
+// fallbackBaseBranches are tried in order when origin/HEAD is not set.
+var fallbackBaseBranches []string = []string{"main", "master"}
+
 // Compile-time interface implementation check.
 var _ port.GitRepository = (*Repository)(nil)
 
 // Repository implements port.GitRepository using git CLI commands.
 // It retrieves git status information by executing shell commands.
-type Repository struct{}
+type Repository struct {
+	trace *model.Trace
+}
 
 // NewRepository creates a new git repository adapter.
 //
 // Returns:
 //   - *Repository: new repository instance
 func NewRepository() *Repository {
-	// Return empty struct as no state is needed
+	// Return repository without tracing
 	return &Repository{}
 }
 
+// SetTrace records every git command run by the repository in t.
+//
+// Params:
+//   - t: trace to record into, nil to stop tracing
+func (r *Repository) SetTrace(t *model.Trace) {
+	r.trace = t
+}
+
+// git runs a git command and records it in the trace.
+//
+// Params:
+//   - ctx: kills the command when the deadline passes
+//   - args: git arguments
+//
+// Returns:
+//   - []byte: standard output
+//   - error: command error if any
+func (r *Repository) git(ctx context.Context, args ...string) ([]byte, error) {
+	output, err := exec.CommandContext(ctx, "git", args...).Output()
+	var exitErr *exec.ExitError
+	// Keep the first line of git's explanation, such as "not a git repository"
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		reason, _, _ := strings.Cut(strings.TrimSpace(string(exitErr.Stderr)), "\n")
+		err = fmt.Errorf("%w: %s", err, reason)
+	}
+	r.trace.Record(model.TraceCommand, "git "+strings.Join(args, " "), err)
+	// Return command result
+	return output, err
+}
+
 // Status retrieves the current git status.
 //
+// Params:
+//   - ctx: kills the git commands when the deadline passes
+//
 // Returns:
 //   - model.GitStatus: branch and change information
-func (r *Repository) Status() model.GitStatus {
-	branch, err := r.getBranch()
+func (r *Repository) Status(ctx context.Context) model.GitStatus {
+	branch, err := r.getBranch(ctx)
 	// Check if we're in a git repository
 	if err != nil {
 		// Return empty status if not in repo
 		return model.GitStatus{}
 	}
 
-	modified, untracked := r.getChangeCounts()
+	modified, untracked := r.getChangeCounts(ctx)
 	// Return populated status
 	return model.GitStatus{
 		Branch:    branch,
@@ -59,12 +102,14 @@ func (r *Repository) Status() model.GitStatus {
 
 // getBranch retrieves the current branch name.
 //
+// Params:
+//   - ctx: kills the command when the deadline passes
+//
 // Returns:
 //   - string: branch name
 //   - error: error if not in a git repository
-func (r *Repository) getBranch() (string, error) {
-	cmd := exec.Command("git", "branch", "--show-current")
-	output, err := cmd.Output()
+func (r *Repository) getBranch(ctx context.Context) (string, error) {
+	output, err := r.git(ctx, "branch", "--show-current")
 	// Check for git command errors
 	if err != nil {
 		// Return error if command failed
@@ -76,12 +121,14 @@ func (r *Repository) getBranch() (string, error) {
 
 // getChangeCounts counts modified and untracked files.
 //
+// Params:
+//   - ctx: kills the command when the deadline passes
+//
 // Returns:
 //   - modified: count of modified files
 //   - untracked: count of untracked files
-func (r *Repository) getChangeCounts() (modified, untracked int) {
-	cmd := exec.Command("git", "status", "--porcelain")
-	output, err := cmd.Output()
+func (r *Repository) getChangeCounts(ctx context.Context) (modified, untracked int) {
+	output, err := r.git(ctx, "status", "--porcelain")
 	// Check for git command errors
 	if err != nil {
 		// Return zero counts if command failed
@@ -107,14 +154,84 @@ func (r *Repository) getChangeCounts() (modified, untracked int) {
 	return modified, untracked
 }
 
-// DiffStats returns lines added and removed from git diff.
+// DiffStats returns lines added and removed in the working tree since HEAD.
+//
+// Params:
+//   - ctx: kills the command when the deadline passes
 //
 // Returns:
 //   - model.CodeChanges: lines added and removed
-func (r *Repository) DiffStats() model.CodeChanges {
+func (r *Repository) DiffStats(ctx context.Context) model.CodeChanges {
 	// Get diff stats for all changes (staged + unstaged)
-	cmd := exec.Command("git", "diff", "--numstat", "HEAD")
-	output, err := cmd.Output()
+	return r.numstat(ctx, "HEAD")
+}
+
+// BranchDiffStats returns lines added and removed since the branch left its
+// base: committed and uncommitted changes against the merge-base of HEAD and
+// the base branch.
+//
+// Params:
+//   - ctx: kills the commands when the deadline passes
+//   - base: base branch, empty to use the default branch
+//
+// Returns:
+//   - model.CodeChanges: lines added and removed, zero without a base
+func (r *Repository) BranchDiffStats(ctx context.Context, base string) model.CodeChanges {
+	// Detect the default branch when none is configured
+	if base == "" {
+		base = r.defaultBranch(ctx)
+	}
+	// Nothing to compare against
+	if base == "" {
+		// Return zero changes
+		return model.CodeChanges{}
+	}
+	output, err := r.git(ctx, "merge-base", "HEAD", base)
+	// Check for unrelated histories or unknown bases
+	if err != nil {
+		// Return zero changes
+		return model.CodeChanges{}
+	}
+	// Return diff stats against the fork point
+	return r.numstat(ctx, strings.TrimSpace(string(output)))
+}
+
+// defaultBranch finds the branch the repository integrates into.
+// The remote's HEAD wins; otherwise the first local fallback branch found.
+//
+// Params:
+//   - ctx: kills the commands when the deadline passes
+//
+// Returns:
+//   - string: branch reference, empty when none is found
+func (r *Repository) defaultBranch(ctx context.Context) string {
+	// Ask the remote which branch is its default
+	if output, err := r.git(ctx, "symbolic-ref", "--quiet", "--short", originHead); err == nil {
+		// Return remote default branch
+		return strings.TrimSpace(string(output))
+	}
+	// Try the usual local names
+	for _, name := range fallbackBaseBranches {
+		// Check that the branch exists
+		if _, err := r.git(ctx, "rev-parse", "--verify", "--quiet", "refs/heads/"+name); err == nil {
+			// Return local branch
+			return name
+		}
+	}
+	// Return no base
+	return ""
+}
+
+// numstat sums the lines added and removed between a revision and the working tree.
+//
+// Params:
+//   - ctx: kills the command when the deadline passes
+//   - rev: revision to compare against
+//
+// Returns:
+//   - model.CodeChanges: lines added and removed
+func (r *Repository) numstat(ctx context.Context, rev string) model.CodeChanges {
+	output, err := r.git(ctx, "diff", "--numstat", rev)
 	// Check for git command errors
 	if err != nil {
 		// Return zero if command failed
@@ -1,9 +1,13 @@
 package git_test
 
 import (
+	"context"
+	"os"
+	"os/exec"
 	"testing"
 
 	"github.com/florent/status-line/internal/adapter/git"
+	"github.com/florent/status-line/internal/domain/model"
 )
 
 func TestNewRepository(t *testing.T) {
@@ -31,7 +35,7 @@ func TestRepository_Status(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := git.NewRepository()
-			status := r.Status()
+			status := r.Status(context.Background())
 			_ = status.IsInRepo() // Just verify no panic
 		})
 	}
@@ -46,10 +50,76 @@ func TestRepository_DiffStats(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := git.NewRepository()
-			changes := r.DiffStats()
+			changes := r.DiffStats(context.Background())
 			if changes.Added < 0 || changes.Removed < 0 {
 				t.Errorf("DiffStats() = {%d, %d}, want non-negative", changes.Added, changes.Removed)
 			}
 		})
 	}
 }
+
+func TestRepository_SetTrace(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "records branch command", want: "git branch --show-current"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trace model.Trace
+			r := git.NewRepository()
+			r.SetTrace(&trace)
+			r.Status(context.Background())
+			if len(trace.Steps) == 0 || trace.Steps[0].Target != tt.want || trace.Steps[0].Kind != model.TraceCommand {
+				t.Errorf("Steps = %+v, want first command %q", trace.Steps, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepository_BranchDiffStats(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	tests := []struct {
+		name        string
+		base        string
+		wantAdded   int
+		wantRemoved int
+	}{
+		{name: "default branch", base: "", wantAdded: 3, wantRemoved: 1},
+		{name: "explicit base", base: "main", wantAdded: 3, wantRemoved: 1},
+		{name: "unknown base", base: "missing", wantAdded: 0, wantRemoved: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			run := func(args ...string) {
+				cmd := exec.Command("git", args...)
+				cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+				if out, err := cmd.CombinedOutput(); err != nil {
+					t.Fatalf("git %v: %v\n%s", args, err, out)
+				}
+			}
+			write := func(content string) {
+				if err := os.WriteFile("file.txt", []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			run("init", "--quiet", "--initial-branch=main")
+			write("a\nb\n")
+			run("add", ".")
+			run("commit", "--quiet", "-m", "base")
+			run("checkout", "--quiet", "-b", "feature")
+			write("a\nc\nd\n")
+			run("commit", "--quiet", "-am", "feature")
+			write("a\nc\nd\ne\n")
+
+			changes := git.NewRepository().BranchDiffStats(context.Background(), tt.base)
+			if changes.Added != tt.wantAdded || changes.Removed != tt.wantRemoved {
+				t.Errorf("BranchDiffStats(%q) = {%d, %d}, want {%d, %d}", tt.base, changes.Added, changes.Removed, tt.wantAdded, tt.wantRemoved)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"context"
 	"os"
 	"testing"
 )
@@ -57,7 +58,7 @@ func TestRepository_getBranch(t *testing.T) {
 			cleanup := tt.setupFunc()
 			defer cleanup()
 			r := &Repository{}
-			_, err := r.getBranch()
+			_, err := r.getBranch(context.Background())
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getBranch() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -74,7 +75,7 @@ func TestRepository_getChangeCounts(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := &Repository{}
-			modified, untracked := r.getChangeCounts()
+			modified, untracked := r.getChangeCounts(context.Background())
 			if modified < 0 || untracked < 0 {
 				t.Errorf("getChangeCounts() = (%d, %d), want non-negative", modified, untracked)
 			}
@@ -93,7 +94,7 @@ func TestRepository_getBranch_GitAvailable(t *testing.T) {
 			r := &Repository{}
 			// This tests that getBranch handles the error case gracefully
 			// when called outside a git repository or when git fails
-			_, _ = r.getBranch()
+			_, _ = r.getBranch(context.Background())
 		})
 	}
 }
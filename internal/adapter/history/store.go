@@ -0,0 +1,285 @@
+// Package history provides the usage history ledger adapter.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/florent/status-line/internal/adapter/atomicfile"
+	"github.com/florent/status-line/internal/domain/model"
+	"github.com/florent/status-line/internal/domain/port"
+)
+
+// Ledger file constants.
+const (
+	// envXDGDataHome is the XDG base directory for user data.
+	envXDGDataHome string = "XDG_DATA_HOME"
+	// envLocalAppData is the Windows directory for local application data.
+	envLocalAppData string = "LOCALAPPDATA"
+	// defaultDataHome is the fallback data directory under the home directory.
+	defaultDataHome string = ".local/share"
+	// appDirName is the application directory inside the data directory.
+	appDirName string = "status-line"
+	// ledgerFileName is the append-only sample file, one JSON object per line.
+	ledgerFileName string = "usage-history.jsonl"
+	// compactedFileName marks the last compaction by its modification time.
+	compactedFileName string = "usage-history.compacted"
+	// dataDirPerm is the permission of the created data directory.
+	dataDirPerm os.FileMode = 0700
+	// dataFilePerm is the permission of ledger files.
+	dataFilePerm os.FileMode = 0600
+	// compactEvery is the time between two compactions.
+	compactEvery time.Duration = 24 * time.Hour
+	// keepRecent is the age under which every sample is kept by compaction.
+	keepRecent time.Duration = 24 * time.Hour
+	// mergeBucket is the period older samples are merged into.
+	mergeBucket time.Duration = time.Hour
+)
+
+// Compile-time interface implementation check.
+var _ port.UsageHistory = (*Store)(nil)
+
+// errNoDataDir reports that no data directory could be resolved.
+var errNoDataDir error = errors.New("no data directory: set XDG_DATA_HOME or HOME")
+
+// Store keeps usage samples in a JSON Lines file under the user data directory.
+// Samples are appended, so concurrent status lines never rewrite each
+// other's lines; once a day the file is compacted in place, dropping old
+// samples and merging those older than a day per hour.
+type Store struct {
+	dir   string
+	cfg   model.HistoryConfig
+	trace *model.Trace
+}
+
+// NewStore creates the ledger for the history options.
+//
+// Params:
+//   - cfg: history options (sampling interval, retention)
+//
+// Returns:
+//   - *Store: ledger in the user data directory
+func NewStore(cfg model.HistoryConfig) *Store {
+	// Return ledger in the resolved directory
+	return &Store{dir: dataDir(), cfg: cfg}
+}
+
+// SetTrace records every file the store reads or writes in t.
+//
+// Params:
+//   - t: trace to record into, nil to stop tracing
+func (s *Store) SetTrace(t *model.Trace) {
+	s.trace = t
+}
+
+// Path returns the ledger file path.
+//
+// Returns:
+//   - string: ledger path, empty if no data directory is known
+func (s *Store) Path() string {
+	// Without a data directory there is no ledger
+	if s.dir == "" {
+		// Return empty path
+		return ""
+	}
+	// Return file inside the data directory
+	return filepath.Join(s.dir, ledgerFileName)
+}
+
+// Record appends a sample unless the ledger was written less than the
+// sampling interval before it, then compacts the ledger when due. Samples
+// dated before the last write, such as a cached reading an earlier render
+// already recorded, are skipped too.
+//
+// Params:
+//   - sample: usage reading to keep
+//
+// Returns:
+//   - error: write error if any
+func (s *Store) Record(sample model.UsageSample) error {
+	path := s.Path()
+	// Nowhere to write
+	if path == "" {
+		// Return directory error
+		return errNoDataDir
+	}
+	// Skip samples already recorded or closer than the interval to the last write
+	if info, err := os.Stat(path); err == nil && (!sample.Time.After(info.ModTime()) || sample.Time.Sub(info.ModTime()) < s.cfg.Interval.Std()) {
+		// Return without writing
+		return nil
+	}
+	line, err := json.Marshal(sample)
+	// Check for encoding errors
+	if err != nil {
+		// Return encoding error
+		return err
+	}
+	// Create the data directory
+	if err := os.MkdirAll(s.dir, dataDirPerm); err != nil {
+		// Return directory error
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, dataFilePerm)
+	// Check for open errors
+	if err != nil {
+		s.trace.Record(model.TraceFile, path, err)
+		// Return open error
+		return err
+	}
+	// A single short write keeps lines from concurrent processes whole
+	_, err = file.Write(append(line, '\n'))
+	// Close, keeping the first error
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	s.trace.Record(model.TraceFile, path, err)
+	// Report write errors before compacting
+	if err != nil {
+		// Return write error
+		return err
+	}
+	// Return compaction result
+	return s.compactIfDue(sample.Time)
+}
+
+// History returns the samples recorded since a time, in time order.
+// Lines that cannot be parsed, such as one cut short by a crash, are skipped.
+//
+// Params:
+//   - ctx: skips reading once the deadline has passed
+//   - since: earliest sample time
+//
+// Returns:
+//   - model.UsageHistory: samples in time order
+//   - error: read error, fs.ErrNotExist before the first sample
+func (s *Store) History(ctx context.Context, since time.Time) (model.UsageHistory, error) {
+	// Skip reads once the deadline has passed
+	if err := ctx.Err(); err != nil {
+		// Return deadline error
+		return nil, err
+	}
+	all, err := s.load()
+	// Check for read errors
+	if err != nil {
+		// Return read error
+		return nil, err
+	}
+	// Return the requested tail
+	return all.Since(since), nil
+}
+
+// load reads every sample of the ledger, sorted by time in the local time zone.
+// Processes appending concurrently may leave samples slightly out of order.
+//
+// Returns:
+//   - model.UsageHistory: every sample
+//   - error: read error if any
+func (s *Store) load() (model.UsageHistory, error) {
+	path := s.Path()
+	// Nothing to read without a data directory
+	if path == "" {
+		// Return directory error
+		return nil, errNoDataDir
+	}
+	data, err := os.ReadFile(path)
+	s.trace.Record(model.TraceFile, path, err)
+	// Check for read errors
+	if err != nil {
+		// Return read error
+		return nil, err
+	}
+	var history model.UsageHistory
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	// Decode each line
+	for scanner.Scan() {
+		var sample model.UsageSample
+		// Skip damaged lines
+		if json.Unmarshal(scanner.Bytes(), &sample) != nil {
+			continue
+		}
+		sample.Time = sample.Time.Local()
+		history = append(history, sample)
+	}
+	slices.SortStableFunc(history, func(a, b model.UsageSample) int {
+		// Order samples by time
+		return a.Time.Compare(b.Time)
+	})
+	// Return samples
+	return history, nil
+}
+
+// compactIfDue rewrites the ledger without old samples once a day.
+// Samples appended by another process during the rewrite may be lost.
+//
+// Params:
+//   - now: current time
+//
+// Returns:
+//   - error: read or write error if any
+func (s *Store) compactIfDue(now time.Time) error {
+	marker := filepath.Join(s.dir, compactedFileName)
+	// Wait for the next compaction
+	if info, err := os.Stat(marker); err == nil && now.Sub(info.ModTime()) < compactEvery {
+		// Return without compacting
+		return nil
+	}
+	history, err := s.load()
+	// Check for read errors
+	if err != nil {
+		// Return read error
+		return err
+	}
+	compacted := history.Compact(now, keepRecent, mergeBucket, s.cfg.Retention.Std())
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// Encode one sample per line
+	for _, sample := range compacted {
+		// Check for encoding errors
+		if err := enc.Encode(sample); err != nil {
+			// Return encoding error
+			return err
+		}
+	}
+	// Replace the ledger with a complete file
+	if err := atomicfile.Write(s.Path(), buf.Bytes(), dataFilePerm); err != nil {
+		// Return write error
+		return err
+	}
+	// Return marker write result
+	return os.WriteFile(marker, nil, dataFilePerm)
+}
+
+// dataDir resolves the application data directory.
+// The location is $XDG_DATA_HOME/status-line, then %LOCALAPPDATA%\status-line
+// on Windows, then ~/.local/share/status-line.
+//
+// Returns:
+//   - string: data directory, empty if no home directory is known
+func dataDir() string {
+	// Use XDG data home when set
+	if dir := os.Getenv(envXDGDataHome); dir != "" {
+		// Return XDG path
+		return filepath.Join(dir, appDirName)
+	}
+	// Use the local application data directory on Windows
+	if dir := os.Getenv(envLocalAppData); runtime.GOOS == "windows" && dir != "" {
+		// Return Windows path
+		return filepath.Join(dir, appDirName)
+	}
+	home, err := os.UserHomeDir()
+	// Check if home lookup failed
+	if err != nil {
+		// Return empty path when home is unknown
+		return ""
+	}
+	// Return ~/.local/share path
+	return filepath.Join(home, defaultDataHome, appDirName)
+}
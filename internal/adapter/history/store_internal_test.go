@@ -0,0 +1,175 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestDataDir(t *testing.T) {
+	tests := []struct {
+		name    string
+		xdg     string
+		home    string
+		wantDir string
+	}{
+		{name: "xdg data home", xdg: "/data", home: "/home/me", wantDir: filepath.Join("/data", appDirName)},
+		{name: "home fallback", home: "/home/me", wantDir: filepath.Join("/home/me", defaultDataHome, appDirName)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envXDGDataHome, tt.xdg)
+			t.Setenv(envLocalAppData, "")
+			t.Setenv("HOME", tt.home)
+			if got := dataDir(); got != tt.wantDir {
+				t.Errorf("dataDir() = %q, want %q", got, tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestStore_RecordHistory(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		records      []time.Duration
+		noInterval   bool
+		wantSessions []int
+	}{
+		{name: "first sample", records: []time.Duration{0}, wantSessions: []int{0}},
+		{name: "within interval", records: []time.Duration{0, time.Minute}, wantSessions: []int{0}},
+		{name: "after interval", records: []time.Duration{0, 10 * time.Minute}, wantSessions: []int{0, 1}},
+		{name: "same reading without interval", records: []time.Duration{0, 0}, noInterval: true, wantSessions: []int{0}},
+		{name: "new reading without interval", records: []time.Duration{0, time.Second}, noInterval: true, wantSessions: []int{0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig().History
+			if tt.noInterval {
+				cfg.Interval = 0
+			}
+			s := &Store{dir: t.TempDir(), cfg: cfg}
+			for i, offset := range tt.records {
+				at := now.Add(offset)
+				// Date the last write like a previous render would have
+				if i > 0 {
+					prev := now.Add(tt.records[i-1])
+					if err := os.Chtimes(s.Path(), prev, prev); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := s.Record(model.UsageSample{Time: at, Session: i}); err != nil {
+					t.Fatalf("Record() = %v", err)
+				}
+			}
+			got, err := s.History(context.Background(), now.Add(-time.Hour))
+			if err != nil {
+				t.Fatalf("History() error = %v", err)
+			}
+			if len(got) != len(tt.wantSessions) {
+				t.Fatalf("History() = %+v, want sessions %v", got, tt.wantSessions)
+			}
+			for i, sample := range got {
+				if sample.Session != tt.wantSessions[i] {
+					t.Errorf("History()[%d].Session = %d, want %d", i, sample.Session, tt.wantSessions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStore_load(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantSessions []int
+	}{
+		{
+			name:         "sorted, damaged lines skipped",
+			content:      "{\"t\":\"2026-10-17T12:00:00Z\",\"s\":2,\"w\":1}\n{\"t\":\"2026-10-17T11:00:00Z\",\"s\":1,\"w\":1}\n{\"t\":\"2026-10-17T13:0\n",
+			wantSessions: []int{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Store{dir: t.TempDir()}
+			if err := os.WriteFile(s.Path(), []byte(tt.content), dataFilePerm); err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.load()
+			if err != nil {
+				t.Fatalf("load() error = %v", err)
+			}
+			if len(got) != len(tt.wantSessions) {
+				t.Fatalf("load() = %+v, want sessions %v", got, tt.wantSessions)
+			}
+			for i, sample := range got {
+				if sample.Session != tt.wantSessions[i] {
+					t.Errorf("load()[%d].Session = %d, want %d", i, sample.Session, tt.wantSessions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStore_History_Missing(t *testing.T) {
+	s := &Store{dir: t.TempDir()}
+	if _, err := s.History(context.Background(), time.Time{}); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("History() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestStore_compactIfDue(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		markerAge time.Duration
+		marker    bool
+		wantLen   int
+	}{
+		{name: "never compacted", marker: false, wantLen: 1},
+		{name: "compacted recently", marker: true, markerAge: time.Hour, wantLen: 2},
+		{name: "compacted long ago", marker: true, markerAge: 48 * time.Hour, wantLen: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig().History
+			cfg.Retention = model.Duration(24 * time.Hour)
+			s := &Store{dir: t.TempDir(), cfg: cfg}
+			content := `{"t":"` + now.Add(-48*time.Hour).Format(time.RFC3339) + `","s":1,"w":1}` + "\n" +
+				`{"t":"` + now.Format(time.RFC3339) + `","s":2,"w":1}` + "\n"
+			if err := os.WriteFile(s.Path(), []byte(content), dataFilePerm); err != nil {
+				t.Fatal(err)
+			}
+			marker := filepath.Join(s.dir, compactedFileName)
+			if tt.marker {
+				if err := os.WriteFile(marker, nil, dataFilePerm); err != nil {
+					t.Fatal(err)
+				}
+				old := now.Add(-tt.markerAge)
+				if err := os.Chtimes(marker, old, old); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.compactIfDue(now); err != nil {
+				t.Fatalf("compactIfDue() = %v", err)
+			}
+			got, err := s.load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("after compactIfDue() %d samples, want %d", len(got), tt.wantLen)
+			}
+			if _, err := os.Stat(marker); err != nil {
+				t.Errorf("marker missing after compactIfDue(): %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Package httpclient builds the HTTP clients of the network adapters.
+// Proxy and certificate options are shared by the usage provider and the
+// updater, so both work behind the same corporate proxy.
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// errNoCertificates reports a CA file without any PEM certificate.
+var errNoCertificates error = errors.New("no PEM certificates")
+
+// New creates an HTTP client for the network options.
+// Invalid options yield a client whose requests fail with the reason, so
+// adapters report it where they already report request errors.
+//
+// Params:
+//   - cfg: proxy and CA file options
+//   - timeout: overall request timeout
+//
+// Returns:
+//   - *http.Client: configured client
+func New(cfg model.HTTPConfig, timeout time.Duration) *http.Client {
+	transport, err := newTransport(cfg)
+	// Fail every request with the configuration error
+	if err != nil {
+		// Return failing client
+		return &http.Client{Transport: failingTransport{err: err}, Timeout: timeout}
+	}
+	// Return configured client
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// newTransport clones the default transport with the network options applied.
+//
+// Params:
+//   - cfg: proxy and CA file options
+//
+// Returns:
+//   - *http.Transport: configured transport
+//   - error: invalid proxy URL or unusable CA file
+func newTransport(cfg model.HTTPConfig) (*http.Transport, error) {
+	// The default transport already honors HTTPS_PROXY and NO_PROXY
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Replace the environment proxy with the configured one
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		// Check for invalid proxy URLs
+		if err != nil {
+			// Return proxy error
+			return nil, fmt.Errorf("proxy %q: %w", cfg.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	// Trust the configured certificates on top of the system ones
+	if cfg.CAFile != "" {
+		pool, err := certPool(cfg.CAFile)
+		// Check for unusable CA files
+		if err != nil {
+			// Return CA file error
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	// Return configured transport
+	return transport, nil
+}
+
+// certPool loads the system certificates and the PEM bundle.
+//
+// Params:
+//   - path: PEM bundle file
+//
+// Returns:
+//   - *x509.CertPool: system certificates plus the bundle
+//   - error: read error or bundle without certificates
+func certPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	// Check for read errors
+	if err != nil {
+		// Return read error
+		return nil, fmt.Errorf("reading CA file: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	// Start empty where the system pool is unavailable
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	// Check that the bundle holds certificates
+	if !pool.AppendCertsFromPEM(data) {
+		// Return bundle error
+		return nil, fmt.Errorf("CA file %s: %w", path, errNoCertificates)
+	}
+	// Return extended pool
+	return pool, nil
+}
+
+// failingTransport fails every request with a configuration error.
+type failingTransport struct {
+	err error
+}
+
+// RoundTrip returns the configuration error.
+//
+// Params:
+//   - req: request not sent
+//
+// Returns:
+//   - *http.Response: always nil
+//   - error: configuration error
+func (t failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Close the body as the RoundTripper contract requires
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	// Return configuration error
+	return nil, t.err
+}
@@ -0,0 +1,93 @@
+package httpclient_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/adapter/httpclient"
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestNew_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	garbage := filepath.Join(dir, "garbage.pem")
+	if err := os.WriteFile(garbage, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		caFile  string
+		wantErr string
+	}{
+		{name: "trusted bundle", caFile: bundle, wantErr: ""},
+		{name: "system pool only", caFile: "", wantErr: "certificate"},
+		{name: "missing file", caFile: filepath.Join(dir, "missing.pem"), wantErr: "reading CA file"},
+		{name: "no certificates", caFile: garbage, wantErr: "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := httpclient.New(model.HTTPConfig{CAFile: tt.caFile}, 5*time.Second)
+			resp, err := client.Get(server.URL)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				resp.Body.Close()
+				return
+			}
+			if err == nil {
+				resp.Body.Close()
+				t.Fatal("Get() succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Get() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_Proxy(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		wantURL string
+	}{
+		{name: "absolute URL sent to proxy", target: "http://usage.invalid/api/oauth/usage", wantURL: "http://usage.invalid/api/oauth/usage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.String()
+				w.Write([]byte("proxied"))
+			}))
+			defer proxy.Close()
+
+			client := httpclient.New(model.HTTPConfig{Proxy: proxy.URL}, 5*time.Second)
+			resp, err := client.Get(tt.target)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			resp.Body.Close()
+			if got != tt.wantURL {
+				t.Errorf("proxy received %q, want %q", got, tt.wantURL)
+			}
+		})
+	}
+}
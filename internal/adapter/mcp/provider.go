@@ -2,7 +2,9 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -41,6 +43,7 @@ var _ port.MCPProvider = (*Provider)(nil)
 // It reads MCP server configurations from official Claude Code config files.
 type Provider struct {
 	projectDir string
+	trace      *model.Trace
 }
 
 // NewProvider creates a new MCP provider adapter.
@@ -55,19 +58,60 @@ func NewProvider(projectDir string) *Provider {
 	return &Provider{projectDir: projectDir}
 }
 
+// SetTrace records every config file read by the provider in t.
+//
+// Params:
+//   - t: trace to record into, nil to stop tracing
+func (p *Provider) SetTrace(t *model.Trace) {
+	p.trace = t
+}
+
+// readJSON reads and decodes a config file, recording the attempt in the trace.
+// Nothing is read once the deadline has passed.
+//
+// Params:
+//   - ctx: deadline for reading
+//   - path: config file path
+//   - v: value to decode into
+//
+// Returns:
+//   - error: read or parse error if any
+func (p *Provider) readJSON(ctx context.Context, path string, v any) error {
+	// Skip reads once the deadline has passed
+	if err := ctx.Err(); err != nil {
+		// Return deadline error
+		return err
+	}
+	data, err := os.ReadFile(path)
+	// Decode readable files
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	// Name parse errors after the file
+	if err != nil && data != nil {
+		err = fmt.Errorf("parsing %s: %w", path, err)
+	}
+	p.trace.Record(model.TraceFile, path, err)
+	// Return read or parse error
+	return err
+}
+
 // Servers returns the list of configured MCP servers.
 // Reads from all official config locations and merges results.
 // Order: Enterprise → User → Local → Project (following precedence)
 //
+// Params:
+//   - ctx: stops reading config files when the deadline passes
+//
 // Returns:
 //   - model.MCPServers: list of MCP server configurations
-func (p *Provider) Servers() model.MCPServers {
+func (p *Provider) Servers(ctx context.Context) model.MCPServers {
 	// Track unique servers by name (last one wins per precedence)
 	seen := make(map[string]bool, defaultMapCapacity)
 	servers := make(model.MCPServers, 0, defaultSliceCapacity)
 
 	// Read enterprise managed config (highest precedence)
-	enterpriseServers := p.readManagedConfig()
+	enterpriseServers := p.readManagedConfig(ctx)
 	// Add enterprise servers to results
 	for _, s := range enterpriseServers {
 		// Mark server as seen and add to list
@@ -76,7 +120,7 @@ func (p *Provider) Servers() model.MCPServers {
 	}
 
 	// Read user scope from ~/.claude.json mcpServers
-	userServers := p.readUserConfig()
+	userServers := p.readUserConfig(ctx)
 	// Add user servers not already seen
 	for _, s := range userServers {
 		// Skip if server already added from higher precedence
@@ -87,7 +131,7 @@ func (p *Provider) Servers() model.MCPServers {
 	}
 
 	// Read local scope from ~/.claude.json projects[path].mcpServers
-	localServers := p.readLocalConfig()
+	localServers := p.readLocalConfig(ctx)
 	// Add local servers not already seen
 	for _, s := range localServers {
 		// Skip if server already added from higher precedence
@@ -98,7 +142,7 @@ func (p *Provider) Servers() model.MCPServers {
 	}
 
 	// Read project scope from {project}/.mcp.json
-	projectServers := p.readProjectConfig()
+	projectServers := p.readProjectConfig(ctx)
 	// Add project servers not already seen
 	for _, s := range projectServers {
 		// Skip if server already added from higher precedence
@@ -168,9 +212,12 @@ func (p *Provider) managedConfigPath() string {
 // readUserConfig reads MCP servers from user-level config.
 // Looks for mcpServers at root of ~/.claude.json.
 //
+// Params:
+//   - ctx: deadline for reading
+//
 // Returns:
 //   - model.MCPServers: list of MCP servers from user config
-func (p *Provider) readUserConfig() model.MCPServers {
+func (p *Provider) readUserConfig(ctx context.Context) model.MCPServers {
 	path := p.userConfigPath()
 	// Check if path is provided
 	if path == "" {
@@ -178,17 +225,10 @@ func (p *Provider) readUserConfig() model.MCPServers {
 		return model.MCPServers{}
 	}
 
-	data, err := os.ReadFile(path)
-	// Check if file is readable
-	if err != nil {
-		// Return empty list if file not accessible
-		return model.MCPServers{}
-	}
-
 	var config userConfigFile
-	// Check if JSON is valid
-	if err := json.Unmarshal(data, &config); err != nil {
-		// Return empty list if parsing fails
+	// Check if file is readable and valid
+	if err := p.readJSON(ctx, path, &config); err != nil {
+		// Return empty list if file not accessible or invalid
 		return model.MCPServers{}
 	}
 
@@ -199,9 +239,12 @@ func (p *Provider) readUserConfig() model.MCPServers {
 // readLocalConfig reads MCP servers from local scope config.
 // Looks for servers in projects[projectDir].mcpServers of ~/.claude.json.
 //
+// Params:
+//   - ctx: deadline for reading
+//
 // Returns:
 //   - model.MCPServers: list of MCP servers from local config
-func (p *Provider) readLocalConfig() model.MCPServers {
+func (p *Provider) readLocalConfig(ctx context.Context) model.MCPServers {
 	path := p.userConfigPath()
 	// Check if path is provided
 	if path == "" {
@@ -209,17 +252,10 @@ func (p *Provider) readLocalConfig() model.MCPServers {
 		return model.MCPServers{}
 	}
 
-	data, err := os.ReadFile(path)
-	// Check if file is readable
-	if err != nil {
-		// Return empty list if file not accessible
-		return model.MCPServers{}
-	}
-
 	var config userConfigFile
-	// Check if JSON is valid
-	if err := json.Unmarshal(data, &config); err != nil {
-		// Return empty list if parsing fails
+	// Check if file is readable and valid
+	if err := p.readJSON(ctx, path, &config); err != nil {
+		// Return empty list if file not accessible or invalid
 		return model.MCPServers{}
 	}
 
@@ -238,22 +274,20 @@ func (p *Provider) readLocalConfig() model.MCPServers {
 // readProjectConfig reads MCP servers from project MCP config file.
 // Tries .mcp.json first, then falls back to mcp.json (undotted).
 //
+// Params:
+//   - ctx: deadline for reading
+//
 // Returns:
 //   - model.MCPServers: list of MCP servers from project config
-func (p *Provider) readProjectConfig() model.MCPServers {
+func (p *Provider) readProjectConfig(ctx context.Context) model.MCPServers {
 	paths := p.projectConfigPaths()
 	if len(paths) == 0 {
 		return model.MCPServers{}
 	}
 
 	for _, path := range paths {
-		data, err := os.ReadFile(path)
-		if err != nil {
-			continue
-		}
-
 		var config mcpConfigFile
-		if err := json.Unmarshal(data, &config); err != nil {
+		if err := p.readJSON(ctx, path, &config); err != nil {
 			continue
 		}
 
@@ -265,9 +299,12 @@ func (p *Provider) readProjectConfig() model.MCPServers {
 
 // readManagedConfig reads MCP servers from enterprise managed config.
 //
+// Params:
+//   - ctx: deadline for reading
+//
 // Returns:
 //   - model.MCPServers: list of MCP servers from managed-mcp.json
-func (p *Provider) readManagedConfig() model.MCPServers {
+func (p *Provider) readManagedConfig(ctx context.Context) model.MCPServers {
 	path := p.managedConfigPath()
 	// Check if path is provided
 	if path == "" {
@@ -275,17 +312,10 @@ func (p *Provider) readManagedConfig() model.MCPServers {
 		return model.MCPServers{}
 	}
 
-	data, err := os.ReadFile(path)
-	// Check if file is readable
-	if err != nil {
-		// Return empty list if file not accessible
-		return model.MCPServers{}
-	}
-
 	var config mcpConfigFile
-	// Check if JSON is valid
-	if err := json.Unmarshal(data, &config); err != nil {
-		// Return empty list if parsing fails
+	// Check if file is readable and valid
+	if err := p.readJSON(ctx, path, &config); err != nil {
+		// Return empty list if file not accessible or invalid
 		return model.MCPServers{}
 	}
 
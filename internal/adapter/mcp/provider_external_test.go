@@ -1,6 +1,7 @@
 package mcp_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/florent/status-line/internal/adapter/mcp"
@@ -35,7 +36,7 @@ func TestProvider_Servers(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := mcp.NewProvider(tt.projectDir)
-			servers := p.Servers()
+			servers := p.Servers(context.Background())
 			if servers == nil {
 				t.Error("Servers() returned nil, want empty slice")
 			}
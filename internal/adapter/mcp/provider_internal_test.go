@@ -1,9 +1,14 @@
 package mcp
 
 import (
+	"context"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
 )
 
 func TestProvider_userConfigPath(t *testing.T) {
@@ -74,7 +79,7 @@ func TestProvider_readUserConfig(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &Provider{projectDir: "/nonexistent/project"}
-			servers := p.readUserConfig()
+			servers := p.readUserConfig(context.Background())
 			if servers == nil {
 				t.Error("readUserConfig() = nil, want non-nil")
 			}
@@ -93,7 +98,7 @@ func TestProvider_readLocalConfig(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &Provider{projectDir: tt.projectDir}
-			servers := p.readLocalConfig()
+			servers := p.readLocalConfig(context.Background())
 			if servers == nil {
 				t.Error("readLocalConfig() = nil, want non-nil")
 			}
@@ -112,7 +117,7 @@ func TestProvider_readProjectConfig(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &Provider{projectDir: tt.projectDir}
-			servers := p.readProjectConfig()
+			servers := p.readProjectConfig(context.Background())
 			if servers == nil {
 				t.Error("readProjectConfig() = nil, want non-nil")
 			}
@@ -129,7 +134,7 @@ func TestProvider_readManagedConfig(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &Provider{projectDir: "/workspace"}
-			servers := p.readManagedConfig()
+			servers := p.readManagedConfig(context.Background())
 			if servers == nil {
 				t.Error("readManagedConfig() = nil, want non-nil")
 			}
@@ -155,7 +160,7 @@ func TestProvider_readProjectConfig_ValidJSON(t *testing.T) {
 				t.Fatalf("Failed to write temp file: %v", err)
 			}
 			p := &Provider{projectDir: tmpDir}
-			servers := p.readProjectConfig()
+			servers := p.readProjectConfig(context.Background())
 			if len(servers) != tt.wantLen {
 				t.Errorf("readProjectConfig() len = %d, want %d", len(servers), tt.wantLen)
 			}
@@ -181,7 +186,7 @@ func TestProvider_readProjectConfig_FallbackUndotted(t *testing.T) {
 				t.Fatalf("Failed to write temp file: %v", err)
 			}
 			p := &Provider{projectDir: tmpDir}
-			servers := p.readProjectConfig()
+			servers := p.readProjectConfig(context.Background())
 			if len(servers) != tt.wantLen {
 				t.Errorf("readProjectConfig() len = %d, want %d", len(servers), tt.wantLen)
 			}
@@ -236,3 +241,39 @@ func TestProvider_convertServers_EnabledState(t *testing.T) {
 		})
 	}
 }
+
+func TestProvider_readJSON(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(valid, []byte(`{"mcpServers":{}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(invalid, []byte(`{"mcpServers":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		path        string
+		wantErr     bool
+		wantMissing bool
+	}{
+		{name: "valid file", path: valid},
+		{name: "invalid file", path: invalid, wantErr: true},
+		{name: "missing file", path: filepath.Join(dir, "none.json"), wantErr: true, wantMissing: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trace model.Trace
+			p := &Provider{trace: &trace}
+			var cfg mcpConfigFile
+			err := p.readJSON(context.Background(), tt.path, &cfg)
+			if (err != nil) != tt.wantErr || errors.Is(err, fs.ErrNotExist) != tt.wantMissing {
+				t.Errorf("readJSON() error = %v, wantErr %v, wantMissing %v", err, tt.wantErr, tt.wantMissing)
+			}
+			if len(trace.Steps) != 1 || trace.Steps[0].Target != tt.path || trace.Steps[0].Err != err {
+				t.Errorf("Steps = %+v, want one step for %s", trace.Steps, tt.path)
+			}
+		})
+	}
+}
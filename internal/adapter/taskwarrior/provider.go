@@ -2,7 +2,9 @@
 package taskwarrior
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,10 +20,6 @@ import (
 const (
 	// taskBinary is the Taskwarrior binary name.
 	taskBinary string = "task"
-	// sessionDir is the directory containing session files.
-	sessionDir string = "/workspace/.claude/sessions"
-	// maxTaskNameLen is the maximum length for task name display.
-	maxTaskNameLen int = 15
 )
 
 // Compile-time interface implementation check.
@@ -29,22 +27,60 @@ var _ port.TaskwarriorProvider = (*Provider)(nil)
 
 // Provider implements port.TaskwarriorProvider by running task commands.
 // It detects Taskwarrior installation and reads project stats.
-type Provider struct{}
+type Provider struct {
+	sessionDir     string
+	taskNameLength int
+	trace          *model.Trace
+}
 
 // NewProvider creates a new Taskwarrior provider adapter.
 //
+// Params:
+//   - cfg: Taskwarrior options (session directory, task name length)
+//
 // Returns:
 //   - *Provider: new provider instance
-func NewProvider() *Provider {
-	// Return empty struct as no state is needed
-	return &Provider{}
+func NewProvider(cfg model.TaskwarriorConfig) *Provider {
+	// Return provider with configured options
+	return &Provider{
+		sessionDir:     cfg.SessionDir,
+		taskNameLength: cfg.TaskNameLength,
+	}
+}
+
+// SetTrace records every command, directory and file used by the provider in t.
+//
+// Params:
+//   - t: trace to record into, nil to stop tracing
+func (p *Provider) SetTrace(t *model.Trace) {
+	p.trace = t
+}
+
+// task runs a Taskwarrior command without confirmation or verbose output.
+//
+// Params:
+//   - ctx: kills the command when the deadline passes
+//   - args: Taskwarrior arguments
+//
+// Returns:
+//   - []byte: standard output
+//   - error: command error if any
+func (p *Provider) task(ctx context.Context, args ...string) ([]byte, error) {
+	args = append([]string{"rc.confirmation=off", "rc.verbose=nothing"}, args...)
+	output, err := exec.CommandContext(ctx, taskBinary, args...).Output()
+	p.trace.Record(model.TraceCommand, taskBinary+" "+strings.Join(args, " "), err)
+	// Return command result
+	return output, err
 }
 
 // Info returns Taskwarrior task information.
 //
+// Params:
+//   - ctx: kills the task commands when the deadline passes
+//
 // Returns:
 //   - model.TaskwarriorInfo: project stats and installation status
-func (p *Provider) Info() model.TaskwarriorInfo {
+func (p *Provider) Info(ctx context.Context) model.TaskwarriorInfo {
 	// Check if task binary exists
 	if !p.isInstalled() {
 		// Return empty info if not installed
@@ -55,7 +91,7 @@ func (p *Provider) Info() model.TaskwarriorInfo {
 	activeProject := p.findActiveSession()
 
 	// Get all legacy projects with stats
-	projects := p.getProjects()
+	projects := p.getProjects(ctx)
 
 	// Return task information
 	return model.TaskwarriorInfo{
@@ -67,21 +103,24 @@ func (p *Provider) Info() model.TaskwarriorInfo {
 
 // getProjects returns all projects with their task counts.
 //
+// Params:
+//   - ctx: kills the task commands when the deadline passes
+//
 // Returns:
 //   - []model.TaskwarriorProject: list of projects with stats
-func (p *Provider) getProjects() []model.TaskwarriorProject {
+func (p *Provider) getProjects(ctx context.Context) []model.TaskwarriorProject {
 	// Get list of project names
-	projectNames := p.listProjects()
+	projectNames := p.listProjects(ctx)
 	projects := make([]model.TaskwarriorProject, 0, len(projectNames))
 
 	// Get stats for each project
 	for _, name := range projectNames {
-		pending := p.countTasks("project:"+name, "status:pending")
+		pending := p.countTasks(ctx, "project:"+name, "status:pending")
 		// Skip projects with no pending tasks
 		if pending == 0 {
 			continue
 		}
-		completed := p.countTasks("project:"+name, "status:completed")
+		completed := p.countTasks(ctx, "project:"+name, "status:completed")
 
 		projects = append(projects, model.TaskwarriorProject{
 			Name:      p.shortProjectName(name),
@@ -102,12 +141,14 @@ func (p *Provider) getProjects() []model.TaskwarriorProject {
 
 // listProjects returns all unique project names.
 //
+// Params:
+//   - ctx: kills the command when the deadline passes
+//
 // Returns:
 //   - []string: list of project names
-func (p *Provider) listProjects() []string {
+func (p *Provider) listProjects(ctx context.Context) []string {
 	// Run task _unique project command
-	cmd := exec.Command(taskBinary, "rc.confirmation=off", "rc.verbose=nothing", "_unique", "project")
-	output, err := cmd.Output()
+	output, err := p.task(ctx, "_unique", "project")
 	// Check if command succeeded
 	if err != nil {
 		// Return empty slice if command fails
@@ -152,7 +193,13 @@ func (p *Provider) shortProjectName(name string) string {
 // Returns:
 //   - bool: true if task binary is in PATH
 func (p *Provider) isInstalled() bool {
-	_, err := exec.LookPath(taskBinary)
+	path, err := exec.LookPath(taskBinary)
+	// Report the resolved binary, or the lookup failure
+	if err == nil {
+		p.trace.Record(model.TraceFile, path, nil)
+	} else {
+		p.trace.Record(model.TraceCommand, taskBinary, err)
+	}
 	// Return true if binary found
 	return err == nil
 }
@@ -160,19 +207,14 @@ func (p *Provider) isInstalled() bool {
 // countTasks returns the count of tasks matching filters.
 //
 // Params:
+//   - ctx: kills the command when the deadline passes
 //   - filters: Taskwarrior filter expressions
 //
 // Returns:
 //   - int: number of matching tasks
-func (p *Provider) countTasks(filters ...string) int {
-	// Build command arguments
-	args := []string{"rc.confirmation=off", "rc.verbose=nothing"}
-	args = append(args, filters...)
-	args = append(args, "count")
-
+func (p *Provider) countTasks(ctx context.Context, filters ...string) int {
 	// Run task count command with filters
-	cmd := exec.Command(taskBinary, args...)
-	output, err := cmd.Output()
+	output, err := p.task(ctx, append(filters, "count")...)
 	// Check if command succeeded
 	if err != nil {
 		// Return zero if command fails
@@ -222,7 +264,8 @@ type sessionTask struct {
 //   - *model.TaskwarriorProject: active project or nil
 func (p *Provider) findActiveSession() *model.TaskwarriorProject {
 	// Check if session directory exists
-	entries, err := os.ReadDir(sessionDir)
+	entries, err := os.ReadDir(p.sessionDir)
+	p.trace.Record(model.TraceDir, p.sessionDir, err)
 	if err != nil {
 		// Return nil if directory not found
 		return nil
@@ -251,7 +294,7 @@ func (p *Provider) findActiveSession() *model.TaskwarriorProject {
 	}
 
 	// Read and parse session file
-	return p.parseSessionFile(filepath.Join(sessionDir, newestFile))
+	return p.parseSessionFile(filepath.Join(p.sessionDir, newestFile))
 }
 
 // parseSessionFile reads and parses a session JSON file.
@@ -265,6 +308,7 @@ func (p *Provider) parseSessionFile(path string) *model.TaskwarriorProject {
 	// Read file content
 	data, err := os.ReadFile(path)
 	if err != nil {
+		p.trace.Record(model.TraceFile, path, err)
 		// Return nil if file read fails
 		return nil
 	}
@@ -272,9 +316,11 @@ func (p *Provider) parseSessionFile(path string) *model.TaskwarriorProject {
 	// Parse JSON
 	var session sessionJSON
 	if err := json.Unmarshal(data, &session); err != nil {
+		p.trace.Record(model.TraceFile, path, fmt.Errorf("parsing %s: %w", path, err))
 		// Return nil if JSON parsing fails
 		return nil
 	}
+	p.trace.Record(model.TraceFile, path, nil)
 
 	// Convert to domain model
 	return p.convertSession(&session)
@@ -354,8 +400,8 @@ func (p *Provider) convertEpic(epic *sessionEpic) model.TaskwarriorEpic {
 func (p *Provider) convertTask(task *sessionTask) model.TaskwarriorTask {
 	// Truncate name if too long
 	name := task.Name
-	if len(name) > maxTaskNameLen {
-		name = name[:maxTaskNameLen-1] + "…"
+	if p.taskNameLength > 0 && len(name) > p.taskNameLength {
+		name = name[:p.taskNameLength-1] + "…"
 	}
 
 	// Build task
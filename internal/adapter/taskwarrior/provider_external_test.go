@@ -1,9 +1,11 @@
 package taskwarrior_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/florent/status-line/internal/adapter/taskwarrior"
+	"github.com/florent/status-line/internal/domain/model"
 )
 
 func TestNewProvider(t *testing.T) {
@@ -14,7 +16,7 @@ func TestNewProvider(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := taskwarrior.NewProvider()
+			p := taskwarrior.NewProvider(model.DefaultConfig().Taskwarrior)
 			if p == nil {
 				t.Error("NewProvider() returned nil")
 			}
@@ -30,8 +32,8 @@ func TestProvider_Info(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := taskwarrior.NewProvider()
-			info := p.Info()
+			p := taskwarrior.NewProvider(model.DefaultConfig().Taskwarrior)
+			info := p.Info(context.Background())
 			_ = info.HasProjects() // Just verify no panic
 		})
 	}
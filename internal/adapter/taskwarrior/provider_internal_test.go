@@ -1,6 +1,11 @@
 package taskwarrior
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
 
 func TestProvider_shortProjectName(t *testing.T) {
 	tests := []struct {
@@ -47,7 +52,7 @@ func TestProvider_countTasks(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &Provider{}
-			count := p.countTasks("status:pending")
+			count := p.countTasks(context.Background(), "status:pending")
 			if count < 0 {
 				t.Errorf("countTasks() = %d, want >= 0", count)
 			}
@@ -64,7 +69,7 @@ func TestProvider_getProjects(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &Provider{}
-			projects := p.getProjects()
+			projects := p.getProjects(context.Background())
 			if projects == nil {
 				t.Error("getProjects() = nil, want non-nil")
 			}
@@ -81,10 +86,35 @@ func TestProvider_listProjects(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &Provider{}
-			projects := p.listProjects()
+			projects := p.listProjects(context.Background())
 			if projects == nil {
 				t.Error("listProjects() = nil, want non-nil")
 			}
 		})
 	}
 }
+
+func TestProvider_convertTask(t *testing.T) {
+	tests := []struct {
+		name           string
+		taskNameLength int
+		taskName       string
+		want           string
+	}{
+		{name: "short name kept", taskNameLength: 15, taskName: "AuthService", want: "AuthService"},
+		{name: "long name truncated", taskNameLength: 6, taskName: "AuthService", want: "AuthS…"},
+		{name: "zero length keeps name", taskNameLength: 0, taskName: "AuthService", want: "AuthService"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProvider(model.TaskwarriorConfig{TaskNameLength: tt.taskNameLength})
+			got := p.convertTask(&sessionTask{ID: "T1", Name: tt.taskName, Status: "WIP"})
+			if got.Name != tt.want {
+				t.Errorf("convertTask().Name = %q, want %q", got.Name, tt.want)
+			}
+			if got.Status != model.StatusWip {
+				t.Errorf("convertTask().Status = %v, want %v", got.Status, model.StatusWip)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package terminal
 
 import (
 	"os"
+	"strings"
 
 	"github.com/florent/status-line/internal/domain/model"
 	"github.com/florent/status-line/internal/domain/port"
@@ -16,6 +17,20 @@ const (
 	ttyPath string = "/dev/tty"
 )
 
+// Environment variables describing color support.
+const (
+	// envNoColor disables colors when set to a non-empty value (https://no-color.org).
+	envNoColor string = "NO_COLOR"
+	// envColorTerm advertises true color support.
+	envColorTerm string = "COLORTERM"
+	// envTerm names the terminal type.
+	envTerm string = "TERM"
+	// termDumb is the terminal type without any escape sequence support.
+	termDumb string = "dumb"
+	// termXterm prefixes xterm terminal types, which all support 256 colors today.
+	termXterm string = "xterm"
+)
+
 // Compile-time interface implementation check.
 var _ port.TerminalProvider = (*Provider)(nil)
 
@@ -37,9 +52,54 @@ func NewProvider() *Provider {
 // Returns:
 //   - model.TerminalInfo: terminal dimensions
 func (p *Provider) Info() model.TerminalInfo {
-	// Return terminal info with width
+	// Return terminal info with width and color support
 	return model.TerminalInfo{
-		Width: p.getWidth(),
+		Width:      p.getWidth(),
+		ColorDepth: detectColorDepth(os.Getenv),
+	}
+}
+
+// detectColorDepth derives color support from the environment.
+// NO_COLOR wins, then COLORTERM, then TERM. Claude Code runs the status line
+// with a minimal environment, so an unset TERM and bare xterm types get 256
+// colors; only a dumb TERM gets none, and other terminals the 16 basic colors.
+//
+// Params:
+//   - getenv: environment lookup function
+//
+// Returns:
+//   - model.ColorDepth: detected color depth
+func detectColorDepth(getenv func(string) string) model.ColorDepth {
+	// Honor the NO_COLOR convention
+	if getenv(envNoColor) != "" {
+		// Return no colors
+		return model.ColorDepthNone
+	}
+	colorTerm := strings.ToLower(getenv(envColorTerm))
+	// Check for advertised true color
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		// Return true color
+		return model.ColorDepthTrueColor
+	}
+	termType := strings.ToLower(getenv(envTerm))
+	// Check terminal type
+	switch {
+	// No escape sequences
+	case termType == termDumb:
+		// Return no colors
+		return model.ColorDepthNone
+	// Direct color terminfo entries
+	case strings.HasSuffix(termType, "-direct") || strings.Contains(termType, "truecolor"):
+		// Return true color
+		return model.ColorDepthTrueColor
+	// 256-color terminfo entries, unset TERM and bare xterm types
+	case strings.Contains(termType, "256color") || termType == "" || strings.HasPrefix(termType, termXterm):
+		// Return palette colors
+		return model.ColorDepth256
+	// Anything else (linux console, vt100)
+	default:
+		// Return basic colors
+		return model.ColorDepth16
 	}
 }
 
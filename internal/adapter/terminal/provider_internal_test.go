@@ -1,6 +1,10 @@
 package terminal
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
 
 func TestProvider_getWidth(t *testing.T) {
 	tests := []struct {
@@ -18,3 +22,31 @@ func TestProvider_getWidth(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectColorDepth(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want model.ColorDepth
+	}{
+		{name: "no color wins", env: map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor", "TERM": "xterm-256color"}, want: model.ColorDepthNone},
+		{name: "empty no color is ignored", env: map[string]string{"NO_COLOR": "", "TERM": "xterm-256color"}, want: model.ColorDepth256},
+		{name: "colorterm truecolor", env: map[string]string{"COLORTERM": "truecolor", "TERM": "xterm"}, want: model.ColorDepthTrueColor},
+		{name: "colorterm 24bit", env: map[string]string{"COLORTERM": "24bit"}, want: model.ColorDepthTrueColor},
+		{name: "direct terminfo", env: map[string]string{"TERM": "xterm-direct"}, want: model.ColorDepthTrueColor},
+		{name: "256 color term", env: map[string]string{"TERM": "tmux-256color"}, want: model.ColorDepth256},
+		{name: "linux console", env: map[string]string{"TERM": "linux"}, want: model.ColorDepth16},
+		{name: "plain xterm", env: map[string]string{"TERM": "xterm"}, want: model.ColorDepth256},
+		{name: "xterm color", env: map[string]string{"TERM": "xterm-color"}, want: model.ColorDepth256},
+		{name: "dumb terminal", env: map[string]string{"TERM": "dumb"}, want: model.ColorDepthNone},
+		{name: "no term", env: map[string]string{}, want: model.ColorDepth256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := detectColorDepth(getenv); got != tt.want {
+				t.Errorf("detectColorDepth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
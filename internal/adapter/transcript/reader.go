@@ -0,0 +1,263 @@
+// Package transcript provides the session transcript adapter.
+package transcript
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/florent/status-line/internal/adapter/atomicfile"
+	"github.com/florent/status-line/internal/domain/model"
+	"github.com/florent/status-line/internal/domain/port"
+)
+
+// State file constants.
+const (
+	// cacheDirName is the application directory inside the user cache directory.
+	cacheDirName string = "status-line"
+	// stateDirName holds one read state per session.
+	stateDirName string = "transcripts"
+	// stateFileExt is the extension of state files.
+	stateFileExt string = ".json"
+	// stateDirPerm is the permission of the created state directory.
+	stateDirPerm os.FileMode = 0700
+	// stateFilePerm is the permission of state files.
+	stateFilePerm os.FileMode = 0600
+	// stateRetention is how long the state of an inactive session is kept.
+	stateRetention time.Duration = 30 * 24 * time.Hour
+	// pathKeyLength is the number of hex digits naming states of sessions without an ID.
+	pathKeyLength int = 16
+)
+
+// Compile-time interface implementation check.
+var _ port.TranscriptReader = (*Reader)(nil)
+
+// errNoTranscript reports that Claude Code sent no transcript path.
+var errNoTranscript error = errors.New("no transcript path")
+
+// Reader summarizes session transcripts incrementally.
+// The summary and the offset read up to are kept per session in the user
+// cache directory, so each call only parses the lines appended since the
+// previous one.
+type Reader struct {
+	dir   string
+	trace *model.Trace
+}
+
+// NewReader creates a reader keeping its state in the user cache directory.
+//
+// Returns:
+//   - *Reader: reader, re-reading whole transcripts when no cache directory is known
+func NewReader() *Reader {
+	base, err := os.UserCacheDir()
+	// Keep no state without a cache directory
+	if err != nil {
+		// Return stateless reader
+		return &Reader{}
+	}
+	// Return reader with a state directory
+	return &Reader{dir: filepath.Join(base, cacheDirName, stateDirName)}
+}
+
+// SetTrace records every file the reader reads or writes in t.
+//
+// Params:
+//   - t: trace to record into, nil to stop tracing
+func (r *Reader) SetTrace(t *model.Trace) {
+	r.trace = t
+}
+
+// Stats returns the summary of a session transcript, parsing only the lines
+// appended since the last call. A transcript shorter than the saved offset
+// was rewritten and is read again from the start. When the deadline passes,
+// the lines read so far are kept and the next call resumes after them.
+//
+// Params:
+//   - ctx: stops reading when the deadline passes
+//   - session: session ID and transcript path
+//
+// Returns:
+//   - model.TranscriptStats: summary of the transcript read so far
+//   - error: read or state error if any
+func (r *Reader) Stats(ctx context.Context, session model.SessionInfo) (model.TranscriptStats, error) {
+	path := session.TranscriptPath
+	// Nothing to read without a transcript
+	if path == "" {
+		// Return missing path error
+		return model.TranscriptStats{}, errNoTranscript
+	}
+	// Skip reads once the deadline has passed
+	if err := ctx.Err(); err != nil {
+		// Return deadline error
+		return model.TranscriptStats{}, err
+	}
+	st := r.load(session)
+	file, err := os.Open(path)
+	r.trace.Record(model.TraceFile, path, err)
+	// Check for open errors
+	if err != nil {
+		// Return open error
+		return model.TranscriptStats{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	// Check for stat errors
+	if err != nil {
+		// Return stat error
+		return model.TranscriptStats{}, err
+	}
+	// Start over when the transcript was replaced or truncated
+	if info.Size() < st.Offset {
+		st = state{Path: path}
+	}
+	// Resume after the last complete line read
+	if _, err := file.Seek(st.Offset, io.SeekStart); err != nil {
+		// Return seek error
+		return model.TranscriptStats{}, err
+	}
+	scanErr := st.scan(ctx, file)
+	// Return summary, keeping the progress made even after errors
+	return st.Stats, errors.Join(scanErr, r.save(session, st))
+}
+
+// load reads the saved state of a session.
+//
+// Params:
+//   - session: session ID and transcript path
+//
+// Returns:
+//   - state: saved state, or an empty one for a new, damaged or moved transcript
+func (r *Reader) load(session model.SessionInfo) state {
+	empty := state{Path: session.TranscriptPath}
+	path := r.statePath(session)
+	// Nothing saved without a state directory
+	if path == "" {
+		// Return empty state
+		return empty
+	}
+	data, err := os.ReadFile(path)
+	r.trace.Record(model.TraceFile, path, err)
+	// Start over without a state
+	if err != nil {
+		// Return empty state
+		return empty
+	}
+	var st state
+	// Start over when the state is damaged or belongs to another transcript
+	if json.Unmarshal(data, &st) != nil || st.Path != session.TranscriptPath || st.Offset < 0 {
+		// Return empty state
+		return empty
+	}
+	// Return saved state
+	return st
+}
+
+// save writes the state of a session, pruning old states on a session's first save.
+//
+// Params:
+//   - session: session ID and transcript path
+//   - st: state to keep
+//
+// Returns:
+//   - error: write error if any
+func (r *Reader) save(session model.SessionInfo, st state) error {
+	path := r.statePath(session)
+	// Keep nothing without a state directory
+	if path == "" {
+		// Return success
+		return nil
+	}
+	data, err := json.Marshal(st)
+	// Check for encoding errors
+	if err != nil {
+		// Return encoding error
+		return err
+	}
+	// Create the state directory
+	if err := os.MkdirAll(r.dir, stateDirPerm); err != nil {
+		// Return directory error
+		return err
+	}
+	// Drop states of sessions long gone when a new session starts
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		r.prune(time.Now())
+	}
+	err = atomicfile.Write(path, data, stateFilePerm)
+	r.trace.Record(model.TraceFile, path, err)
+	// Return write result
+	return err
+}
+
+// prune removes state files not written for longer than the retention.
+//
+// Params:
+//   - now: current time
+func (r *Reader) prune(now time.Time) {
+	entries, err := os.ReadDir(r.dir)
+	// Nothing to prune in an unreadable directory
+	if err != nil {
+		return
+	}
+	// Remove each expired state
+	for _, entry := range entries {
+		info, err := entry.Info()
+		// Keep recent and unreadable entries
+		if err != nil || !strings.HasSuffix(entry.Name(), stateFileExt) || now.Sub(info.ModTime()) < stateRetention {
+			continue
+		}
+		os.Remove(filepath.Join(r.dir, entry.Name()))
+	}
+}
+
+// statePath returns the state file of a session.
+// Sessions are named by their ID; sessions without one by a hash of their
+// transcript path.
+//
+// Params:
+//   - session: session ID and transcript path
+//
+// Returns:
+//   - string: state file path, empty without a state directory
+func (r *Reader) statePath(session model.SessionInfo) string {
+	// No state directory
+	if r.dir == "" {
+		// Return empty path
+		return ""
+	}
+	key := sanitizeKey(session.ID)
+	// Derive a key from the transcript path
+	if key == "" {
+		sum := sha256.Sum256([]byte(session.TranscriptPath))
+		key = hex.EncodeToString(sum[:])[:pathKeyLength]
+	}
+	// Return file inside the state directory
+	return filepath.Join(r.dir, key+stateFileExt)
+}
+
+// sanitizeKey keeps the characters of a session ID that are safe in file names.
+//
+// Params:
+//   - id: session ID
+//
+// Returns:
+//   - string: letters, digits, dashes and underscores of id
+func sanitizeKey(id string) string {
+	// Drop every other character
+	return strings.Map(func(r rune) rune {
+		// Keep file name safe characters
+		if r == '-' || r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			// Return character
+			return r
+		}
+		// Return drop marker
+		return -1
+	}, id)
+}
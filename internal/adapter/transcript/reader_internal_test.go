@@ -0,0 +1,124 @@
+package transcript
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// promptLine is one typed prompt transcript line.
+const promptLine string = "{\"type\":\"user\",\"message\":{\"content\":\"hi\"}}\n"
+
+// appendLines appends text to a transcript file.
+func appendLines(t *testing.T, path, text string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReader_Stats_Incremental(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "session.jsonl")
+	session := model.SessionInfo{ID: "abc", TranscriptPath: path}
+	steps := []struct {
+		name      string
+		write     func()
+		wantUsers int
+	}{
+		{name: "first read", write: func() { appendLines(t, path, promptLine+promptLine) }, wantUsers: 2},
+		{name: "appended lines", write: func() { appendLines(t, path, promptLine) }, wantUsers: 3},
+		{name: "partial line kept for later", write: func() { appendLines(t, path, "{\"type\":\"user\"") }, wantUsers: 3},
+		{name: "partial line completed", write: func() { appendLines(t, path, ",\"message\":{\"content\":\"x\"}}\n") }, wantUsers: 4},
+		{name: "rewritten transcript", write: func() {
+			if err := os.WriteFile(path, []byte(promptLine), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}, wantUsers: 1},
+	}
+	for _, step := range steps {
+		step.write()
+		// A new reader per step checks the state survives between renders
+		r := &Reader{dir: filepath.Join(dir, "state")}
+		got, err := r.Stats(context.Background(), session)
+		if err != nil {
+			t.Fatalf("%s: Stats() error = %v", step.name, err)
+		}
+		if got.UserMessages != step.wantUsers {
+			t.Errorf("%s: UserMessages = %d, want %d", step.name, got.UserMessages, step.wantUsers)
+		}
+	}
+}
+
+func TestReader_Stats_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		session model.SessionInfo
+		wantErr error
+	}{
+		{name: "no path", session: model.SessionInfo{ID: "abc"}, wantErr: errNoTranscript},
+		{name: "missing file", session: model.SessionInfo{ID: "abc", TranscriptPath: "/nonexistent/s.jsonl"}, wantErr: os.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{dir: t.TempDir()}
+			if _, err := r.Stats(context.Background(), tt.session); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Stats() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReader_statePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		session model.SessionInfo
+		want    string
+	}{
+		{name: "session id", dir: "/state", session: model.SessionInfo{ID: "ab-1"}, want: filepath.Join("/state", "ab-1.json")},
+		{name: "unsafe id", dir: "/state", session: model.SessionInfo{ID: "../x"}, want: filepath.Join("/state", "x.json")},
+		{name: "no state dir", session: model.SessionInfo{ID: "ab"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reader{dir: tt.dir}
+			if got := r.statePath(tt.session); got != tt.want {
+				t.Errorf("statePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_prune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	old := filepath.Join(dir, "old.json")
+	recent := filepath.Join(dir, "recent.json")
+	for _, path := range []string{old, recent} {
+		if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expired := now.Add(-stateRetention - time.Hour)
+	if err := os.Chtimes(old, expired, expired); err != nil {
+		t.Fatal(err)
+	}
+	(&Reader{dir: dir}).prune(now)
+	if _, err := os.Stat(old); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expired state kept, stat error = %v", err)
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("recent state removed: %v", err)
+	}
+}
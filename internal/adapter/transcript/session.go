@@ -0,0 +1,104 @@
+// Package transcript provides the session transcript adapter.
+package transcript
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Claude Code transcript location constants.
+const (
+	// envClaudeConfigDir overrides the Claude Code configuration directory.
+	envClaudeConfigDir string = "CLAUDE_CONFIG_DIR"
+	// claudeConfigDir is the Claude Code configuration directory in the home directory.
+	claudeConfigDir string = ".claude"
+	// projectsDirName holds one transcript directory per project.
+	projectsDirName string = "projects"
+	// transcriptExt is the extension of transcript files.
+	transcriptExt string = ".jsonl"
+	// projectKeySeparator replaces the characters of project paths unsafe in file names.
+	projectKeySeparator rune = '-'
+)
+
+// LatestSession returns the session whose transcript was written last in a
+// project, for commands run outside Claude Code. Transcripts are read from
+// CLAUDE_CONFIG_DIR or ~/.claude, under projects/ in a directory named
+// after the project path with every other character than letters and
+// digits replaced by a dash.
+//
+// Params:
+//   - projectDir: project directory
+//
+// Returns:
+//   - model.SessionInfo: session ID, transcript path and project directory
+//   - error: lookup error, fs.ErrNotExist when the project has no transcript
+func (r *Reader) LatestSession(projectDir string) (model.SessionInfo, error) {
+	base := os.Getenv(envClaudeConfigDir)
+	// Default to the home directory
+	if base == "" {
+		home, err := os.UserHomeDir()
+		// Check if home lookup failed
+		if err != nil {
+			// Return lookup error
+			return model.SessionInfo{}, err
+		}
+		base = filepath.Join(home, claudeConfigDir)
+	}
+	dir := filepath.Join(base, projectsDirName, projectKey(projectDir))
+	entries, err := os.ReadDir(dir)
+	r.trace.Record(model.TraceFile, dir, err)
+	// Check for directory errors
+	if err != nil {
+		// Return read error
+		return model.SessionInfo{}, err
+	}
+	var latest model.SessionInfo
+	var latestTime time.Time
+	// Keep the transcript written last
+	for _, entry := range entries {
+		info, err := entry.Info()
+		// Skip other files and unreadable entries
+		if err != nil || entry.IsDir() || !strings.HasSuffix(entry.Name(), transcriptExt) || !info.ModTime().After(latestTime) {
+			continue
+		}
+		latestTime = info.ModTime()
+		latest = model.SessionInfo{
+			ID:             strings.TrimSuffix(entry.Name(), transcriptExt),
+			TranscriptPath: filepath.Join(dir, entry.Name()),
+			ProjectDir:     projectDir,
+		}
+	}
+	// Report projects without any transcript
+	if latest.TranscriptPath == "" {
+		// Return missing transcript error
+		return model.SessionInfo{}, fmt.Errorf("%w in %s: %w", errNoTranscript, dir, fs.ErrNotExist)
+	}
+	// Return latest session
+	return latest, nil
+}
+
+// projectKey returns the transcript directory name of a project.
+//
+// Params:
+//   - projectDir: project directory
+//
+// Returns:
+//   - string: project path with every character but letters and digits as a dash
+func projectKey(projectDir string) string {
+	// Replace unsafe characters
+	return strings.Map(func(r rune) rune {
+		// Keep letters and digits
+		if (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			// Return character
+			return r
+		}
+		// Return separator
+		return projectKeySeparator
+	}, projectDir)
+}
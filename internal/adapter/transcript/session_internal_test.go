@@ -0,0 +1,69 @@
+package transcript
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestProjectKey(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  string
+		want string
+	}{
+		{name: "absolute path", dir: "/home/me/status-line", want: "-home-me-status-line"},
+		{name: "dots and underscores", dir: "/srv/my_app.v2", want: "-srv-my-app-v2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := projectKey(tt.dir); got != tt.want {
+				t.Errorf("projectKey(%q) = %q, want %q", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_LatestSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]time.Duration
+		wantID  string
+		wantErr error
+	}{
+		{name: "latest transcript", files: map[string]time.Duration{"old.jsonl": -time.Hour, "new.jsonl": 0, "notes.txt": time.Hour}, wantID: "new"},
+		{name: "no transcript", files: map[string]time.Duration{"notes.txt": 0}, wantErr: fs.ErrNotExist},
+		{name: "no project directory", wantErr: fs.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := t.TempDir()
+			t.Setenv(envClaudeConfigDir, base)
+			project := "/work/app"
+			dir := filepath.Join(base, projectsDirName, projectKey(project))
+			now := time.Now()
+			for name, age := range tt.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(dir, 0o700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0o600); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chtimes(path, now.Add(age), now.Add(age)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := (&Reader{}).LatestSession(project)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LatestSession() error = %v, want %v", err, tt.wantErr)
+			}
+			if got.ID != tt.wantID || (tt.wantID != "" && got.TranscriptPath != filepath.Join(dir, tt.wantID+transcriptExt)) {
+				t.Errorf("LatestSession() = %+v, want session %q", got, tt.wantID)
+			}
+		})
+	}
+}
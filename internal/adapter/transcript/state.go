@@ -0,0 +1,310 @@
+// Package transcript provides the session transcript adapter.
+package transcript
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Transcript entry types and subtypes.
+const (
+	// entryUser is a prompt or a tool result.
+	entryUser string = "user"
+	// entryAssistant is one content block of a model response.
+	entryAssistant string = "assistant"
+	// entrySystem is a notice written by Claude Code.
+	entrySystem string = "system"
+	// subtypeCompactBoundary marks where the conversation was compacted.
+	subtypeCompactBoundary string = "compact_boundary"
+	// blockText is a text content block.
+	blockText string = "text"
+	// blockToolResult is a tool result content block.
+	blockToolResult string = "tool_result"
+	// blockToolUse is a tool call content block.
+	blockToolUse string = "tool_use"
+)
+
+// agentTools are the tools starting a sub-agent.
+var agentTools []string = []string{"Task", "Agent"}
+
+// toolTarget names the input field holding what a tool call targets.
+type toolTarget struct {
+	field string
+	kind  model.ActivityTarget
+}
+
+// toolTargets maps built-in tools to their target; other tools show their name only.
+var toolTargets map[string]toolTarget = map[string]toolTarget{
+	"Read":         {field: "file_path", kind: model.ActivityTargetPath},
+	"Write":        {field: "file_path", kind: model.ActivityTargetPath},
+	"Edit":         {field: "file_path", kind: model.ActivityTargetPath},
+	"MultiEdit":    {field: "file_path", kind: model.ActivityTargetPath},
+	"NotebookEdit": {field: "notebook_path", kind: model.ActivityTargetPath},
+	"LS":           {field: "path", kind: model.ActivityTargetPath},
+	"Bash":         {field: "command", kind: model.ActivityTargetCommand},
+	"Grep":         {field: "pattern", kind: model.ActivityTargetText},
+	"Glob":         {field: "pattern", kind: model.ActivityTargetText},
+	"WebFetch":     {field: "url", kind: model.ActivityTargetURL},
+	"WebSearch":    {field: "query", kind: model.ActivityTargetText},
+	"Task":         {field: "description", kind: model.ActivityTargetText},
+	"Agent":        {field: "description", kind: model.ActivityTargetText},
+}
+
+// entry is the part of a transcript line the summary needs.
+type entry struct {
+	Type             string    `json:"type"`
+	Subtype          string    `json:"subtype"`
+	IsSidechain      bool      `json:"isSidechain"`
+	IsMeta           bool      `json:"isMeta"`
+	IsCompactSummary bool      `json:"isCompactSummary"`
+	Timestamp        time.Time `json:"timestamp"`
+	Message          message   `json:"message"`
+}
+
+// message is the API message of a user or assistant entry.
+type message struct {
+	ID      string          `json:"id"`
+	Content json.RawMessage `json:"content"`
+	Usage   *usage          `json:"usage"`
+}
+
+// usage is the token usage the API returned with a response.
+type usage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+}
+
+// block is the part of a content block the activity needs.
+type block struct {
+	Type      string                     `json:"type"`
+	ID        string                     `json:"id"`
+	Name      string                     `json:"name"`
+	Input     map[string]json.RawMessage `json:"input"`
+	ToolUseID string                     `json:"tool_use_id"`
+}
+
+// blocks returns the content blocks of a message.
+//
+// Returns:
+//   - []block: content blocks, nil for plain string content
+func (m message) blocks() []block {
+	var blocks []block
+	// Plain strings and unknown content hold no blocks
+	if json.Unmarshal(m.Content, &blocks) != nil {
+		// Return no blocks
+		return nil
+	}
+	// Return parsed blocks
+	return blocks
+}
+
+// activity returns the tool call a block makes.
+//
+// Returns:
+//   - model.Activity: tool name with its target when the tool has a known one
+func (b block) activity() model.Activity {
+	a := model.Activity{Tool: b.Name}
+	target, ok := toolTargets[b.Name]
+	// Show unknown tools by name only
+	if !ok {
+		// Return tool name
+		return a
+	}
+	var value string
+	// Keep string targets only
+	if json.Unmarshal(b.Input[target.field], &value) == nil {
+		a.Target, a.Kind = value, target.kind
+	}
+	// Return tool call
+	return a
+}
+
+// isPrompt reports whether a user message was typed rather than a tool result.
+// Prompts are a plain string or hold text blocks; tool results hold
+// tool_result blocks.
+//
+// Returns:
+//   - bool: true for prompts
+func (m message) isPrompt() bool {
+	var text string
+	// Plain string content is a prompt
+	if json.Unmarshal(m.Content, &text) == nil {
+		// Return prompt
+		return true
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+	}
+	// Unknown content is not counted
+	if json.Unmarshal(m.Content, &blocks) != nil {
+		// Return not a prompt
+		return false
+	}
+	prompt := false
+	// Look for text and tool results
+	for _, block := range blocks {
+		// Tool results are never prompts
+		if block.Type == blockToolResult {
+			// Return not a prompt
+			return false
+		}
+		prompt = prompt || block.Type == blockText
+	}
+	// Return whether text was found
+	return prompt
+}
+
+// state is the summary of a transcript and the offset it was read up to.
+// Responses are logged one content block per line with the same message ID,
+// so the last ID is kept to count each response once. Agents lists the
+// sub-agent calls still waiting for their result.
+type state struct {
+	Path          string                `json:"path"`
+	Offset        int64                 `json:"offset"`
+	Stats         model.TranscriptStats `json:"stats"`
+	LastMessageID string                `json:"last_message_id,omitempty"`
+	AfterBoundary bool                  `json:"after_boundary,omitempty"`
+	Agents        []string              `json:"agents,omitempty"`
+}
+
+// scan adds the complete lines of r to the summary.
+// A last line without a newline is still being written and is left for the
+// next call.
+//
+// Params:
+//   - ctx: stops reading when the deadline passes
+//   - r: transcript positioned at the offset
+//
+// Returns:
+//   - error: deadline or read error if any
+func (s *state) scan(ctx context.Context, r io.Reader) error {
+	reader := bufio.NewReader(r)
+	// Read line by line
+	for {
+		// Stop at the deadline, keeping the lines read
+		if err := ctx.Err(); err != nil {
+			// Return deadline error
+			return err
+		}
+		line, err := reader.ReadBytes('\n')
+		// Leave a partial last line unread
+		if errors.Is(err, io.EOF) {
+			// Return success
+			return nil
+		}
+		// Check for read errors
+		if err != nil {
+			// Return read error
+			return err
+		}
+		s.Offset += int64(len(line))
+		s.add(line)
+	}
+}
+
+// add adds one transcript line to the summary.
+// Damaged lines and sub-agent lines are skipped.
+//
+// Params:
+//   - line: JSON transcript entry
+func (s *state) add(line []byte) {
+	var e entry
+	// Skip lines that cannot be parsed
+	if json.Unmarshal(line, &e) != nil || e.IsSidechain {
+		return
+	}
+	afterBoundary := s.AfterBoundary
+	s.AfterBoundary = false
+	// Count the entry by kind
+	switch {
+	// Compaction marker
+	case e.Type == entrySystem && e.Subtype == subtypeCompactBoundary:
+		s.compacted(e.Timestamp)
+		s.AfterBoundary = true
+	// Summary replacing the conversation, alone in transcripts without markers
+	case e.IsCompactSummary:
+		// Count compactions not already marked
+		if !afterBoundary {
+			s.compacted(e.Timestamp)
+		}
+	// Typed prompt
+	case e.Type == entryUser && !e.IsMeta && e.Message.isPrompt():
+		s.Stats.UserMessages++
+	// Tool results
+	case e.Type == entryUser:
+		s.finished(e.Message.blocks())
+	// Response block
+	case e.Type == entryAssistant:
+		s.started(e.Message.blocks())
+		// Count each response once across its blocks
+		if e.Message.ID == "" || e.Message.ID != s.LastMessageID {
+			s.Stats.AssistantMessages++
+		}
+		s.LastMessageID = e.Message.ID
+		// Keep the usage of the last response that reported one
+		if u := e.Message.Usage; u != nil && *u != (usage{}) {
+			s.Stats.Tokens = model.TokenUsage{
+				Input:         u.InputTokens,
+				Output:        u.OutputTokens,
+				CacheRead:     u.CacheReadInputTokens,
+				CacheCreation: u.CacheCreationInputTokens,
+			}
+		}
+	}
+}
+
+// started records the tool calls of a response block.
+//
+// Params:
+//   - blocks: content blocks of the response
+func (s *state) started(blocks []block) {
+	// Track each tool call
+	for _, b := range blocks {
+		// Skip text and thinking blocks
+		if b.Type != blockToolUse {
+			continue
+		}
+		agents := s.Stats.Activity.Agents
+		s.Stats.Activity = b.activity()
+		s.Stats.Activity.Agents = agents
+		// Wait for the result of sub-agent calls
+		if slices.Contains(agentTools, b.Name) && b.ID != "" && !slices.Contains(s.Agents, b.ID) {
+			s.Agents = append(s.Agents, b.ID)
+			s.Stats.Activity.Agents = len(s.Agents)
+		}
+	}
+}
+
+// finished records the tool results of a user entry.
+//
+// Params:
+//   - blocks: content blocks of the entry
+func (s *state) finished(blocks []block) {
+	// Release sub-agents whose result arrived
+	for _, b := range blocks {
+		// Skip other blocks
+		if b.Type != blockToolResult {
+			continue
+		}
+		s.Agents = slices.DeleteFunc(s.Agents, func(id string) bool { return id == b.ToolUseID })
+	}
+	s.Stats.Activity.Agents = len(s.Agents)
+}
+
+// compacted records a compaction.
+//
+// Params:
+//   - at: time of the compaction
+func (s *state) compacted(at time.Time) {
+	s.Stats.Compactions++
+	s.Stats.LastCompaction = at
+}
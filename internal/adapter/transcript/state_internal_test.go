@@ -0,0 +1,163 @@
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestMessage_isPrompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{name: "plain string", content: `"hello"`, want: true},
+		{name: "text block", content: `[{"type":"text","text":"hi"}]`, want: true},
+		{name: "tool result", content: `[{"type":"tool_result","content":"ok"}]`, want: false},
+		{name: "image only", content: `[{"type":"image"}]`, want: false},
+		{name: "missing", content: ``, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := message{Content: json.RawMessage(tt.content)}
+			if got := m.isPrompt(); got != tt.want {
+				t.Errorf("isPrompt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestState_add(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  model.TranscriptStats
+	}{
+		{
+			name:  "prompt and response",
+			lines: []string{`{"type":"user","message":{"content":"hi"}}`, `{"type":"assistant","message":{"id":"a","usage":{"input_tokens":3,"output_tokens":5,"cache_read_input_tokens":100,"cache_creation_input_tokens":20}}}`},
+			want:  model.TranscriptStats{UserMessages: 1, AssistantMessages: 1, Tokens: model.TokenUsage{Input: 3, Output: 5, CacheRead: 100, CacheCreation: 20}},
+		},
+		{
+			name:  "response blocks counted once",
+			lines: []string{`{"type":"assistant","message":{"id":"a","usage":{"input_tokens":1}}}`, `{"type":"assistant","message":{"id":"a","usage":{"input_tokens":2}}}`, `{"type":"assistant","message":{"id":"b"}}`},
+			want:  model.TranscriptStats{AssistantMessages: 2, Tokens: model.TokenUsage{Input: 2}},
+		},
+		{
+			name:  "tool results and meta skipped",
+			lines: []string{`{"type":"user","message":{"content":[{"type":"tool_result"}]}}`, `{"type":"user","isMeta":true,"message":{"content":"caveat"}}`},
+			want:  model.TranscriptStats{},
+		},
+		{
+			name:  "sidechain and damaged lines skipped",
+			lines: []string{`{"type":"user","isSidechain":true,"message":{"content":"task"}}`, `{"type":"assistant","isSidechain":true,"message":{"id":"s","usage":{"input_tokens":9}}}`, `{not json`},
+			want:  model.TranscriptStats{},
+		},
+		{
+			name:  "boundary and its summary counted once",
+			lines: []string{`{"type":"system","subtype":"compact_boundary","timestamp":"2026-01-02T03:04:05Z"}`, `{"type":"user","isCompactSummary":true,"message":{"content":"summary"}}`},
+			want:  model.TranscriptStats{Compactions: 1, LastCompaction: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+		{
+			name:  "summary without boundary",
+			lines: []string{`{"type":"user","isCompactSummary":true,"timestamp":"2026-01-02T03:04:05Z","message":{"content":"summary"}}`},
+			want:  model.TranscriptStats{Compactions: 1, LastCompaction: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s state
+			for _, line := range tt.lines {
+				s.add([]byte(line))
+			}
+			if s.Stats != tt.want {
+				t.Errorf("Stats = %+v, want %+v", s.Stats, tt.want)
+			}
+		})
+	}
+}
+
+func TestState_add_Activity(t *testing.T) {
+	tests := []struct {
+		name       string
+		lines      []string
+		want       model.Activity
+		wantAgents []string
+	}{
+		{
+			name:  "last tool call with target",
+			lines: []string{`{"type":"assistant","message":{"id":"a","content":[{"type":"tool_use","id":"t1","name":"Read","input":{"file_path":"/r/a.go"}}]}}`, `{"type":"assistant","message":{"id":"a","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{"command":"go test"}}]}}`},
+			want:  model.Activity{Tool: "Bash", Target: "go test", Kind: model.ActivityTargetCommand},
+		},
+		{
+			name:  "unknown tool by name",
+			lines: []string{`{"type":"assistant","message":{"id":"a","content":[{"type":"tool_use","id":"t1","name":"mcp__github__get_issue","input":{"number":1}}]}}`},
+			want:  model.Activity{Tool: "mcp__github__get_issue"},
+		},
+		{
+			name: "running sub-agents",
+			lines: []string{
+				`{"type":"assistant","message":{"id":"a","content":[{"type":"tool_use","id":"t1","name":"Task","input":{"description":"Explore"}},{"type":"tool_use","id":"t2","name":"Task","input":{"description":"Review"}}]}}`,
+				`{"type":"assistant","isSidechain":true,"message":{"id":"s","content":[{"type":"tool_use","id":"s1","name":"Grep","input":{"pattern":"x"}}]}}`,
+				`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"done"}]}}`,
+			},
+			want:       model.Activity{Tool: "Task", Target: "Review", Agents: 1},
+			wantAgents: []string{"t2"},
+		},
+		{
+			name: "all sub-agents returned",
+			lines: []string{
+				`{"type":"assistant","message":{"id":"a","content":[{"type":"tool_use","id":"t1","name":"Agent","input":{"description":"Explore"}}]}}`,
+				`{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"done"}]}}`,
+			},
+			want: model.Activity{Tool: "Agent", Target: "Explore"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s state
+			for _, line := range tt.lines {
+				s.add([]byte(line))
+			}
+			if s.Stats.Activity != tt.want {
+				t.Errorf("Activity = %+v, want %+v", s.Stats.Activity, tt.want)
+			}
+			if !slices.Equal(s.Agents, tt.wantAgents) {
+				t.Errorf("Agents = %v, want %v", s.Agents, tt.wantAgents)
+			}
+		})
+	}
+}
+
+func TestState_scan(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantOffset int64
+		wantUsers  int
+	}{
+		{name: "complete lines", input: "{\"type\":\"user\",\"message\":{\"content\":\"a\"}}\n", wantOffset: 42, wantUsers: 1},
+		{name: "partial last line", input: "{\"type\":\"user\",\"message\":{\"content\":\"a\"}}\n{\"type\":\"user\"", wantOffset: 42, wantUsers: 1},
+		{name: "empty", input: "", wantOffset: 0, wantUsers: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s state
+			if err := s.scan(context.Background(), strings.NewReader(tt.input)); err != nil {
+				t.Fatalf("scan() error = %v", err)
+			}
+			if s.Offset != tt.wantOffset {
+				t.Errorf("Offset = %d, want %d", s.Offset, tt.wantOffset)
+			}
+			if s.Stats.UserMessages != tt.wantUsers {
+				t.Errorf("UserMessages = %d, want %d", s.Stats.UserMessages, tt.wantUsers)
+			}
+		})
+	}
+}
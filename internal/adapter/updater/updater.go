@@ -4,6 +4,7 @@ package updater
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,9 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/florent/status-line/internal/adapter/httpclient"
+	"github.com/florent/status-line/internal/domain/model"
 )
 
 // Updater constants for GitHub API, versioning, and file operations.
@@ -21,14 +25,10 @@ const (
 	repoOwner string = "kodflow"
 	// repoName is the GitHub repository name.
 	repoName string = "status-line"
-	// apiURL is the GitHub releases API endpoint.
-	apiURL string = "https://api.github.com/repos/%s/%s/releases/latest"
-	// downloadURL is the release asset download URL pattern.
-	downloadURL string = "https://github.com/%s/%s/releases/download/%s/%s"
-	// httpTimeout is the timeout for HTTP requests.
-	httpTimeout time.Duration = 10 * time.Second
-	// checkInterval is the minimum time between update checks.
-	checkInterval time.Duration = 1 * time.Hour
+	// releasePath is the latest release path under the API base URL.
+	releasePath string = "/repos/%s/%s/releases/latest"
+	// downloadPath is the release asset path pattern under the download base URL.
+	downloadPath string = "/%s/%s/releases/download/%s/%s"
 	// cacheFileName is the name of the update check cache file.
 	cacheFileName string = ".status-line-update-check"
 	// semverMajorIdx is the index of major version component.
@@ -46,27 +46,43 @@ const (
 // Updater handles self-update logic for status-line binary.
 // It checks GitHub releases periodically and downloads newer versions.
 type Updater struct {
-	version string
-	client  *http.Client
+	version       string
+	checkInterval time.Duration
+	apiURL        string
+	downloadURL   string
+	client        *http.Client
+	trace         *model.Trace
 }
 
 // NewUpdater creates a new updater instance.
 //
 // Params:
 //   - version: current binary version (empty means dev build)
+//   - cfg: update options (interval between checks, endpoints and network options)
 //
 // Returns:
 //   - *Updater: configured updater instance
-func NewUpdater(version string) *Updater {
+func NewUpdater(version string, cfg model.UpdateConfig) *Updater {
 	// Return configured updater
 	return &Updater{
-		version: version,
-		client:  &http.Client{Timeout: httpTimeout},
+		version:       version,
+		checkInterval: cfg.CheckInterval.Std(),
+		apiURL:        strings.TrimSuffix(cfg.APIURL, "/"),
+		downloadURL:   strings.TrimSuffix(cfg.DownloadURL, "/"),
+		client:        httpclient.New(cfg.HTTPConfig, cfg.Timeout.Std()),
 	}
 }
 
+// SetTrace records the release lookups of the updater in t.
+//
+// Params:
+//   - t: trace to record into, nil to stop tracing
+func (u *Updater) SetTrace(t *model.Trace) {
+	u.trace = t
+}
+
 // CheckForUpdate checks if an update is available without downloading.
-// Uses a cache file to limit checks to once per hour.
+// Uses a cache file to limit checks to once per check interval.
 //
 // Returns:
 //   - UpdateInfo: information about available update
@@ -104,6 +120,56 @@ func (u *Updater) CheckForUpdate() UpdateInfo {
 	return UpdateInfo{Available: true, Version: latest}
 }
 
+// Latest checks for an update right away, ignoring the check interval.
+// Unlike CheckForUpdate it reports why no version could be determined.
+//
+// Returns:
+//   - UpdateInfo: information about available update
+//   - error: dev build or release lookup error
+func (u *Updater) Latest() (UpdateInfo, error) {
+	// Dev builds have no version to compare against
+	if u.version == "" {
+		// Return dev build error
+		return UpdateInfo{}, errors.New("development build, updates are disabled")
+	}
+
+	// Record the check so the status line does not repeat it
+	u.updateCache()
+
+	// Return lookup result
+	return u.Lookup()
+}
+
+// Lookup asks for the latest release without recording the check, so the
+// status line still runs its own check when due.
+//
+// Returns:
+//   - UpdateInfo: information about available update
+//   - error: dev build or release lookup error
+func (u *Updater) Lookup() (UpdateInfo, error) {
+	// Dev builds have no version to compare against
+	if u.version == "" {
+		// Return dev build error
+		return UpdateInfo{}, errors.New("development build, updates are disabled")
+	}
+
+	latest, err := u.getLatestVersion()
+	// Check for API errors
+	if err != nil {
+		// Return lookup error
+		return UpdateInfo{}, err
+	}
+
+	// Compare versions
+	if !u.isNewer(latest) {
+		// Already up to date
+		return UpdateInfo{Version: latest}, nil
+	}
+
+	// Return update info
+	return UpdateInfo{Available: true, Version: latest}, nil
+}
+
 // DownloadUpdate downloads and applies the specified version.
 //
 // Params:
@@ -117,7 +183,7 @@ func (u *Updater) DownloadUpdate(version string) error {
 }
 
 // CheckAndUpdate checks for updates and applies them if available.
-// Uses a cache file to limit checks to once per hour.
+// Uses a cache file to limit checks to once per check interval.
 //
 // Returns:
 //   - bool: true if update was applied
@@ -156,7 +222,7 @@ func (u *Updater) shouldCheck() bool {
 	}
 
 	// Check if enough time has passed
-	return time.Since(info.ModTime()) > checkInterval
+	return time.Since(info.ModTime()) > u.checkInterval
 }
 
 // updateCache updates the cache file timestamp.
@@ -185,7 +251,22 @@ func (u *Updater) getCachePath() string {
 //   - string: latest version tag
 //   - error: any API error
 func (u *Updater) getLatestVersion() (string, error) {
-	url := fmt.Sprintf(apiURL, repoOwner, repoName)
+	url := u.apiURL + fmt.Sprintf(releasePath, repoOwner, repoName)
+	tag, err := u.fetchLatestVersion(url)
+	u.trace.Record(model.TraceRequest, url, err)
+	// Return lookup result
+	return tag, err
+}
+
+// fetchLatestVersion requests the latest release from the GitHub API.
+//
+// Params:
+//   - url: releases API URL
+//
+// Returns:
+//   - string: latest version tag
+//   - error: any API error
+func (u *Updater) fetchLatestVersion(url string) (string, error) {
 	resp, err := u.client.Get(url)
 	// Check for HTTP errors
 	if err != nil {
@@ -280,7 +361,7 @@ func (u *Updater) parseVersion(v string) [semverComponents]int {
 func (u *Updater) downloadAndReplace(version string) error {
 	// Get binary name for current platform
 	binaryName := u.getBinaryName()
-	url := fmt.Sprintf(downloadURL, repoOwner, repoName, version, binaryName)
+	url := u.downloadURL + fmt.Sprintf(downloadPath, repoOwner, repoName, version, binaryName)
 
 	// Download new binary
 	resp, err := u.client.Get(url)
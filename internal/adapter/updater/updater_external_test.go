@@ -1,9 +1,13 @@
 package updater_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/florent/status-line/internal/adapter/updater"
+	"github.com/florent/status-line/internal/domain/model"
 )
 
 func TestNewUpdater(t *testing.T) {
@@ -16,7 +20,7 @@ func TestNewUpdater(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := updater.NewUpdater(tt.version)
+			u := updater.NewUpdater(tt.version, model.DefaultConfig().Update)
 			// Verify updater is not nil
 			if u == nil {
 				t.Error("NewUpdater() returned nil")
@@ -36,7 +40,7 @@ func TestUpdater_CheckAndUpdate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := updater.NewUpdater(tt.version)
+			u := updater.NewUpdater(tt.version, model.DefaultConfig().Update)
 			// CheckAndUpdate should not panic
 			updated, err := u.CheckAndUpdate()
 			// Verify no unexpected errors
@@ -61,7 +65,7 @@ func TestUpdater_CheckForUpdate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := updater.NewUpdater(tt.version)
+			u := updater.NewUpdater(tt.version, model.DefaultConfig().Update)
 			// Check for update
 			info := u.CheckForUpdate()
 			// Dev build should return empty info
@@ -72,6 +76,78 @@ func TestUpdater_CheckForUpdate(t *testing.T) {
 	}
 }
 
+func TestUpdater_Latest(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{name: "dev build reports an error", version: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := updater.NewUpdater(tt.version, model.DefaultConfig().Update)
+			info, err := u.Latest()
+			// Verify dev builds are refused
+			if (err != nil) != tt.wantErr || info.Available {
+				t.Errorf("Latest() = %+v, %v, want error %v", info, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdater_Latest_Server(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		lookup        bool
+		wantAvailable bool
+		wantErr       bool
+	}{
+		{name: "newer release", status: http.StatusOK, body: `{"tag_name":"v1.2.0"}`, wantAvailable: true},
+		{name: "same release", status: http.StatusOK, body: `{"tag_name":"v1.0.0"}`, wantAvailable: false},
+		{name: "server error", status: http.StatusBadGateway, wantErr: true},
+		{name: "lookup only", status: http.StatusOK, body: `{"tag_name":"v1.2.0"}`, lookup: true, wantAvailable: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmp := t.TempDir()
+			t.Setenv("TMPDIR", tmp)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/repos/kodflow/status-line/releases/latest" {
+					t.Errorf("request path = %q", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			cfg := model.DefaultConfig().Update
+			cfg.APIURL = server.URL + "/"
+			u := updater.NewUpdater("v1.0.0", cfg)
+			check := u.Latest
+			if tt.lookup {
+				check = u.Lookup
+			}
+			info, err := check()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Latest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if info.Available != tt.wantAvailable {
+				t.Errorf("Latest() = %+v, want available %v", info, tt.wantAvailable)
+			}
+			entries, err := os.ReadDir(tmp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if recorded := len(entries) > 0; recorded == tt.lookup {
+				t.Errorf("check recorded = %v, want %v", recorded, !tt.lookup)
+			}
+		})
+	}
+}
+
 func TestUpdater_DownloadUpdate(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -82,7 +158,7 @@ func TestUpdater_DownloadUpdate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := updater.NewUpdater("v1.0.0")
+			u := updater.NewUpdater("v1.0.0", model.DefaultConfig().Update)
 			// Try to download
 			err := u.DownloadUpdate(tt.version)
 			// Verify error handling
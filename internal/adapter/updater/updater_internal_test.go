@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
 )
 
 func TestUpdater_parseVersion(t *testing.T) {
@@ -21,7 +23,7 @@ func TestUpdater_parseVersion(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := NewUpdater("")
+			u := NewUpdater("", model.DefaultConfig().Update)
 			got := u.parseVersion(tt.version)
 			// Verify parsed version matches expected
 			if got != tt.want {
@@ -48,7 +50,7 @@ func TestUpdater_isNewer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := NewUpdater(tt.current)
+			u := NewUpdater(tt.current, model.DefaultConfig().Update)
 			got := u.isNewer(tt.latest)
 			// Verify comparison result
 			if got != tt.want {
@@ -66,7 +68,7 @@ func TestUpdater_getBinaryName(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := NewUpdater("")
+			u := NewUpdater("", model.DefaultConfig().Update)
 			got := u.getBinaryName()
 			// Verify binary name is not empty
 			if got == "" {
@@ -93,7 +95,7 @@ func TestUpdater_shouldCheck(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := NewUpdater("v1.0.0")
+			u := NewUpdater("v1.0.0", model.DefaultConfig().Update)
 			cachePath := u.getCachePath()
 
 			// Clean up any existing cache
@@ -130,7 +132,7 @@ func TestUpdater_getCachePath(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := NewUpdater("")
+			u := NewUpdater("", model.DefaultConfig().Update)
 			got := u.getCachePath()
 			// Verify path is in temp directory
 			if filepath.Dir(got) != os.TempDir() {
@@ -148,7 +150,7 @@ func TestUpdater_updateCache(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := NewUpdater("")
+			u := NewUpdater("", model.DefaultConfig().Update)
 			cachePath := u.getCachePath()
 
 			// Clean up
@@ -180,7 +182,7 @@ func TestUpdater_getLatestVersion(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := NewUpdater("v1.0.0")
+			u := NewUpdater("v1.0.0", model.DefaultConfig().Update)
 			// Try to get latest version
 			version, err := u.getLatestVersion()
 			// API may fail in CI environment
@@ -207,7 +209,7 @@ func TestUpdater_downloadAndReplace(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := NewUpdater("v1.0.0")
+			u := NewUpdater("v1.0.0", model.DefaultConfig().Update)
 			// Try to download
 			err := u.downloadAndReplace(tt.version)
 			// Verify error handling
@@ -0,0 +1,142 @@
+// Package usage provides the Anthropic API usage adapter.
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Backoff constants.
+const (
+	// backoffBase is the delay after the first failed refresh.
+	backoffBase time.Duration = 30 * time.Second
+	// backoffMax caps the exponential delay between failed refreshes.
+	backoffMax time.Duration = 30 * time.Minute
+)
+
+// errBadResponse reports a successful response with an unreadable body.
+var errBadResponse error = errors.New("bad usage response")
+
+// statusError is a non-200 response from the usage API.
+type statusError struct {
+	code       int
+	retryAfter time.Duration
+}
+
+// newStatusError builds the error for a failed response.
+//
+// Params:
+//   - resp: API response with a non-200 status
+//
+// Returns:
+//   - *statusError: status code and requested retry delay
+func newStatusError(resp *http.Response) *statusError {
+	// Return status with the Retry-After delay
+	return &statusError{code: resp.StatusCode, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
+}
+
+// Error describes the response status.
+//
+// Returns:
+//   - string: status code, text and retry delay
+func (e *statusError) Error() string {
+	msg := fmt.Sprintf("status %d %s", e.code, http.StatusText(e.code))
+	// Mention the delay the server asked for
+	if e.retryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %s", e.retryAfter)
+	}
+	// Return description
+	return msg
+}
+
+// parseRetryAfter reads a Retry-After header, given in seconds or as an HTTP date.
+//
+// Params:
+//   - value: header value
+//   - now: reference time for dates
+//
+// Returns:
+//   - time.Duration: requested delay, 0 when absent or invalid
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	// Accept a number of seconds
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		// Return delay in seconds
+		return time.Duration(seconds) * time.Second
+	}
+	// Accept an HTTP date
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		// Return delay until the date
+		return at.Sub(now)
+	}
+	// Return no delay
+	return 0
+}
+
+// classify maps a fetch error to the status shown to the user.
+// Missing credentials are not a failure: the usage segments just stay hidden.
+//
+// Params:
+//   - err: fetch error
+//
+// Returns:
+//   - model.UsageStatus: status, empty when not worth reporting
+func classify(err error) model.UsageStatus {
+	var statusErr *statusError
+	// Classify API responses by status code
+	if errors.As(err, &statusErr) {
+		// Select status
+		switch statusErr.code {
+		// Token expired or revoked
+		case http.StatusUnauthorized, http.StatusForbidden:
+			// Return auth status
+			return model.UsageAuthExpired
+		// Too many requests
+		case http.StatusTooManyRequests:
+			// Return rate limit status
+			return model.UsageRateLimited
+		// Server errors and anything unexpected
+		default:
+			// Return unavailable status
+			return model.UsageUnavailable
+		}
+	}
+	var urlErr *url.Error
+	// Network failures and unreadable responses
+	if errors.As(err, &urlErr) || errors.Is(err, errBadResponse) {
+		// Return unavailable status
+		return model.UsageUnavailable
+	}
+	// Return no status
+	return ""
+}
+
+// backoff returns how long to wait before the next refresh after failures.
+// The delay doubles with each failure up to backoffMax, and never undercuts
+// the delay the server asked for.
+//
+// Params:
+//   - failures: consecutive failed refreshes, at least 1
+//   - err: last fetch error
+//
+// Returns:
+//   - time.Duration: delay before the next refresh
+func backoff(failures int, err error) time.Duration {
+	delay := backoffMax
+	// Double the base delay per failure until the cap
+	if shift := failures - 1; shift < 16 {
+		delay = min(backoffBase<<max(shift, 0), backoffMax)
+	}
+	var statusErr *statusError
+	// Honor Retry-After even beyond the cap
+	if errors.As(err, &statusErr) {
+		delay = max(delay, statusErr.retryAfter)
+	}
+	// Return delay
+	return delay
+}
@@ -0,0 +1,166 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// roundTripFunc answers requests without a network.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls the function.
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "seconds", value: "120", want: 2 * time.Minute},
+		{name: "date", value: now.Add(time.Hour).Format(http.TimeFormat), want: time.Hour},
+		{name: "past date", value: now.Add(-time.Hour).Format(http.TimeFormat), want: 0},
+		{name: "empty", value: "", want: 0},
+		{name: "invalid", value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want model.UsageStatus
+	}{
+		{name: "unauthorized", err: &statusError{code: http.StatusUnauthorized}, want: model.UsageAuthExpired},
+		{name: "rate limited", err: &statusError{code: http.StatusTooManyRequests}, want: model.UsageRateLimited},
+		{name: "server error", err: &statusError{code: http.StatusBadGateway}, want: model.UsageUnavailable},
+		{name: "network", err: &url.Error{Op: "Get", URL: "https://api.anthropic.com/api/oauth/usage", Err: io.EOF}, want: model.UsageUnavailable},
+		{name: "bad body", err: fmt.Errorf("%w: %w", errBadResponse, io.EOF), want: model.UsageUnavailable},
+		{name: "no credentials", err: os.ErrNotExist, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.err); got != tt.want {
+				t.Errorf("classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		err      error
+		want     time.Duration
+	}{
+		{name: "first", failures: 1, err: io.EOF, want: backoffBase},
+		{name: "third", failures: 3, err: io.EOF, want: 4 * backoffBase},
+		{name: "capped", failures: 10, err: io.EOF, want: backoffMax},
+		{name: "overflow", failures: 100, err: io.EOF, want: backoffMax},
+		{name: "retry after", failures: 1, err: &statusError{code: http.StatusTooManyRequests, retryAfter: 5 * time.Minute}, want: 5 * time.Minute},
+		{name: "retry after beyond cap", failures: 1, err: &statusError{code: http.StatusTooManyRequests, retryAfter: time.Hour}, want: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backoff(tt.failures, tt.err); got != tt.want {
+				t.Errorf("backoff(%d) = %s, want %s", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProvider_Usage_Backoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       int
+		retryAfter string
+		cached     bool
+		wantStatus model.UsageStatus
+		wantDelay  time.Duration
+	}{
+		{name: "auth expired", code: http.StatusUnauthorized, wantStatus: model.UsageAuthExpired, wantDelay: backoffBase},
+		{name: "rate limited", code: http.StatusTooManyRequests, retryAfter: "600", wantStatus: model.UsageRateLimited, wantDelay: 10 * time.Minute},
+		{name: "server error with cache", code: http.StatusServiceUnavailable, cached: true, wantStatus: model.UsageUnavailable, wantDelay: backoffBase},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CACHE_HOME", t.TempDir())
+			credDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(credDir, credentialsFileName), []byte(`{"claudeAiOauth":{"accessToken":"token"}}`), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := model.DefaultConfig().Usage
+			cfg.Credentials = model.CredentialConfig{Sources: []string{model.CredentialFile}, ConfigDir: credDir}
+			p := NewProvider(cfg)
+			requests := 0
+			p.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				requests++
+				header := http.Header{}
+				if tt.retryAfter != "" {
+					header.Set("Retry-After", tt.retryAfter)
+				}
+				return &http.Response{StatusCode: tt.code, Header: header, Body: io.NopCloser(strings.NewReader(""))}, nil
+			})
+			if tt.cached {
+				entry := cacheEntry{FetchedAt: time.Now().Add(-time.Hour), Data: model.UsageData{Weekly: model.NewWeeklyUsage(35, time.Now().Add(time.Hour))}}
+				if err := p.cache.save(entry); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			start := time.Now()
+			first, _ := p.Usage(context.Background())
+			p.Wait()
+			entry, err := p.cache.load()
+			if err != nil {
+				t.Fatalf("load() = %v", err)
+			}
+			if entry.Status != tt.wantStatus || entry.Failures != 1 {
+				t.Errorf("entry = status %q failures %d, want %q and 1", entry.Status, entry.Failures, tt.wantStatus)
+			}
+			if delay := entry.RetryAt.Sub(start); delay < tt.wantDelay || delay > tt.wantDelay+time.Minute {
+				t.Errorf("retry in %s, want %s", delay, tt.wantDelay)
+			}
+			if tt.cached && first.Weekly.Utilization != 35 {
+				t.Errorf("Usage() = %+v, want cached weekly usage", first)
+			}
+			if !tt.cached && first.Status != tt.wantStatus {
+				t.Errorf("Usage().Status = %q, want %q", first.Status, tt.wantStatus)
+			}
+
+			second, err := p.Usage(context.Background())
+			p.Wait()
+			if err != nil {
+				t.Errorf("Usage() during backoff error = %v", err)
+			}
+			if requests != 1 {
+				t.Errorf("requests = %d, want 1 during backoff", requests)
+			}
+			if second.Status != tt.wantStatus {
+				t.Errorf("Usage().Status = %q, want %q", second.Status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+// Package usage provides the Anthropic API usage adapter.
+package usage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/florent/status-line/internal/adapter/atomicfile"
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Cache file constants.
+const (
+	// cacheDirName is the application directory inside the user cache directory.
+	cacheDirName string = "status-line"
+	// cacheFileName is the cached usage response file.
+	cacheFileName string = "usage.json"
+	// lockFileName marks a refresh in progress.
+	lockFileName string = "usage.lock"
+	// cacheDirPerm is the permission of the created cache directory.
+	cacheDirPerm os.FileMode = 0700
+	// cacheFilePerm is the permission of cache files.
+	cacheFilePerm os.FileMode = 0600
+	// lockMargin is added to the longest refresh before a lock counts as abandoned.
+	lockMargin time.Duration = 5 * time.Second
+)
+
+// errRefreshing reports that another invocation is refreshing an empty cache.
+var errRefreshing error = errors.New("usage refresh in progress")
+
+// cacheEntry is the usage data stored on disk with its fetch time.
+// Failed refreshes keep the last data and record when to try again, so the
+// backoff holds across invocations.
+type cacheEntry struct {
+	FetchedAt time.Time         `json:"fetched_at"`
+	Data      model.UsageData   `json:"data"`
+	Failures  int               `json:"failures,omitempty"`
+	RetryAt   time.Time         `json:"retry_at,omitzero"`
+	Status    model.UsageStatus `json:"status,omitempty"`
+}
+
+// failed records a failed refresh in the entry.
+//
+// Params:
+//   - err: fetch error
+//   - now: time of the failure
+//
+// Returns:
+//   - cacheEntry: entry with the failure count, retry time and status updated
+func (e cacheEntry) failed(err error, now time.Time) cacheEntry {
+	e.Failures++
+	e.RetryAt = now.Add(backoff(e.Failures, err))
+	e.Status = classify(err)
+	// Return updated entry
+	return e
+}
+
+// cache stores the last usage response in the user cache directory.
+// A lock file next to it lets one invocation refresh while the others keep
+// serving the cached data.
+type cache struct {
+	dir        string
+	ttl        time.Duration
+	staleAfter time.Duration
+	lockAfter  time.Duration
+}
+
+// newCache creates the cache for the usage options.
+//
+// Params:
+//   - cfg: usage options (cache TTL, stale threshold, request timeout)
+//
+// Returns:
+//   - *cache: cache in the user cache directory, nil when disabled or unresolvable
+func newCache(cfg model.UsageConfig) *cache {
+	// A zero TTL disables caching
+	if cfg.CacheTTL <= 0 {
+		// Return disabled cache
+		return nil
+	}
+	base, err := os.UserCacheDir()
+	// Skip caching without a cache directory
+	if err != nil {
+		// Return disabled cache
+		return nil
+	}
+	// Return cache; a refresh takes up to a request timeout for the token
+	// renewal and fetch, then another for writing renewed credentials back,
+	// so a lock older than both belongs to a dead process
+	return &cache{
+		dir:        filepath.Join(base, cacheDirName),
+		ttl:        cfg.CacheTTL.Std(),
+		staleAfter: cfg.StaleAfter.Std(),
+		lockAfter:  2*cfg.Timeout.Std() + lockMargin,
+	}
+}
+
+// path returns the cache file path.
+//
+// Returns:
+//   - string: cache file path
+func (c *cache) path() string {
+	// Return file inside the cache directory
+	return filepath.Join(c.dir, cacheFileName)
+}
+
+// load reads the cached entry.
+//
+// Returns:
+//   - cacheEntry: cached data and fetch time
+//   - error: read or parse error, fs.ErrNotExist when nothing is cached
+func (c *cache) load() (cacheEntry, error) {
+	var entry cacheEntry
+	data, err := os.ReadFile(c.path())
+	// Check for read errors
+	if err != nil {
+		// Return read error
+		return entry, err
+	}
+	// Check for corrupt files
+	if err := json.Unmarshal(data, &entry); err != nil {
+		// Return parse error
+		return entry, fmt.Errorf("parsing %s: %w", c.path(), err)
+	}
+	// Return cached entry
+	return entry, nil
+}
+
+// save writes the entry atomically, so readers never see a partial file.
+//
+// Params:
+//   - entry: data and fetch time to store
+//
+// Returns:
+//   - error: write error if any
+func (c *cache) save(entry cacheEntry) error {
+	data, err := json.Marshal(entry)
+	// Check for encoding errors
+	if err != nil {
+		// Return encoding error
+		return err
+	}
+	// Create the cache directory
+	if err := os.MkdirAll(c.dir, cacheDirPerm); err != nil {
+		// Return directory error
+		return err
+	}
+	// Return write result
+	return atomicfile.Write(c.path(), data, cacheFilePerm)
+}
+
+// fresh reports whether an entry can be served without a refresh.
+//
+// Params:
+//   - entry: cached entry
+//
+// Returns:
+//   - bool: true within the TTL
+func (c *cache) fresh(entry cacheEntry) bool {
+	// Compare age to TTL
+	return time.Since(entry.FetchedAt) < c.ttl
+}
+
+// backingOff reports whether refreshes wait after recent failures.
+//
+// Params:
+//   - entry: cached entry
+//
+// Returns:
+//   - bool: true before the retry time
+func (c *cache) backingOff(entry cacheEntry) bool {
+	// Compare retry time to now
+	return time.Now().Before(entry.RetryAt)
+}
+
+// serve returns cached data, flagged as stale once it is older than the
+// threshold and carrying the status of the last failed refresh.
+//
+// Params:
+//   - entry: cached entry
+//
+// Returns:
+//   - model.UsageData: data to display
+func (c *cache) serve(entry cacheEntry) model.UsageData {
+	data := entry.Data
+	data.Status = entry.Status
+	// Flag data that refreshes have not replaced for too long
+	if time.Since(entry.FetchedAt) > c.staleAfter {
+		// Return stale data
+		return data.MarkStale()
+	}
+	// Return cached data
+	return data
+}
+
+// lock takes the refresh lock.
+// Only one invocation refreshes at a time; a lock left by a process that
+// died is taken over once it is older than the longest refresh. The lock
+// file holds the owner's pid and a random nonce, so releasing a lock that
+// was taken over does not remove the new owner's lock.
+//
+// Returns:
+//   - func(): releases the lock
+//   - bool: false when another invocation holds the lock
+func (c *cache) lock() (func(), bool) {
+	path := filepath.Join(c.dir, lockFileName)
+	// Create the cache directory
+	if err := os.MkdirAll(c.dir, cacheDirPerm); err != nil {
+		// Refresh without a lock rather than never
+		return func() {}, true
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, cacheFilePerm)
+	// Take over abandoned locks
+	if errors.Is(err, fs.ErrExist) {
+		info, statErr := os.Stat(path)
+		// Keep locks of running refreshes
+		if statErr == nil && time.Since(info.ModTime()) < c.lockAfter {
+			// Return busy
+			return nil, false
+		}
+		os.Remove(path)
+		file, err = os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, cacheFilePerm)
+		// Another invocation took it over first
+		if errors.Is(err, fs.ErrExist) {
+			// Return busy
+			return nil, false
+		}
+	}
+	// Refresh without a lock rather than never when the directory is not writable
+	if err != nil {
+		// Return no-op release
+		return func() {}, true
+	}
+	owner := fmt.Appendf(nil, "%d %s\n", os.Getpid(), rand.Text())
+	_, writeErr := file.Write(owner)
+	file.Close()
+	// Release an unmarked lock on failure rather than leave it to expire
+	if writeErr != nil {
+		os.Remove(path)
+		// Refresh without a lock rather than never
+		return func() {}, true
+	}
+	// Return release function
+	return func() { c.unlock(path, owner) }, true
+}
+
+// unlock removes the refresh lock if this invocation still owns it.
+//
+// Params:
+//   - path: lock file path
+//   - owner: content written when the lock was taken
+func (c *cache) unlock(path string, owner []byte) {
+	data, err := os.ReadFile(path)
+	// Leave locks taken over by another invocation
+	if err != nil || !bytes.Equal(data, owner) {
+		// Return without removing
+		return
+	}
+	os.Remove(path)
+}
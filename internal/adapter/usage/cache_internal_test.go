@@ -0,0 +1,174 @@
+package usage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestNewCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		wantNil bool
+	}{
+		{name: "enabled", ttl: time.Minute, wantNil: false},
+		{name: "disabled", ttl: 0, wantNil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CACHE_HOME", t.TempDir())
+			cfg := model.DefaultConfig().Usage
+			cfg.CacheTTL = model.Duration(tt.ttl)
+			got := newCache(cfg)
+			if (got == nil) != tt.wantNil {
+				t.Errorf("newCache() = %v, want nil %v", got, tt.wantNil)
+			}
+			if got != nil && got.lockAfter <= 2*cfg.Timeout.Std() {
+				t.Errorf("lockAfter = %v, want more than the fetch and write-back timeouts", got.lockAfter)
+			}
+		})
+	}
+}
+
+func TestCache_saveLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		age       time.Duration
+		wantFresh bool
+		wantStale bool
+	}{
+		{name: "fresh", age: 0, wantFresh: true, wantStale: false},
+		{name: "expired", age: 2 * time.Minute, wantFresh: false, wantStale: false},
+		{name: "stale", age: time.Hour, wantFresh: false, wantStale: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &cache{dir: t.TempDir(), ttl: time.Minute, staleAfter: 15 * time.Minute, lockAfter: time.Second}
+			want := model.UsageData{Weekly: model.NewWeeklyUsage(35, time.Now().Add(time.Hour).Truncate(time.Second))}
+			if err := c.save(cacheEntry{FetchedAt: time.Now().Add(-tt.age), Data: want}); err != nil {
+				t.Fatalf("save() = %v", err)
+			}
+			entry, err := c.load()
+			if err != nil {
+				t.Fatalf("load() = %v", err)
+			}
+			if entry.Data.Weekly.Utilization != 35 || !entry.Data.Weekly.ResetsAt.Equal(want.Weekly.ResetsAt) {
+				t.Errorf("load() = %+v, want %+v", entry.Data, want)
+			}
+			if got := c.fresh(entry); got != tt.wantFresh {
+				t.Errorf("fresh() = %v, want %v", got, tt.wantFresh)
+			}
+			if got := c.serve(entry); got.Weekly.Stale != tt.wantStale {
+				t.Errorf("serve().Weekly.Stale = %v, want %v", got.Weekly.Stale, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestCache_lock(t *testing.T) {
+	tests := []struct {
+		name     string
+		lockAge  time.Duration
+		existing bool
+		wantOK   bool
+	}{
+		{name: "free", existing: false, wantOK: true},
+		{name: "held", existing: true, lockAge: 0, wantOK: false},
+		{name: "abandoned", existing: true, lockAge: time.Minute, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &cache{dir: t.TempDir(), lockAfter: 5 * time.Second}
+			path := filepath.Join(c.dir, lockFileName)
+			if tt.existing {
+				if err := os.WriteFile(path, nil, cacheFilePerm); err != nil {
+					t.Fatal(err)
+				}
+				old := time.Now().Add(-tt.lockAge)
+				if err := os.Chtimes(path, old, old); err != nil {
+					t.Fatal(err)
+				}
+			}
+			unlock, ok := c.lock()
+			if ok != tt.wantOK {
+				t.Fatalf("lock() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if _, again := c.lock(); again {
+				t.Error("lock() succeeded twice")
+			}
+			data, err := os.ReadFile(path)
+			if err != nil || !strings.HasPrefix(string(data), strconv.Itoa(os.Getpid())+" ") {
+				t.Errorf("lock file = %q, %v, want the pid and a nonce", data, err)
+			}
+			unlock()
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("unlock() left %s", path)
+			}
+		})
+	}
+}
+
+func TestCache_lock_TakenOver(t *testing.T) {
+	c := &cache{dir: t.TempDir(), lockAfter: 5 * time.Second}
+	path := filepath.Join(c.dir, lockFileName)
+	unlock, ok := c.lock()
+	if !ok {
+		t.Fatal("lock() failed")
+	}
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlockNew, ok := c.lock()
+	if !ok {
+		t.Fatal("lock() did not take over the abandoned lock")
+	}
+	unlock()
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("stale unlock() removed the new owner's lock: %v", err)
+	}
+	unlockNew()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("unlock() left %s", path)
+	}
+}
+
+func TestProvider_Usage_Cached(t *testing.T) {
+	tests := []struct {
+		name      string
+		age       time.Duration
+		wantStale bool
+	}{
+		{name: "fresh cache", age: 0, wantStale: false},
+		{name: "stale cache", age: time.Hour, wantStale: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CACHE_HOME", t.TempDir())
+			t.Setenv("HOME", t.TempDir())
+			p := NewProvider(model.DefaultConfig().Usage)
+			entry := cacheEntry{FetchedAt: time.Now().Add(-tt.age), Data: model.UsageData{Session: model.NewSessionUsage(42, time.Now().Add(time.Hour))}}
+			if err := p.cache.save(entry); err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Usage(context.Background())
+			p.Wait()
+			if err != nil {
+				t.Fatalf("Usage() error = %v", err)
+			}
+			if got.Session.Utilization != 42 || got.Session.Stale != tt.wantStale {
+				t.Errorf("Usage() = %+v, want cached 42%% with stale %v", got.Session, tt.wantStale)
+			}
+		})
+	}
+}
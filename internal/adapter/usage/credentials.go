@@ -1,6 +1,20 @@
 // Package usage provides the Anthropic API usage adapter.
 package usage
 
+import (
+	"encoding/json"
+	"time"
+)
+
+// Credentials constants.
+const (
+	// oauthKey is the credentials JSON key holding the OAuth tokens.
+	oauthKey string = "claudeAiOauth"
+	// expiryMargin renews tokens slightly before they expire, so they do not
+	// expire in flight.
+	expiryMargin time.Duration = time.Minute
+)
+
 // credentialsFile represents the credentials JSON structure.
 // It wraps the OAuth credentials from Claude Code configuration.
 type credentialsFile struct {
@@ -8,7 +22,83 @@ type credentialsFile struct {
 }
 
 // oauthCredentials represents the nested OAuth credentials.
-// It contains the access token for API authentication.
+// It contains the access token for API authentication, and the refresh
+// token and expiry (Unix milliseconds) used to renew it.
 type oauthCredentials struct {
-	AccessToken string `json:"accessToken"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	ExpiresAt    int64  `json:"expiresAt,omitempty"`
+}
+
+// expired reports whether the access token has expired or is about to.
+// Credentials without an expiry never expire.
+//
+// Params:
+//   - now: reference time
+//
+// Returns:
+//   - bool: true when the token must be renewed before use
+func (c oauthCredentials) expired(now time.Time) bool {
+	// Unknown expiry, use the token as is
+	if c.ExpiresAt == 0 {
+		// Return valid
+		return false
+	}
+	// Compare expiry to now with a margin
+	return !now.Add(expiryMargin).Before(time.UnixMilli(c.ExpiresAt))
+}
+
+// withCredentials returns the credentials JSON with the OAuth tokens replaced.
+// Every other field (scopes, subscription, other keys) is kept as is.
+//
+// Params:
+//   - raw: credentials JSON as stored
+//   - creds: renewed tokens
+//
+// Returns:
+//   - []byte: updated credentials JSON
+//   - error: parse or encoding error
+func withCredentials(raw []byte, creds oauthCredentials) ([]byte, error) {
+	var doc map[string]json.RawMessage
+	// Parse the whole document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		// Return parse error
+		return nil, err
+	}
+	oauth := map[string]json.RawMessage{}
+	// Keep the existing OAuth fields
+	if existing, ok := doc[oauthKey]; ok {
+		// Parse the OAuth object
+		if err := json.Unmarshal(existing, &oauth); err != nil {
+			// Return parse error
+			return nil, err
+		}
+	}
+	fields := map[string]any{
+		"accessToken":  creds.AccessToken,
+		"refreshToken": creds.RefreshToken,
+	}
+	// Never store an unknown expiry: zero would mean the token never expires
+	if creds.ExpiresAt != 0 {
+		fields["expiresAt"] = creds.ExpiresAt
+	}
+	// Replace the token fields
+	for key, value := range fields {
+		encoded, err := json.Marshal(value)
+		// Check for encoding errors
+		if err != nil {
+			// Return encoding error
+			return nil, err
+		}
+		oauth[key] = encoded
+	}
+	encoded, err := json.Marshal(oauth)
+	// Check for encoding errors
+	if err != nil {
+		// Return encoding error
+		return nil, err
+	}
+	doc[oauthKey] = encoded
+	// Return updated document
+	return json.Marshal(doc)
 }
@@ -0,0 +1,162 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestOauthCredentials_expired(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		expiresAt int64
+		want      bool
+	}{
+		{name: "no expiry", expiresAt: 0, want: false},
+		{name: "valid", expiresAt: now.Add(time.Hour).UnixMilli(), want: false},
+		{name: "about to expire", expiresAt: now.Add(30 * time.Second).UnixMilli(), want: true},
+		{name: "expired", expiresAt: now.Add(-time.Hour).UnixMilli(), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (oauthCredentials{ExpiresAt: tt.expiresAt}).expired(now); got != tt.want {
+				t.Errorf("expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCredentials(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        string
+		wantScopes int
+		wantErr    bool
+	}{
+		{name: "keeps other fields", raw: `{"claudeAiOauth":{"accessToken":"old","scopes":["user:inference"]},"other":1}`, wantScopes: 1},
+		{name: "adds missing oauth", raw: `{"other":1}`, wantScopes: 0},
+		{name: "invalid json", raw: `{`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := withCredentials([]byte(tt.raw), oauthCredentials{AccessToken: "new", RefreshToken: "refresh", ExpiresAt: 42})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("withCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var doc struct {
+				ClaudeAiOauth struct {
+					oauthCredentials
+					Scopes []string `json:"scopes"`
+				} `json:"claudeAiOauth"`
+				Other int `json:"other"`
+			}
+			if err := json.Unmarshal(got, &doc); err != nil {
+				t.Fatal(err)
+			}
+			oauth := doc.ClaudeAiOauth.oauthCredentials
+			if oauth.AccessToken != "new" || oauth.RefreshToken != "refresh" || oauth.ExpiresAt != 42 || doc.Other != 1 {
+				t.Errorf("withCredentials() = %s", got)
+			}
+			if len(doc.ClaudeAiOauth.Scopes) != tt.wantScopes {
+				t.Errorf("withCredentials() scopes = %v, want %d", doc.ClaudeAiOauth.Scopes, tt.wantScopes)
+			}
+		})
+	}
+}
+
+func TestProvider_tokenFrom(t *testing.T) {
+	expired := time.Now().Add(-time.Hour).UnixMilli()
+	valid := time.Now().Add(time.Hour).UnixMilli()
+	tests := []struct {
+		name         string
+		expiresAt    int64
+		status       int
+		response     string
+		wantToken    string
+		wantRefresh  string
+		wantRequests int
+		keepExpiry   bool
+		prefix       string
+	}{
+		{name: "valid token", expiresAt: valid, wantToken: "old", wantRefresh: "refresh", wantRequests: 0},
+		{name: "renewed with rotation", expiresAt: expired, status: http.StatusOK, response: `{"access_token":"new","refresh_token":"rotated","expires_in":3600}`, wantToken: "new", wantRefresh: "rotated", wantRequests: 1},
+		{name: "renewed without rotation", expiresAt: expired, status: http.StatusOK, response: `{"access_token":"new","expires_in":3600}`, wantToken: "new", wantRefresh: "refresh", wantRequests: 1},
+		{name: "renewed without expiry", expiresAt: expired, status: http.StatusOK, response: `{"access_token":"new"}`, wantToken: "new", wantRefresh: "refresh", wantRequests: 1, keepExpiry: true},
+		{name: "renewed after leading whitespace", expiresAt: expired, status: http.StatusOK, response: `{"access_token":"new","expires_in":3600}`, wantToken: "new", wantRefresh: "refresh", wantRequests: 1, prefix: "\n  "},
+		{name: "renewal rejected", expiresAt: expired, status: http.StatusBadRequest, response: `{"error":"invalid_grant"}`, wantToken: "old", wantRefresh: "refresh", wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				var body map[string]string
+				json.NewDecoder(r.Body).Decode(&body)
+				if body["grant_type"] != "refresh_token" || body["refresh_token"] != "refresh" || body["client_id"] != "client" {
+					t.Errorf("token request = %v", body)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			cfg := model.DefaultConfig().Usage
+			cfg.TokenURL = server.URL
+			cfg.ClientID = "client"
+			p := NewProvider(cfg)
+			dir := t.TempDir()
+			path := filepath.Join(dir, credentialsFileName)
+			raw := tt.prefix + `{"claudeAiOauth":{"accessToken":"old","refreshToken":"refresh","expiresAt":` + strconv.FormatInt(tt.expiresAt, 10) + `,"subscriptionType":"max"}}`
+			if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := p.tokenFrom(context.Background(), fileSource{dir: dir})
+			if err != nil {
+				t.Fatalf("tokenFrom() error = %v", err)
+			}
+			if got != tt.wantToken {
+				t.Errorf("tokenFrom() = %q, want %q", got, tt.wantToken)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var stored struct {
+				ClaudeAiOauth struct {
+					oauthCredentials
+					SubscriptionType string `json:"subscriptionType"`
+				} `json:"claudeAiOauth"`
+			}
+			if err := json.Unmarshal(data, &stored); err != nil {
+				t.Fatal(err)
+			}
+			oauth := stored.ClaudeAiOauth
+			if oauth.AccessToken != tt.wantToken || oauth.RefreshToken != tt.wantRefresh || oauth.SubscriptionType != "max" {
+				t.Errorf("stored credentials = %s", data)
+			}
+			if tt.keepExpiry && oauth.ExpiresAt != tt.expiresAt {
+				t.Errorf("stored expiry = %d, want the previous %d", oauth.ExpiresAt, tt.expiresAt)
+			}
+			if tt.wantToken == "new" && !tt.keepExpiry && !time.UnixMilli(oauth.ExpiresAt).After(time.Now()) {
+				t.Errorf("stored expiry %d is not in the future", oauth.ExpiresAt)
+			}
+		})
+	}
+}
@@ -2,69 +2,239 @@
 package usage
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"runtime"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
+	"github.com/florent/status-line/internal/adapter/httpclient"
 	"github.com/florent/status-line/internal/domain/model"
 	"github.com/florent/status-line/internal/domain/port"
 )
 
 // API and authentication constants.
 const (
-	// usageAPIURL is the Anthropic usage API endpoint.
-	usageAPIURL string = "https://api.anthropic.com/api/oauth/usage"
-	// httpTimeout is the timeout for API requests.
-	httpTimeout time.Duration = 5 * time.Second
-	// keychainService is the macOS keychain service name.
-	keychainService string = "Claude Code-credentials"
+	// usagePath is the usage endpoint path under the API base URL.
+	usagePath string = "/api/oauth/usage"
 	// credentialsFileName is the credentials file name.
 	credentialsFileName string = ".credentials.json"
 	// claudeConfigDir is the Claude configuration directory.
 	claudeConfigDir string = ".claude"
 )
 
+// Credential errors.
+var (
+	// errNoCredentials reports that no credential source yielded a token.
+	errNoCredentials error = errors.New("no OAuth credentials found")
+	// errNoAccessToken reports credentials without an access token.
+	errNoAccessToken error = errors.New("no access token")
+	// errMalformedToken reports a token spanning lines or holding spaces.
+	errMalformedToken error = errors.New("malformed access token")
+)
+
+// utf8BOM is the byte order mark some editors put at the start of files.
+var utf8BOM []byte = []byte("\xef\xbb\xbf")
+
 // Compile-time interface implementation check.
 var _ port.UsageProvider = (*Provider)(nil)
 
 // Provider implements port.UsageProvider using Anthropic API.
-// It fetches weekly usage data from the OAuth usage endpoint.
+// It fetches weekly usage data from the OAuth usage endpoint and caches it
+// on disk, so most renders do not send a request.
 type Provider struct {
-	client *http.Client
+	client   *http.Client
+	timeout  time.Duration
+	usageURL string
+	tokenURL string
+	clientID string
+	sources  []CredentialSource
+	cache    *cache
+	pending  sync.WaitGroup
+	trace    *model.Trace
+}
+
+// fetchResult is the outcome of a background refresh.
+type fetchResult struct {
+	data model.UsageData
+	err  error
 }
 
 // NewProvider creates a new usage provider adapter.
 //
+// Params:
+//   - cfg: usage options (endpoints, network options, credentials, request timeout and cache)
+//
 // Returns:
 //   - *Provider: new provider instance
-func NewProvider() *Provider {
-	// Return provider with configured HTTP client
+func NewProvider(cfg model.UsageConfig) *Provider {
+	// Return provider with configured HTTP client and cache
 	return &Provider{
-		client: &http.Client{Timeout: httpTimeout},
+		client:   httpclient.New(cfg.HTTPConfig, cfg.Timeout.Std()),
+		timeout:  cfg.Timeout.Std(),
+		usageURL: strings.TrimSuffix(cfg.BaseURL, "/") + usagePath,
+		tokenURL: cfg.TokenURL,
+		clientID: cfg.ClientID,
+		sources:  NewCredentialSources(cfg.ActiveCredentials()),
+		cache:    newCache(cfg),
 	}
 }
 
+// SetCredentialSources replaces the configured credential sources.
+//
+// Params:
+//   - sources: sources to try in order
+func (p *Provider) SetCredentialSources(sources []CredentialSource) {
+	p.sources = sources
+}
+
+// SetTrace records every credential source and request used by the provider in t.
+//
+// Params:
+//   - t: trace to record into, nil to stop tracing
+func (p *Provider) SetTrace(t *model.Trace) {
+	p.trace = t
+}
 
 // Usage returns both session (5h) and weekly (7d) API usage.
+// Cached data is served as is within the cache TTL. Past it, one invocation
+// refreshes in the background while cached data keeps being served, flagged
+// as stale once refreshes have failed for too long. Without cached data the
+// call waits for the refresh until ctx ends; the refresh itself is bounded by
+// the request timeout only, so call Wait before exiting to let it fill the cache.
+// Failed refreshes back off exponentially (longer if the API sends
+// Retry-After), and the returned data carries the failure status.
+//
+// Params:
+//   - ctx: deadline for waiting on the API when nothing is cached
+//
+// Returns:
+//   - model.UsageData: session and weekly utilization and reset times
+//   - error: any error during fetch
+func (p *Provider) Usage(ctx context.Context) (model.UsageData, error) {
+	// Without a cache every call asks the API
+	if p.cache == nil {
+		data, err := p.fetch(ctx)
+		// Report why the request failed
+		if err != nil {
+			data.Status = classify(err)
+		}
+		// Return API response
+		return data, err
+	}
+
+	entry, err := p.cache.load()
+	p.trace.Record(model.TraceFile, p.cache.path(), err)
+	cached := err == nil
+	// Serve fresh data without a request
+	if cached && p.cache.fresh(entry) {
+		// Return cached data
+		return entry.Data, nil
+	}
+	// Leave the API alone until the backoff ends
+	if cached && p.cache.backingOff(entry) {
+		// Return cached data with the failure status
+		return p.cache.serve(entry), nil
+	}
+
+	done, started := p.refresh(ctx, entry)
+	// Serve cached data while a refresh runs here or elsewhere
+	if cached {
+		// Return cached data
+		return p.cache.serve(entry), nil
+	}
+	// Another invocation is filling the empty cache
+	if !started {
+		// Return busy error
+		return model.UsageData{}, errRefreshing
+	}
+	// Wait for the first response within the deadline
+	select {
+	// Refresh finished
+	case result := <-done:
+		// Return refresh result
+		return result.data, result.err
+	// Deadline passed first, the refresh goes on
+	case <-ctx.Done():
+		// Return deadline error
+		return model.UsageData{}, ctx.Err()
+	}
+}
+
+// Wait blocks until background cache refreshes are done.
+func (p *Provider) Wait() {
+	p.pending.Wait()
+}
+
+// refresh fetches usage data in the background and stores it in the cache.
+// The request is detached from ctx so it can outlive the caller's deadline.
+// An API failure keeps the previous data and stores the backoff instead;
+// missing credentials store nothing, so logging in takes effect at once.
+//
+// Params:
+//   - ctx: parent context (values only, its deadline is ignored)
+//   - prev: current cache entry, zero when nothing is cached
+//
+// Returns:
+//   - <-chan fetchResult: receives the refresh result
+//   - bool: false when another invocation holds the refresh lock
+func (p *Provider) refresh(ctx context.Context, prev cacheEntry) (<-chan fetchResult, bool) {
+	unlock, ok := p.cache.lock()
+	// Leave the refresh to the lock holder
+	if !ok {
+		// Return busy
+		return nil, false
+	}
+	done := make(chan fetchResult, 1)
+	p.pending.Add(1)
+	go func() {
+		defer p.pending.Done()
+		defer unlock()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.timeout)
+		defer cancel()
+		data, err := p.fetch(ctx)
+		// Select what to store
+		switch {
+		// Store successful responses
+		case err == nil:
+			saveErr := p.cache.save(cacheEntry{FetchedAt: time.Now(), Data: data})
+			p.trace.Record(model.TraceFile, p.cache.path(), saveErr)
+		// Keep the previous data and back off after API failures
+		case classify(err) != "":
+			next := prev.failed(err, time.Now())
+			saveErr := p.cache.save(next)
+			p.trace.Record(model.TraceFile, p.cache.path(), saveErr)
+			data = p.cache.serve(next)
+		}
+		done <- fetchResult{data: data, err: err}
+	}()
+	// Return result channel
+	return done, true
+}
+
+// fetch requests usage data from the API.
+//
+// Params:
+//   - ctx: cancels the keychain lookup and request when the deadline passes
 //
 // Returns:
 //   - model.UsageData: session and weekly utilization and reset times
 //   - error: any error during fetch
-func (p *Provider) Usage() (model.UsageData, error) {
+func (p *Provider) fetch(ctx context.Context) (model.UsageData, error) {
 	// Get OAuth token
-	token, err := p.getToken()
+	token, err := p.getToken(ctx)
 	if err != nil {
 		return model.UsageData{}, err
 	}
 
 	// Create API request
-	req, err := http.NewRequest(http.MethodGet, usageAPIURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.usageURL, nil)
 	if err != nil {
 		return model.UsageData{}, err
 	}
@@ -77,21 +247,33 @@ func (p *Provider) Usage() (model.UsageData, error) {
 	// Execute request
 	resp, err := p.client.Do(req)
 	if err != nil {
+		p.trace.Record(model.TraceRequest, p.usageURL, err)
 		return model.UsageData{}, err
 	}
 	defer resp.Body.Close()
 
+	// Reject error statuses, keeping the Retry-After delay
+	if resp.StatusCode != http.StatusOK {
+		statusErr := newStatusError(resp)
+		p.trace.Record(model.TraceRequest, p.usageURL, statusErr)
+		return model.UsageData{}, statusErr
+	}
+
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
+		p.trace.Record(model.TraceRequest, p.usageURL, err)
 		return model.UsageData{}, err
 	}
 
 	// Parse JSON response
 	var usage usageResponse
 	if err := json.Unmarshal(body, &usage); err != nil {
+		err = fmt.Errorf("%w: %w", errBadResponse, err)
+		p.trace.Record(model.TraceRequest, p.usageURL, err)
 		return model.UsageData{}, err
 	}
+	p.trace.Record(model.TraceRequest, p.usageURL, nil)
 
 	// Parse session (five_hour) reset time
 	sessionResetsAt, err := time.Parse(time.RFC3339, usage.FiveHour.ResetsAt)
@@ -106,92 +288,193 @@ func (p *Provider) Usage() (model.UsageData, error) {
 	}
 
 	return model.UsageData{
-		Session: model.NewSessionUsage(int(usage.FiveHour.Utilization), sessionResetsAt),
-		Weekly:  model.NewWeeklyUsage(int(usage.SevenDay.Utilization), weeklyResetsAt),
+		Session:   model.NewSessionUsage(int(usage.FiveHour.Utilization), sessionResetsAt),
+		Weekly:    model.NewWeeklyUsage(int(usage.SevenDay.Utilization), weeklyResetsAt),
+		Windows:   parseWindows(body),
+		FetchedAt: time.Now(),
 	}, nil
 }
 
-// getToken retrieves the OAuth token from the appropriate source.
+// getToken retrieves the OAuth token from the first credential source that has one.
+//
+// Params:
+//   - ctx: cancels the source lookups and token renewal when the deadline passes
 //
 // Returns:
 //   - string: OAuth access token
-//   - error: any error during retrieval
-func (p *Provider) getToken() (string, error) {
-	// Try platform-specific methods first
-	switch runtime.GOOS {
-	// macOS uses keychain
-	case "darwin":
-		token, err := p.getTokenFromKeychain()
-		// Check if token was successfully retrieved
-		if err == nil && token != "" {
-			// Return valid keychain token
+//   - error: errNoCredentials with the failure of every source tried
+func (p *Provider) getToken(ctx context.Context) (string, error) {
+	var errs []error
+	// Try each source in order
+	for _, source := range p.sources {
+		token, err := p.tokenFrom(ctx, source)
+		// Use the first token found
+		if err == nil {
+			// Return token
 			return token, nil
 		}
+		// Keep failures of sources that apply here
+		if !errors.Is(err, errSkipped) {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+		}
 	}
-
-	// Fall back to credentials file
-	return p.getTokenFromFile()
+	// Return every failure
+	return "", errors.Join(append([]error{errNoCredentials}, errs...)...)
 }
 
-// getTokenFromKeychain retrieves the token from macOS keychain.
+// tokenFrom reads the access token from a credential source, renewing it
+// first when it has expired. The renewed tokens are written back to the source.
+//
+// Params:
+//   - ctx: cancels the source access and token renewal when the deadline passes
+//   - source: credential source
 //
 // Returns:
 //   - string: OAuth access token
-//   - error: any error during retrieval
-func (p *Provider) getTokenFromKeychain() (string, error) {
-	// Execute security command
-	cmd := exec.Command("security", "find-generic-password", "-s", keychainService, "-w")
-	output, err := cmd.Output()
-	// Check if command failed
+//   - error: any error during retrieval, errNoAccessToken when the source is empty
+func (p *Provider) tokenFrom(ctx context.Context, source CredentialSource) (string, error) {
+	raw, err := source.Read(ctx, p.trace)
+	// Check if read failed
 	if err != nil {
 		// Return empty on error
 		return "", err
 	}
 
+	raw = bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(raw), utf8BOM))
+	// Accept a bare token, as printed by token commands
+	if !bytes.HasPrefix(raw, []byte("{")) {
+		// Return bare token if well formed
+		return checkToken(string(raw))
+	}
+
 	// Parse JSON credentials
 	var creds credentialsFile
 	// Check if parsing failed
-	if err := json.Unmarshal(output, &creds); err != nil {
+	if err := json.Unmarshal(raw, &creds); err != nil {
 		// Return empty on error
 		return "", err
 	}
+	oauth := creds.ClaudeAiOauth
+	oauth.AccessToken, err = checkToken(oauth.AccessToken)
+	// Check for credentials without a usable token
+	if err != nil {
+		// Return token error
+		return "", err
+	}
+
+	// Use valid tokens, and expired ones that cannot be renewed
+	if !oauth.expired(time.Now()) || oauth.RefreshToken == "" {
+		// Return access token
+		return oauth.AccessToken, nil
+	}
+	renewed, err := p.renewToken(ctx, oauth)
+	// Let the API reject the expired token, which reports the expiry
+	if err != nil {
+		// Return expired token
+		return oauth.AccessToken, nil
+	}
+	data, err := withCredentials(raw, renewed)
+	// Store the renewed tokens even past the deadline, since the old refresh
+	// token may no longer work; they are used even if storing fails
+	if err == nil {
+		writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.timeout)
+		source.Write(writeCtx, p.trace, data)
+		cancel()
+	}
 
-	// Return access token
-	return creds.ClaudeAiOauth.AccessToken, nil
+	// Return renewed access token
+	return renewed.AccessToken, nil
 }
 
-// getTokenFromFile retrieves the token from credentials file.
+// checkToken trims an access token and checks that it can be sent as a
+// bearer token: a single word on a single line.
+//
+// Params:
+//   - token: access token as read from the source
 //
 // Returns:
-//   - string: OAuth access token
-//   - error: any error during retrieval
-func (p *Provider) getTokenFromFile() (string, error) {
-	// Get user home directory
-	home, err := os.UserHomeDir()
-	// Check if home lookup failed
+//   - string: trimmed access token
+//   - error: errNoAccessToken when empty, errMalformedToken when it holds spaces
+func checkToken(token string) (string, error) {
+	token = strings.TrimSpace(token)
+	// Check for empty token
+	if token == "" {
+		// Return empty source error
+		return "", errNoAccessToken
+	}
+	// Reject text that is not a single token, such as error messages
+	if strings.ContainsFunc(token, unicode.IsSpace) {
+		// Return malformed token error
+		return "", errMalformedToken
+	}
+	// Return trimmed token
+	return token, nil
+}
+
+// renewToken exchanges the refresh token for a new access token.
+//
+// Params:
+//   - ctx: cancels the request when the deadline passes
+//   - creds: expired credentials with a refresh token
+//
+// Returns:
+//   - oauthCredentials: renewed tokens and expiry
+//   - error: request or response error
+func (p *Provider) renewToken(ctx context.Context, creds oauthCredentials) (oauthCredentials, error) {
+	body, err := json.Marshal(map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": creds.RefreshToken,
+		"client_id":     p.clientID,
+	})
+	// Check for encoding errors
 	if err != nil {
-		// Return empty on error
-		return "", err
+		// Return encoding error
+		return oauthCredentials{}, err
 	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, bytes.NewReader(body))
+	// Check for request errors
+	if err != nil {
+		// Return request error
+		return oauthCredentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
 
-	// Build credentials path
-	credPath := filepath.Join(home, claudeConfigDir, credentialsFileName)
-	// Read credentials file
-	data, err := os.ReadFile(credPath)
-	// Check if read failed
+	resp, err := p.client.Do(req)
+	// Check for network errors
 	if err != nil {
-		// Return empty on error
-		return "", err
+		p.trace.Record(model.TraceRequest, p.tokenURL, err)
+		// Return network error
+		return oauthCredentials{}, err
+	}
+	defer resp.Body.Close()
+	// Reject error statuses
+	if resp.StatusCode != http.StatusOK {
+		statusErr := newStatusError(resp)
+		p.trace.Record(model.TraceRequest, p.tokenURL, statusErr)
+		// Return status error
+		return oauthCredentials{}, statusErr
 	}
 
-	// Parse JSON credentials
-	var creds credentialsFile
-	// Check if parsing failed
-	if err := json.Unmarshal(data, &creds); err != nil {
-		// Return empty on error
-		return "", err
+	var token tokenResponse
+	// Parse the token response
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil || token.AccessToken == "" {
+		err = fmt.Errorf("%w: no access token", errBadResponse)
+		p.trace.Record(model.TraceRequest, p.tokenURL, err)
+		// Return parse error
+		return oauthCredentials{}, err
 	}
+	p.trace.Record(model.TraceRequest, p.tokenURL, nil)
 
-	// Return access token
-	return strings.TrimSpace(creds.ClaudeAiOauth.AccessToken), nil
+	// Keep the previous expiry unless the server sends one: a zero expiry never expires
+	renewed := oauthCredentials{AccessToken: token.AccessToken, RefreshToken: token.RefreshToken, ExpiresAt: creds.ExpiresAt}
+	// Keep the refresh token when the server does not rotate it
+	if renewed.RefreshToken == "" {
+		renewed.RefreshToken = creds.RefreshToken
+	}
+	// Record the new expiry
+	if token.ExpiresIn > 0 {
+		renewed.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second).UnixMilli()
+	}
+	// Return renewed tokens
+	return renewed, nil
 }
@@ -1,9 +1,15 @@
 package usage_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/florent/status-line/internal/adapter/usage"
+	"github.com/florent/status-line/internal/domain/model"
 )
 
 func TestNewProvider(t *testing.T) {
@@ -14,7 +20,7 @@ func TestNewProvider(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := usage.NewProvider()
+			p := usage.NewProvider(model.DefaultConfig().Usage)
 			if p == nil {
 				t.Error("NewProvider() returned nil")
 			}
@@ -31,9 +37,9 @@ func TestProvider_Usage(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := usage.NewProvider()
+			p := usage.NewProvider(model.DefaultConfig().Usage)
 			// API call may succeed or fail depending on environment
-			result, err := p.Usage()
+			result, err := p.Usage(context.Background())
 			// Verify both success and error paths work correctly
 			if err != nil {
 				// Error path: verify result is zero value
@@ -53,3 +59,40 @@ func TestProvider_Usage(t *testing.T) {
 		})
 	}
 }
+
+func TestProvider_Usage_BaseURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantWeekly int
+	}{
+		{name: "stub server", body: `{"five_hour":{"utilization":12,"resets_at":"2026-01-01T12:00:00Z"},"seven_day":{"utilization":34,"resets_at":"2026-01-05T00:00:00Z"}}`, wantWeekly: 34},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(credDir, ".credentials.json"), []byte(`{"claudeAiOauth":{"accessToken":"token"}}`), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/gateway/api/oauth/usage" || r.Header.Get("Authorization") != "Bearer token" {
+					t.Errorf("request = %s %v", r.URL.Path, r.Header)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			cfg := model.DefaultConfig().Usage
+			cfg.BaseURL = server.URL + "/gateway/"
+			cfg.CacheTTL = 0
+			cfg.Credentials = model.CredentialConfig{Sources: []string{model.CredentialFile}, ConfigDir: credDir}
+			got, err := usage.NewProvider(cfg).Usage(context.Background())
+			if err != nil {
+				t.Fatalf("Usage() error = %v", err)
+			}
+			if got.Weekly.Utilization != tt.wantWeekly {
+				t.Errorf("Usage().Weekly = %+v, want %d%%", got.Weekly, tt.wantWeekly)
+			}
+		})
+	}
+}
@@ -1,76 +1,263 @@
 package usage
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
 )
 
+// stubSource returns fixed credentials.
+type stubSource struct {
+	name string
+	data string
+	err  error
+}
+
+// Name returns the stub name.
+func (s stubSource) Name() string { return s.name }
+
+// Read returns the stub credentials.
+func (s stubSource) Read(context.Context, *model.Trace) ([]byte, error) {
+	return []byte(s.data), s.err
+}
+
+// Write refuses writes.
+func (stubSource) Write(context.Context, *model.Trace, []byte) error { return errReadOnly }
+
 func TestProvider_getToken(t *testing.T) {
 	tests := []struct {
-		name      string
-		wantError bool
+		name       string
+		sources    []CredentialSource
+		wantToken  string
+		wantErr    string
+		wantAbsent string
 	}{
-		{name: "attempts to retrieve token", wantError: false},
-		{name: "handles missing credentials gracefully", wantError: true},
+		{
+			name:      "first source wins",
+			sources:   []CredentialSource{stubSource{name: "env", data: "env-token"}, stubSource{name: "file", data: `{"claudeAiOauth":{"accessToken":"file-token"}}`}},
+			wantToken: "env-token",
+		},
+		{
+			name:      "falls through failures and skips",
+			sources:   []CredentialSource{stubSource{name: "command", err: errSkipped}, stubSource{name: "env", err: errNotSet}, stubSource{name: "file", data: `{"claudeAiOauth":{"accessToken":" file-token\n"}}`}},
+			wantToken: "file-token",
+		},
+		{
+			name:      "json after whitespace and byte order mark",
+			sources:   []CredentialSource{stubSource{name: "file", data: "\ufeff \n\t{\"claudeAiOauth\":{\"accessToken\":\"file-token\"}}\n"}},
+			wantToken: "file-token",
+		},
+		{
+			name:      "bare token after byte order mark",
+			sources:   []CredentialSource{stubSource{name: "command", data: "\ufeffcmd-token\n"}},
+			wantToken: "cmd-token",
+		},
+		{
+			name:    "multi-line output is rejected",
+			sources: []CredentialSource{stubSource{name: "command", data: "token\nsecond line"}},
+			wantErr: "command: malformed access token",
+		},
+		{
+			name:    "text with spaces is rejected",
+			sources: []CredentialSource{stubSource{name: "command", data: "error: not logged in"}},
+			wantErr: "command: malformed access token",
+		},
+		{
+			name:    "json token with spaces is rejected",
+			sources: []CredentialSource{stubSource{name: "file", data: `{"claudeAiOauth":{"accessToken":"two words"}}`}},
+			wantErr: "file: malformed access token",
+		},
+		{
+			name:    "empty credentials",
+			sources: []CredentialSource{stubSource{name: "file", data: `{"claudeAiOauth":{}}`}},
+			wantErr: "file: no access token",
+		},
+		{
+			name:       "skipped sources are not reported",
+			sources:    []CredentialSource{stubSource{name: "keychain", err: errSkipped}},
+			wantErr:    "no OAuth credentials found",
+			wantAbsent: "keychain",
+		},
+		{name: "no sources", sources: nil, wantErr: "no OAuth credentials found"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := NewProvider()
-			// Token retrieval may fail in test environment
-			token, err := p.getToken()
-			// Verify function handles errors gracefully
-			if tt.wantError && err == nil && token != "" {
+			p := NewProvider(model.DefaultConfig().Usage)
+			p.SetCredentialSources(tt.sources)
+			token, err := p.getToken(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !errors.Is(err, errNoCredentials) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("getToken() error = %v, want %q", err, tt.wantErr)
+				}
+				if tt.wantAbsent != "" && strings.Contains(err.Error(), tt.wantAbsent) {
+					t.Errorf("getToken() error = %v, want no %q", err, tt.wantAbsent)
+				}
 				return
 			}
-			// Success case - just verify no panic occurred
-			if !tt.wantError && token == "" && err != nil {
-				return
+			if err != nil || token != tt.wantToken {
+				t.Errorf("getToken() = %q, %v, want %q", token, err, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestNewCredentialSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []string
+	}{
+		{name: "default order", sources: model.DefaultConfig().Usage.Credentials.Sources},
+		{name: "every source", sources: model.CredentialSources()},
+		{name: "custom order", sources: []string{model.CredentialFile, model.CredentialEnv}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewCredentialSources(model.CredentialConfig{Sources: tt.sources})
+			if len(got) != len(tt.sources) {
+				t.Fatalf("NewCredentialSources() = %d sources, want %d", len(got), len(tt.sources))
+			}
+			for i, source := range got {
+				if source.Name() != tt.sources[i] {
+					t.Errorf("source[%d] = %q, want %q", i, source.Name(), tt.sources[i])
+				}
 			}
 		})
 	}
 }
 
-func TestProvider_getTokenFromKeychain(t *testing.T) {
+func TestEnvSource_Read(t *testing.T) {
 	tests := []struct {
 		name      string
-		wantError bool
+		env       map[string]string
+		wantToken string
+		wantErr   bool
 	}{
-		{name: "attempts to read from keychain", wantError: false},
-		{name: "handles keychain unavailable", wantError: runtime.GOOS != "darwin"},
+		{name: "status line token first", env: map[string]string{envStatuslineToken: "mine", envClaudeCodeToken: "claude"}, wantToken: "mine"},
+		{name: "claude code token", env: map[string]string{envClaudeCodeToken: " claude "}, wantToken: "claude"},
+		{name: "unset", env: nil, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := NewProvider()
-			// Keychain may not have credentials or may not be available
-			token, err := p.getTokenFromKeychain()
-			// Verify error handling on non-macOS
-			if tt.wantError && err == nil && token != "" {
-				t.Error("getTokenFromKeychain() expected error on non-macOS")
+			t.Setenv(envStatuslineToken, "")
+			t.Setenv(envClaudeCodeToken, "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			trace := &model.Trace{}
+			got, err := envSource{vars: []string{envStatuslineToken, envClaudeCodeToken}}.Read(context.Background(), trace)
+			if (err != nil) != tt.wantErr || string(got) != tt.wantToken {
+				t.Errorf("Read() = %q, %v, want %q", got, err, tt.wantToken)
+			}
+			if len(trace.Steps) == 0 || trace.Steps[0].Kind != model.TraceEnv {
+				t.Errorf("Read() trace = %+v, want env steps", trace.Steps)
 			}
 		})
 	}
 }
 
-func TestProvider_getTokenFromFile(t *testing.T) {
+func TestCommandSource_Read(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
 	tests := []struct {
 		name      string
-		wantError bool
+		command   string
+		wantToken string
+		wantErr   error
 	}{
-		{name: "attempts to read credentials file", wantError: false},
-		{name: "handles missing file gracefully", wantError: true},
+		{name: "prints token", command: "echo token-from-helper", wantToken: "token-from-helper"},
+		{name: "not configured", command: "", wantErr: errSkipped},
+		{name: "fails", command: "exit 3"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := NewProvider()
-			// File may not exist in test environment
-			token, err := p.getTokenFromFile()
-			// Verify function handles errors gracefully
-			if tt.wantError && err == nil && token != "" {
+			got, err := commandSource{command: tt.command}.Read(context.Background(), nil)
+			if tt.wantToken != "" {
+				if err != nil || string(got) != tt.wantToken {
+					t.Errorf("Read() = %q, %v, want %q", got, err, tt.wantToken)
+				}
 				return
 			}
-			// Success case - just verify no panic occurred
-			if !tt.wantError && token == "" && err != nil {
-				return
+			if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("Read() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeychainSource_Read(t *testing.T) {
+	tests := []struct {
+		name string
+	}{
+		{name: "skipped outside macOS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if runtime.GOOS == "darwin" {
+				t.Skip("reads the real keychain")
+			}
+			if _, err := (keychainSource{service: "Claude Code-credentials"}).Read(context.Background(), nil); !errors.Is(err, errSkipped) {
+				t.Errorf("Read() error = %v, want errSkipped", err)
+			}
+		})
+	}
+}
+
+func TestFileSource_path(t *testing.T) {
+	home := t.TempDir()
+	tests := []struct {
+		name      string
+		dir       string
+		configDir string
+		want      string
+	}{
+		{name: "home default", want: filepath.Join(home, ".claude", ".credentials.json")},
+		{name: "CLAUDE_CONFIG_DIR", configDir: "/srv/claude", want: filepath.Join("/srv/claude", ".credentials.json")},
+		{name: "configured directory wins", dir: "/opt/claude", configDir: "/srv/claude", want: filepath.Join("/opt/claude", ".credentials.json")},
+		{name: "tilde expansion", dir: "~/.claude-work", want: filepath.Join(home, ".claude-work", ".credentials.json")},
+		{name: "tilde user left alone", dir: "~other/claude", want: filepath.Join("~other/claude", ".credentials.json")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", home)
+			t.Setenv(envClaudeConfigDir, tt.configDir)
+			got, err := fileSource{dir: tt.dir}.path()
+			if err != nil || got != tt.want {
+				t.Errorf("path() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileSource_ReadWrite(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "round trip", data: `{"claudeAiOauth":{"accessToken":"token"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := fileSource{dir: t.TempDir()}
+			if _, err := source.Read(context.Background(), nil); !os.IsNotExist(err) {
+				t.Errorf("Read() error = %v, want not exist", err)
+			}
+			if err := source.Write(context.Background(), nil, []byte(tt.data)); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			got, err := source.Read(context.Background(), nil)
+			if err != nil || string(got) != tt.data {
+				t.Errorf("Read() = %q, %v, want %q", got, err, tt.data)
+			}
+			path, _ := source.path()
+			if info, err := os.Stat(path); err != nil || info.Mode().Perm() != cacheFilePerm {
+				t.Errorf("Write() mode = %v, %v, want %v", info.Mode().Perm(), err, cacheFilePerm)
 			}
 		})
 	}
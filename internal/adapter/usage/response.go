@@ -1,6 +1,23 @@
 // Package usage provides the Anthropic API usage adapter.
 package usage
 
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Response field names.
+const (
+	// fiveHourField is the session window, decoded into usageResponse.
+	fiveHourField string = "five_hour"
+	// sevenDayField is the weekly window, decoded into usageResponse.
+	sevenDayField string = "seven_day"
+)
+
 // usageResponse represents the API response structure.
 // It contains both five-hour session and seven-day weekly usage periods.
 type usageResponse struct {
@@ -14,3 +31,96 @@ type usagePeriod struct {
 	Utilization float64 `json:"utilization"`
 	ResetsAt    string  `json:"resets_at"`
 }
+
+// windowPeriod represents any other usage window from the API.
+// Fields are pointers because the API reports null for windows that do not
+// apply to the account; extra usage also reports whether it is enabled.
+type windowPeriod struct {
+	Utilization *float64 `json:"utilization"`
+	ResetsAt    *string  `json:"resets_at"`
+	IsEnabled   *bool    `json:"is_enabled"`
+}
+
+// tokenResponse represents the OAuth token endpoint response.
+// The refresh token is only present when the server rotates it.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// parseWindows extracts the named windows besides session and weekly.
+// Fields that are not windows, windows without utilization and disabled
+// extra usage are left out.
+//
+// Params:
+//   - body: usage API response
+//
+// Returns:
+//   - model.UsageWindows: windows sorted by name, nil when there are none
+func parseWindows(body []byte) model.UsageWindows {
+	var fields map[string]json.RawMessage
+	// Ignore bodies that are not objects
+	if err := json.Unmarshal(body, &fields); err != nil {
+		// Return no windows
+		return nil
+	}
+	var windows model.UsageWindows
+	// Decode every other field as a window
+	for name, raw := range fields {
+		// Session and weekly have their own fields
+		if name == fiveHourField || name == sevenDayField {
+			continue
+		}
+		var period windowPeriod
+		// Skip values that are not windows
+		if err := json.Unmarshal(raw, &period); err != nil || period.Utilization == nil {
+			continue
+		}
+		// Skip extra usage that is turned off
+		if period.IsEnabled != nil && !*period.IsEnabled {
+			continue
+		}
+		windows = append(windows, model.UsageWindow{Name: name, Usage: period.usage(name)})
+	}
+	slices.SortFunc(windows, func(a, b model.UsageWindow) int {
+		// Order by name
+		return strings.Compare(a.Name, b.Name)
+	})
+	// Return windows
+	return windows
+}
+
+// usage converts the window, taking its length from its name.
+//
+// Params:
+//   - name: window name, "seven_day_*" or "five_hour_*" for known lengths
+//
+// Returns:
+//   - model.Usage: utilization and reset time, without a cursor for unknown lengths
+func (w windowPeriod) usage(name string) model.Usage {
+	var resetsAt time.Time
+	// Parse the reset time when reported
+	if w.ResetsAt != nil {
+		// Keep the zero time for malformed values
+		if t, err := time.Parse(time.RFC3339, *w.ResetsAt); err == nil {
+			resetsAt = t
+		}
+	}
+	utilization := int(*w.Utilization)
+	// Select the window length
+	switch {
+	// Weekly windows
+	case strings.HasPrefix(name, sevenDayField):
+		// Return weekly usage
+		return model.NewWeeklyUsage(utilization, resetsAt)
+	// Session windows
+	case strings.HasPrefix(name, fiveHourField):
+		// Return session usage
+		return model.NewSessionUsage(utilization, resetsAt)
+	// Unknown length, no burn-rate cursor
+	default:
+		// Return usage without a window length
+		return model.NewUsage(utilization, resetsAt, 0)
+	}
+}
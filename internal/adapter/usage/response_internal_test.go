@@ -0,0 +1,44 @@
+package usage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWindows(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantNames []string
+		wantUsage map[string]int
+	}{
+		{
+			name:      "model buckets and extra usage",
+			body:      `{"five_hour":{"utilization":10,"resets_at":"2026-01-01T12:00:00Z"},"seven_day":{"utilization":30,"resets_at":"2026-01-05T00:00:00Z"},"seven_day_sonnet":{"utilization":12.7,"resets_at":"2026-01-05T00:00:00Z"},"seven_day_opus":{"utilization":45,"resets_at":null},"extra_usage":{"is_enabled":true,"utilization":8,"used_credits":4.2}}`,
+			wantNames: []string{"extra_usage", "seven_day_opus", "seven_day_sonnet"},
+			wantUsage: map[string]int{"extra_usage": 8, "seven_day_opus": 45, "seven_day_sonnet": 12},
+		},
+		{
+			name:      "null and disabled windows",
+			body:      `{"seven_day_oauth_apps":null,"seven_day_opus":{"utilization":null,"resets_at":null},"extra_usage":{"is_enabled":false,"utilization":null},"unrelated":3}`,
+			wantNames: nil,
+		},
+		{name: "not an object", body: `[]`, wantNames: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseWindows([]byte(tt.body))
+			if len(got) != len(tt.wantNames) {
+				t.Fatalf("parseWindows() = %+v, want %v", got, tt.wantNames)
+			}
+			for i, w := range got {
+				if w.Name != tt.wantNames[i] || w.Usage.Utilization != tt.wantUsage[w.Name] {
+					t.Errorf("window[%d] = %s %d%%, want %s %d%%", i, w.Name, w.Usage.Utilization, tt.wantNames[i], tt.wantUsage[tt.wantNames[i]])
+				}
+			}
+			if w, ok := got.Find("seven_day_sonnet"); ok && (!w.Usage.IsValid() || w.Usage.WindowDuration != 7*24*time.Hour) {
+				t.Errorf("seven_day_sonnet = %+v, want a weekly window with reset time", w.Usage)
+			}
+		})
+	}
+}
@@ -0,0 +1,401 @@
+// Package usage provides the Anthropic API usage adapter.
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/florent/status-line/internal/adapter/atomicfile"
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Credential source constants.
+const (
+	// securityCommand is the macOS keychain command line tool.
+	securityCommand string = "security"
+	// secretToolCommand is the libsecret command line tool.
+	secretToolCommand string = "secret-tool"
+	// secretAttribute is the secret store attribute naming the item.
+	secretAttribute string = "service"
+	// envStatuslineToken holds a token meant for the status line only.
+	envStatuslineToken string = "STATUSLINE_OAUTH_TOKEN"
+	// envClaudeCodeToken holds the long-lived token of `claude setup-token`.
+	envClaudeCodeToken string = "CLAUDE_CODE_OAUTH_TOKEN"
+	// envClaudeConfigDir moves the Claude configuration directory.
+	envClaudeConfigDir string = "CLAUDE_CONFIG_DIR"
+)
+
+// Credential source errors.
+var (
+	// errSkipped reports a source that does not apply here (not configured, other OS).
+	errSkipped error = errors.New("credential source not available")
+	// errReadOnly reports a source renewed tokens cannot be written back to.
+	errReadOnly error = errors.New("credential source is read-only")
+	// errNotSet reports an unset environment variable.
+	errNotSet error = fmt.Errorf("variable not set: %w", fs.ErrNotExist)
+)
+
+// CredentialSource is one place the OAuth credentials can come from.
+// The provider tries its sources in order and writes renewed tokens back to
+// the source they were read from.
+type CredentialSource interface {
+	// Name returns the configuration name of the source.
+	Name() string
+	// Read returns the credentials JSON or a bare access token.
+	Read(ctx context.Context, trace *model.Trace) ([]byte, error)
+	// Write replaces the stored credentials JSON.
+	Write(ctx context.Context, trace *model.Trace, data []byte) error
+}
+
+// NewCredentialSources creates the sources listed in the credential options, in order.
+//
+// Params:
+//   - cfg: credential options (source order, directory, item name, command)
+//
+// Returns:
+//   - []CredentialSource: sources to try in order
+func NewCredentialSources(cfg model.CredentialConfig) []CredentialSource {
+	sources := make([]CredentialSource, 0, len(cfg.Sources))
+	// Create each listed source
+	for _, name := range cfg.Sources {
+		// Select implementation
+		switch name {
+		// Environment variables
+		case model.CredentialEnv:
+			sources = append(sources, envSource{vars: []string{envStatuslineToken, envClaudeCodeToken}})
+		// Token command
+		case model.CredentialCommand:
+			sources = append(sources, commandSource{command: cfg.TokenCommand})
+		// macOS keychain
+		case model.CredentialKeychain:
+			sources = append(sources, keychainSource{service: cfg.KeychainService})
+		// Linux secret store
+		case model.CredentialSecretService:
+			sources = append(sources, secretServiceSource{service: cfg.KeychainService})
+		// Credentials file
+		case model.CredentialFile:
+			sources = append(sources, fileSource{dir: cfg.ConfigDir})
+		}
+	}
+	// Return sources in configuration order
+	return sources
+}
+
+// envSource reads a bare token from the first set environment variable.
+type envSource struct {
+	vars []string
+}
+
+// Name returns the configuration name of the source.
+//
+// Returns:
+//   - string: source name
+func (envSource) Name() string {
+	// Return name
+	return model.CredentialEnv
+}
+
+// Read returns the value of the first set variable.
+//
+// Params:
+//   - ctx: unused, variables are read at once
+//   - trace: records each variable looked up
+//
+// Returns:
+//   - []byte: bare access token
+//   - error: errNotSet when no variable is set
+func (s envSource) Read(_ context.Context, trace *model.Trace) ([]byte, error) {
+	// Try each variable in order
+	for _, name := range s.vars {
+		// Use the first non-blank value
+		if val := strings.TrimSpace(os.Getenv(name)); val != "" {
+			trace.Record(model.TraceEnv, name, nil)
+			// Return token
+			return []byte(val), nil
+		}
+		trace.Record(model.TraceEnv, name, errNotSet)
+	}
+	// Return unset error
+	return nil, errNotSet
+}
+
+// Write refuses to change environment variables.
+//
+// Params:
+//   - ctx: unused
+//   - trace: unused
+//   - data: unused
+//
+// Returns:
+//   - error: errReadOnly
+func (envSource) Write(context.Context, *model.Trace, []byte) error {
+	// Return read-only error
+	return errReadOnly
+}
+
+// commandSource runs a command whose standard output is the token, like a
+// git credential helper.
+type commandSource struct {
+	command string
+}
+
+// Name returns the configuration name of the source.
+//
+// Returns:
+//   - string: source name
+func (commandSource) Name() string {
+	// Return name
+	return model.CredentialCommand
+}
+
+// Read runs the command through the shell.
+//
+// Params:
+//   - ctx: kills the command when the deadline passes
+//   - trace: records the command
+//
+// Returns:
+//   - []byte: command output, a bare token or credentials JSON
+//   - error: errSkipped without a command, or the command error
+func (s commandSource) Read(ctx context.Context, trace *model.Trace) ([]byte, error) {
+	// Nothing to run
+	if s.command == "" {
+		// Return skipped
+		return nil, errSkipped
+	}
+	shell, flag := "sh", "-c"
+	// Windows has no sh
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	output, err := exec.CommandContext(ctx, shell, flag, s.command).Output()
+	trace.Record(model.TraceCommand, s.command, err)
+	// Return output without the trailing newline
+	return bytes.TrimSpace(output), err
+}
+
+// Write refuses to feed tokens back to the command.
+//
+// Params:
+//   - ctx: unused
+//   - trace: unused
+//   - data: unused
+//
+// Returns:
+//   - error: errReadOnly
+func (commandSource) Write(context.Context, *model.Trace, []byte) error {
+	// Return read-only error
+	return errReadOnly
+}
+
+// keychainSource keeps credentials in a macOS keychain item.
+type keychainSource struct {
+	service string
+}
+
+// Name returns the configuration name of the source.
+//
+// Returns:
+//   - string: source name
+func (keychainSource) Name() string {
+	// Return name
+	return model.CredentialKeychain
+}
+
+// Read returns the keychain item password.
+//
+// Params:
+//   - ctx: kills the security command when the deadline passes
+//   - trace: records the command
+//
+// Returns:
+//   - []byte: credentials JSON
+//   - error: errSkipped outside macOS, or the command error
+func (s keychainSource) Read(ctx context.Context, trace *model.Trace) ([]byte, error) {
+	// The keychain only exists on macOS
+	if runtime.GOOS != "darwin" {
+		// Return skipped
+		return nil, errSkipped
+	}
+	cmd := exec.CommandContext(ctx, securityCommand, "find-generic-password", "-s", s.service, "-w")
+	output, err := cmd.Output()
+	trace.Record(model.TraceCommand, strings.Join(cmd.Args, " "), err)
+	// Return password without the trailing newline
+	return bytes.TrimSpace(output), err
+}
+
+// Write replaces the keychain item password.
+// The password goes through stdin in hex, so it never shows in the process list.
+//
+// Params:
+//   - ctx: kills the security command when the deadline passes
+//   - trace: records the command, without the password
+//   - data: credentials JSON
+//
+// Returns:
+//   - error: command error if any
+func (s keychainSource) Write(ctx context.Context, trace *model.Trace, data []byte) error {
+	account := os.Getenv("USER")
+	// Fall back to the account database
+	if account == "" {
+		// Look up the current user
+		if u, err := user.Current(); err == nil {
+			account = u.Username
+		}
+	}
+	cmd := exec.CommandContext(ctx, securityCommand, "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -a %q -s %q -X %s\n", account, s.service, hex.EncodeToString(data)))
+	err := cmd.Run()
+	trace.Record(model.TraceCommand, securityCommand+" add-generic-password -U -s "+s.service, err)
+	// Return command result
+	return err
+}
+
+// secretServiceSource keeps credentials in the freedesktop secret store
+// (GNOME Keyring, KWallet) through secret-tool.
+type secretServiceSource struct {
+	service string
+}
+
+// Name returns the configuration name of the source.
+//
+// Returns:
+//   - string: source name
+func (secretServiceSource) Name() string {
+	// Return name
+	return model.CredentialSecretService
+}
+
+// Read looks the item up by its service attribute.
+//
+// Params:
+//   - ctx: kills secret-tool when the deadline passes
+//   - trace: records the command
+//
+// Returns:
+//   - []byte: credentials JSON or bare token
+//   - error: command error if any
+func (s secretServiceSource) Read(ctx context.Context, trace *model.Trace) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, secretToolCommand, "lookup", secretAttribute, s.service)
+	output, err := cmd.Output()
+	trace.Record(model.TraceCommand, strings.Join(cmd.Args, " "), err)
+	// Return secret without the trailing newline
+	return bytes.TrimSpace(output), err
+}
+
+// Write replaces the item, passing the secret through stdin.
+//
+// Params:
+//   - ctx: kills secret-tool when the deadline passes
+//   - trace: records the command, without the secret
+//   - data: credentials JSON
+//
+// Returns:
+//   - error: command error if any
+func (s secretServiceSource) Write(ctx context.Context, trace *model.Trace, data []byte) error {
+	cmd := exec.CommandContext(ctx, secretToolCommand, "store", "--label="+s.service, secretAttribute, s.service)
+	cmd.Stdin = bytes.NewReader(data)
+	err := cmd.Run()
+	trace.Record(model.TraceCommand, strings.Join(cmd.Args, " "), err)
+	// Return command result
+	return err
+}
+
+// fileSource keeps credentials in .credentials.json of the Claude
+// configuration directory.
+type fileSource struct {
+	dir string
+}
+
+// Name returns the configuration name of the source.
+//
+// Returns:
+//   - string: source name
+func (fileSource) Name() string {
+	// Return name
+	return model.CredentialFile
+}
+
+// path resolves the credentials file: the configured directory, then
+// CLAUDE_CONFIG_DIR, then ~/.claude.
+//
+// Returns:
+//   - string: credentials file path
+//   - error: home directory lookup error
+func (s fileSource) path() (string, error) {
+	dir := s.dir
+	// Follow Claude Code's directory override
+	if dir == "" {
+		dir = os.Getenv(envClaudeConfigDir)
+	}
+	// Default to the home directory
+	if dir == "" {
+		dir = filepath.Join("~", claudeConfigDir)
+	}
+	// Expand the home directory
+	if rest, ok := strings.CutPrefix(dir, "~"); ok && (rest == "" || os.IsPathSeparator(rest[0])) {
+		home, err := os.UserHomeDir()
+		// Check if home lookup failed
+		if err != nil {
+			// Return lookup error
+			return "", err
+		}
+		dir = filepath.Join(home, rest)
+	}
+	// Return file inside the directory
+	return filepath.Join(dir, credentialsFileName), nil
+}
+
+// Read returns the file content.
+//
+// Params:
+//   - ctx: unused, files are read at once
+//   - trace: records the file read
+//
+// Returns:
+//   - []byte: credentials JSON
+//   - error: read error if any
+func (s fileSource) Read(_ context.Context, trace *model.Trace) ([]byte, error) {
+	path, err := s.path()
+	// Check if the path is unresolvable
+	if err != nil {
+		// Return lookup error
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	trace.Record(model.TraceFile, path, err)
+	// Return file content
+	return data, err
+}
+
+// Write replaces the file atomically, so Claude Code never reads a partial file.
+//
+// Params:
+//   - ctx: unused, files are written at once
+//   - trace: records the file write
+//   - data: credentials JSON
+//
+// Returns:
+//   - error: write error if any
+func (s fileSource) Write(_ context.Context, trace *model.Trace, data []byte) error {
+	path, err := s.path()
+	// Check if the path is unresolvable
+	if err != nil {
+		// Return lookup error
+		return err
+	}
+	err = atomicfile.Write(path, data, cacheFilePerm)
+	trace.Record(model.TraceFile, path, err)
+	// Return write result
+	return err
+}
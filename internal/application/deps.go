@@ -5,6 +5,8 @@ import "github.com/florent/status-line/internal/domain/port"
 
 // ServiceDeps bundles dependencies for StatusLineService.
 // It groups providers together to reduce constructor parameters.
+// History is optional: without it no usage history is kept. Transcript is
+// optional: without it the context bar relies on the input alone.
 type ServiceDeps struct {
 	Git         port.GitRepository
 	System      port.SystemProvider
@@ -12,4 +14,6 @@ type ServiceDeps struct {
 	MCP         port.MCPProvider
 	Taskwarrior port.TaskwarriorProvider
 	Usage       port.UsageProvider
+	History     port.UsageHistory
+	Transcript  port.TranscriptReader
 }
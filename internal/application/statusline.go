@@ -2,6 +2,7 @@
 package application
 
 import (
+	"context"
 	"time"
 
 	"github.com/florent/status-line/internal/domain/model"
@@ -14,6 +15,7 @@ const timeFormat string = "15:04:05"
 // StatusLineService orchestrates the status line generation.
 // It coordinates between adapters and the renderer to produce output.
 type StatusLineService struct {
+	config   model.Config
 	deps     ServiceDeps
 	renderer port.Renderer
 }
@@ -21,14 +23,16 @@ type StatusLineService struct {
 // NewStatusLineService creates a new status line service.
 //
 // Params:
+//   - cfg: user configuration (enabled segments decide which providers run)
 //   - deps: bundled provider dependencies
 //   - renderer: status line renderer
 //
 // Returns:
 //   - *StatusLineService: configured service instance
-func NewStatusLineService(deps ServiceDeps, renderer port.Renderer) *StatusLineService {
+func NewStatusLineService(cfg model.Config, deps ServiceDeps, renderer port.Renderer) *StatusLineService {
 	// Return service with all dependencies injected
 	return &StatusLineService{
+		config:   cfg,
 		deps:     deps,
 		renderer: renderer,
 	}
@@ -37,51 +41,153 @@ func NewStatusLineService(deps ServiceDeps, renderer port.Renderer) *StatusLineS
 // Generate creates the status line string from input.
 //
 // Params:
+//   - ctx: cancels data collection
 //   - input: input provider for status line data
 //
 // Returns:
 //   - string: formatted status line ready for output
-func (s *StatusLineService) Generate(input port.InputProvider) string {
+func (s *StatusLineService) Generate(ctx context.Context, input port.InputProvider) string {
 	// Generate without update info
-	return s.GenerateWithUpdate(input, model.UpdateInfo{})
+	return s.GenerateWithUpdate(ctx, input, model.UpdateInfo{})
 }
 
 // GenerateWithUpdate creates the status line string with update notification.
+// Providers whose segments are disabled in the configuration are not called.
+// The others run concurrently, each under its own timeout and all under the
+// render budget; a provider that misses its deadline leaves its data empty
+// and is listed in StatusLineData.Late.
 //
 // Params:
+//   - ctx: cancels data collection
 //   - input: input provider for status line data
 //   - update: update information to display
 //
 // Returns:
 //   - string: formatted status line ready for output
-func (s *StatusLineService) GenerateWithUpdate(input port.InputProvider, update model.UpdateInfo) string {
-	// Fetch usage data (ignore error, use zero value on failure)
-	usageData, _ := s.deps.Usage.Usage()
+func (s *StatusLineService) GenerateWithUpdate(ctx context.Context, input port.InputProvider, update model.UpdateInfo) string {
+	cfg := s.config
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeouts.Render.Std())
+	defer cancel()
 
-	// Determine progress: prefer session API (real rate limit), fallback to context window
-	progress := input.Progress()
-	if usageData.Session.IsValid() {
-		progress = usageData.Session.Progress()
+	// Start providers of enabled segments
+	var usageTask *task[model.UsageData]
+	// Fetch usage data only when a segment displays it (ignore error, use zero value on failure)
+	if cfg.SegmentEnabled(model.SegmentModel) || cfg.SegmentEnabled(model.SegmentWeekly) || cfg.SegmentEnabled(model.SegmentSparkline) {
+		usageTask = startTask(ctx, model.ProviderUsage, cfg.Usage.Timeout.Std(), func(ctx context.Context) model.UsageData {
+			data, _ := s.deps.Usage.Usage(ctx)
+			// Return usage data
+			return data
+		})
+	}
+	var historyTask *task[model.UsageHistory]
+	// Read the usage history for the sparkline, bounded by the render budget only
+	if cfg.SegmentEnabled(model.SegmentSparkline) && s.deps.History != nil {
+		historyTask = startTask(ctx, model.ProviderHistory, cfg.Timeouts.Render.Std(), func(ctx context.Context) model.UsageHistory {
+			history, _ := s.deps.History.History(ctx, time.Now().Add(-cfg.History.SparklineSpan.Std()))
+			// Return recent samples
+			return history
+		})
+	}
+	var transcriptTask *task[model.TranscriptStats]
+	// Read the transcript for the context bar and the activity when Claude Code names one
+	if session := input.Session(); (cfg.SegmentEnabled(model.SegmentModel) || cfg.SegmentEnabled(model.SegmentActivity)) && s.deps.Transcript != nil && session.TranscriptPath != "" {
+		transcriptTask = startTask(ctx, model.ProviderTranscript, cfg.Timeouts.Transcript.Std(), func(ctx context.Context) model.TranscriptStats {
+			stats, _ := s.deps.Transcript.Stats(ctx, session)
+			// Return summary read so far
+			return stats
+		})
+	}
+	var gitTask *task[model.GitStatus]
+	// Check if git segment is enabled
+	if cfg.SegmentEnabled(model.SegmentGit) {
+		gitTask = startTask(ctx, model.ProviderGit, cfg.Timeouts.Git.Std(), s.deps.Git.Status)
+	}
+	var changesTask *task[model.CodeChanges]
+	// Check if changes segment shows git counts
+	if cfg.SegmentEnabled(model.SegmentChanges) && cfg.Changes.ShowsGit() {
+		changesTask = startTask(ctx, model.ProviderGit, cfg.Timeouts.Git.Std(), s.gitChanges(cfg.Changes))
+	}
+	var mcpTask *task[model.MCPServers]
+	// Check if MCP segment is enabled
+	if cfg.SegmentEnabled(model.SegmentMCP) {
+		mcpTask = startTask(ctx, model.ProviderMCP, cfg.Timeouts.MCP.Std(), s.deps.MCP.Servers)
+	}
+	var tasksTask *task[model.TaskwarriorInfo]
+	// Check if Taskwarrior segment is enabled
+	if cfg.SegmentEnabled(model.SegmentTasks) {
+		tasksTask = startTask(ctx, model.ProviderTaskwarrior, cfg.Timeouts.Taskwarrior.Std(), s.deps.Taskwarrior.Info)
 	}
 
-	// Gather all data from various sources
+	// Gather data common to every layout while providers run
 	data := model.StatusLineData{
 		Model:       input.ModelInfo(),
-		Progress:    progress,
-		Session:     usageData.Session,
-		Usage:       usageData.Weekly,
-		Icons:       model.IconConfigFromEnv(),
-		Git:         s.deps.Git.Status(),
-		System:      s.deps.System.Info(),
+		SessionInfo: input.Session(),
+		Icons:       cfg.Icons,
 		Terminal:    s.deps.Terminal.Info(),
 		Dir:         input.WorkingDir(),
+		Cost:        input.SessionCost(),
 		Time:        time.Now().Format(timeFormat),
-		Changes:     s.deps.Git.DiffStats(),
-		MCP:         s.deps.MCP.Servers(),
-		Taskwarrior: s.deps.Taskwarrior.Info(),
 		Update:      update,
 	}
+	// Check if OS segment is enabled
+	if cfg.SegmentEnabled(model.SegmentOS) {
+		data.System = s.deps.System.Info()
+	}
+	// Check if changes segment shows the session counts
+	if cfg.SegmentEnabled(model.SegmentChanges) && cfg.Changes.ShowsSession() {
+		data.SessionChanges = input.CodeChanges()
+	}
+
+	// Collect provider results, late ones stay empty
+	usageData := usageTask.wait(&data.Late)
+	data.History = historyTask.wait(&data.Late)
+	data.Transcript = transcriptTask.wait(&data.Late)
+	data.Git = gitTask.wait(&data.Late)
+	data.Changes = changesTask.wait(&data.Late)
+	data.MCP = mcpTask.wait(&data.Late)
+	data.Taskwarrior = tasksTask.wait(&data.Late)
+
+	// Determine progress: prefer session API (real rate limit), fallback to context window
+	data.Progress = input.ContextProgress(data.Transcript)
+	if usageData.Session.IsValid() {
+		data.Progress = usageData.Session.Progress()
+	}
+	data.Session = usageData.Session
+	data.Usage = usageData.Weekly
+	data.UsageWindows = usageData.Windows
+	data.UsageStatus = usageData.Status
+
+	// Offer each API reading to the history ledger, which keeps it once
+	// however many renders serve it from the cache
+	if sample, ok := model.NewUsageSample(usageData); ok && s.deps.History != nil {
+		// Ignore write errors: the history must never hide the status line
+		_ = s.deps.History.Record(sample)
+		// Show the current reading unless the ledger already returned it
+		if historyTask != nil && (len(data.History) == 0 || data.History[len(data.History)-1].Time.Before(sample.Time)) {
+			data.History = append(data.History, sample)
+		}
+	}
 
 	// Delegate rendering to the renderer
 	return s.renderer.Render(data)
 }
+
+// gitChanges returns the git query of the configured changes source.
+//
+// Params:
+//   - cfg: changes options (source and base branch)
+//
+// Returns:
+//   - func(context.Context) model.CodeChanges: branch or worktree diff stats
+func (s *StatusLineService) gitChanges(cfg model.ChangesConfig) func(context.Context) model.CodeChanges {
+	// Compare against the base branch
+	if cfg.Source == model.ChangesSourceBranch {
+		// Return branch query
+		return func(ctx context.Context) model.CodeChanges {
+			// Return changes since the fork point
+			return s.deps.Git.BranchDiffStats(ctx, cfg.BaseBranch)
+		}
+	}
+	// Return worktree query
+	return s.deps.Git.DiffStats
+}
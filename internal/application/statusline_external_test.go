@@ -1,7 +1,11 @@
 package application_test
 
 import (
+	"context"
+	"encoding/json"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/florent/status-line/internal/application"
 	"github.com/florent/status-line/internal/domain/model"
@@ -9,8 +13,34 @@ import (
 
 type mockGitRepo struct{}
 
-func (m *mockGitRepo) Status() model.GitStatus      { return model.GitStatus{Branch: "main"} }
-func (m *mockGitRepo) DiffStats() model.CodeChanges { return model.CodeChanges{Added: 10, Removed: 5} }
+func (m *mockGitRepo) Status(context.Context) model.GitStatus { return model.GitStatus{Branch: "main"} }
+func (m *mockGitRepo) DiffStats(context.Context) model.CodeChanges {
+	return model.CodeChanges{Added: 10, Removed: 5}
+}
+func (m *mockGitRepo) BranchDiffStats(_ context.Context, base string) model.CodeChanges {
+	// Report the base through the counts so tests can check it was passed on
+	return model.CodeChanges{Added: 100 + len(base), Removed: 50}
+}
+
+// slowGitRepo answers after its delay or when the deadline passes.
+type slowGitRepo struct {
+	delay time.Duration
+}
+
+func (m *slowGitRepo) Status(ctx context.Context) model.GitStatus {
+	select {
+	case <-time.After(m.delay):
+		return model.GitStatus{Branch: "slow"}
+	case <-ctx.Done():
+		return model.GitStatus{}
+	}
+}
+func (m *slowGitRepo) DiffStats(context.Context) model.CodeChanges {
+	return model.CodeChanges{Added: 10, Removed: 5}
+}
+func (m *slowGitRepo) BranchDiffStats(context.Context, string) model.CodeChanges {
+	return model.CodeChanges{Added: 100, Removed: 50}
+}
 
 type mockSystemProv struct{}
 
@@ -22,29 +52,93 @@ func (m *mockTerminalProv) Info() model.TerminalInfo { return model.TerminalInfo
 
 type mockMCPProv struct{}
 
-func (m *mockMCPProv) Servers() model.MCPServers { return model.MCPServers{} }
+func (m *mockMCPProv) Servers(context.Context) model.MCPServers { return model.MCPServers{} }
 
 type mockTaskwarriorProv struct{}
 
-func (m *mockTaskwarriorProv) Info() model.TaskwarriorInfo {
+func (m *mockTaskwarriorProv) Info(context.Context) model.TaskwarriorInfo {
 	return model.TaskwarriorInfo{Installed: false}
 }
 
 type mockUsageProv struct{}
 
-func (m *mockUsageProv) Usage() (model.UsageData, error) {
+func (m *mockUsageProv) Usage(context.Context) (model.UsageData, error) {
 	return model.UsageData{}, nil
 }
 
+// freshUsageProv reports usage fetched at a fixed time.
+type freshUsageProv struct {
+	fetchedAt time.Time
+}
+
+func (m *freshUsageProv) Usage(context.Context) (model.UsageData, error) {
+	return model.UsageData{
+		Session:   model.NewSessionUsage(40, time.Now().Add(time.Hour)),
+		Weekly:    model.NewWeeklyUsage(20, time.Now().Add(time.Hour)),
+		FetchedAt: m.fetchedAt,
+	}, nil
+}
+
+// mockTranscript reports a fixed summary and the sessions it was asked about.
+type mockTranscript struct {
+	stats    model.TranscriptStats
+	sessions []model.SessionInfo
+}
+
+func (m *mockTranscript) Stats(_ context.Context, session model.SessionInfo) (model.TranscriptStats, error) {
+	m.sessions = append(m.sessions, session)
+	return m.stats, nil
+}
+
+// recordingHistory keeps recorded samples in memory.
+type recordingHistory struct {
+	samples model.UsageHistory
+}
+
+func (m *recordingHistory) Record(sample model.UsageSample) error {
+	m.samples = append(m.samples, sample)
+	return nil
+}
+func (m *recordingHistory) History(context.Context, time.Time) (model.UsageHistory, error) {
+	return m.samples, nil
+}
+
 type mockRenderer struct{}
 
 func (m *mockRenderer) Render(data model.StatusLineData) string { return "mocked output" }
 
-type mockInputProvider struct{}
+type capturingRenderer struct {
+	data model.StatusLineData
+}
+
+func (m *capturingRenderer) Render(data model.StatusLineData) string {
+	m.data = data
+	return "captured"
+}
+
+type mockInputProvider struct {
+	transcript string
+}
 
 func (m *mockInputProvider) ModelInfo() model.ModelInfo { return model.ModelInfo{Name: "Opus"} }
 func (m *mockInputProvider) WorkingDir() string         { return "/workspace" }
 func (m *mockInputProvider) Progress() model.Progress   { return model.Progress{Percent: 50} }
+func (m *mockInputProvider) CodeChanges() model.CodeChanges {
+	return model.CodeChanges{Added: 3, Removed: 1}
+}
+func (m *mockInputProvider) SessionCost() model.SessionCost {
+	return model.SessionCost{USD: 1.5, Duration: model.Duration(time.Minute)}
+}
+func (m *mockInputProvider) ContextProgress(stats model.TranscriptStats) model.Progress {
+	if stats.Tokens.IsZero() {
+		return m.Progress()
+	}
+	return stats.Progress(200000)
+}
+func (m *mockInputProvider) Session() model.SessionInfo {
+	return model.SessionInfo{ID: "session", ProjectDir: "/workspace", TranscriptPath: m.transcript}
+}
+func (m *mockInputProvider) Field(string) (json.RawMessage, bool) { return nil, false }
 
 func TestNewStatusLineService(t *testing.T) {
 	tests := []struct {
@@ -62,7 +156,7 @@ func TestNewStatusLineService(t *testing.T) {
 				Taskwarrior: &mockTaskwarriorProv{},
 				Usage:       &mockUsageProv{},
 			}
-			svc := application.NewStatusLineService(deps, &mockRenderer{})
+			svc := application.NewStatusLineService(model.DefaultConfig(), deps, &mockRenderer{})
 			if svc == nil {
 				t.Error("NewStatusLineService() returned nil")
 			}
@@ -87,11 +181,213 @@ func TestStatusLineService_Generate(t *testing.T) {
 				Taskwarrior: &mockTaskwarriorProv{},
 				Usage:       &mockUsageProv{},
 			}
-			svc := application.NewStatusLineService(deps, &mockRenderer{})
-			result := svc.Generate(&mockInputProvider{})
+			svc := application.NewStatusLineService(model.DefaultConfig(), deps, &mockRenderer{})
+			result := svc.Generate(context.Background(), &mockInputProvider{})
 			if result != tt.want {
 				t.Errorf("Generate() = %q, want %q", result, tt.want)
 			}
 		})
 	}
 }
+
+func TestStatusLineService_GenerateWithUpdate_DisabledSegments(t *testing.T) {
+	tests := []struct {
+		name       string
+		layout     model.Layout
+		wantBranch string
+		wantAdded  int
+	}{
+		{name: "all enabled", layout: model.DefaultLayout(), wantBranch: "main", wantAdded: 10},
+		{name: "git disabled", layout: model.Layout{{"model"}, {"changes"}}, wantBranch: "", wantAdded: 10},
+		{name: "changes disabled", layout: model.Layout{{"git"}}, wantBranch: "main", wantAdded: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Layout = tt.layout
+			deps := application.ServiceDeps{
+				Git:         &mockGitRepo{},
+				System:      &mockSystemProv{},
+				Terminal:    &mockTerminalProv{},
+				MCP:         &mockMCPProv{},
+				Taskwarrior: &mockTaskwarriorProv{},
+				Usage:       &mockUsageProv{},
+			}
+			r := &capturingRenderer{}
+			application.NewStatusLineService(cfg, deps, r).GenerateWithUpdate(context.Background(), &mockInputProvider{}, model.UpdateInfo{})
+			if r.data.Git.Branch != tt.wantBranch {
+				t.Errorf("Git.Branch = %q, want %q", r.data.Git.Branch, tt.wantBranch)
+			}
+			if r.data.Changes.Added != tt.wantAdded {
+				t.Errorf("Changes.Added = %d, want %d", r.data.Changes.Added, tt.wantAdded)
+			}
+		})
+	}
+}
+
+func TestStatusLineService_GenerateWithUpdate_ChangesSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      string
+		base        string
+		wantAdded   int
+		wantSession int
+	}{
+		{name: "session", source: model.ChangesSourceSession, wantAdded: 0, wantSession: 3},
+		{name: "worktree", source: model.ChangesSourceWorktree, wantAdded: 10, wantSession: 0},
+		{name: "branch", source: model.ChangesSourceBranch, wantAdded: 100, wantSession: 0},
+		{name: "branch with base", source: model.ChangesSourceBranch, base: "dev", wantAdded: 103, wantSession: 0},
+		{name: "combined", source: model.ChangesSourceCombined, wantAdded: 10, wantSession: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Changes = model.ChangesConfig{Source: tt.source, BaseBranch: tt.base}
+			deps := application.ServiceDeps{
+				Git:         &mockGitRepo{},
+				System:      &mockSystemProv{},
+				Terminal:    &mockTerminalProv{},
+				MCP:         &mockMCPProv{},
+				Taskwarrior: &mockTaskwarriorProv{},
+				Usage:       &mockUsageProv{},
+			}
+			r := &capturingRenderer{}
+			application.NewStatusLineService(cfg, deps, r).GenerateWithUpdate(context.Background(), &mockInputProvider{}, model.UpdateInfo{})
+			if r.data.Changes.Added != tt.wantAdded {
+				t.Errorf("Changes.Added = %d, want %d", r.data.Changes.Added, tt.wantAdded)
+			}
+			if r.data.SessionChanges.Added != tt.wantSession {
+				t.Errorf("SessionChanges.Added = %d, want %d", r.data.SessionChanges.Added, tt.wantSession)
+			}
+		})
+	}
+}
+
+func TestStatusLineService_GenerateWithUpdate_Deadlines(t *testing.T) {
+	tests := []struct {
+		name       string
+		delay      time.Duration
+		render     time.Duration
+		git        time.Duration
+		wantBranch string
+		wantLate   []string
+	}{
+		{name: "in time", delay: 0, render: time.Second, git: time.Second, wantBranch: "slow", wantLate: nil},
+		{name: "provider timeout", delay: time.Second, render: time.Second, git: 10 * time.Millisecond, wantBranch: "", wantLate: []string{"git"}},
+		{name: "render budget", delay: time.Second, render: 10 * time.Millisecond, git: time.Second, wantBranch: "", wantLate: []string{"git"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Timeouts.Render = model.Duration(tt.render)
+			cfg.Timeouts.Git = model.Duration(tt.git)
+			deps := application.ServiceDeps{
+				Git:         &slowGitRepo{delay: tt.delay},
+				System:      &mockSystemProv{},
+				Terminal:    &mockTerminalProv{},
+				MCP:         &mockMCPProv{},
+				Taskwarrior: &mockTaskwarriorProv{},
+				Usage:       &mockUsageProv{},
+			}
+			r := &capturingRenderer{}
+			start := time.Now()
+			application.NewStatusLineService(cfg, deps, r).GenerateWithUpdate(context.Background(), &mockInputProvider{}, model.UpdateInfo{})
+			// Slow providers must not hold the line back
+			if elapsed := time.Since(start); elapsed >= tt.delay && tt.delay > 0 {
+				t.Errorf("GenerateWithUpdate() took %s, want less than %s", elapsed, tt.delay)
+			}
+			if r.data.Git.Branch != tt.wantBranch {
+				t.Errorf("Git.Branch = %q, want %q", r.data.Git.Branch, tt.wantBranch)
+			}
+			if !slices.Equal(r.data.Late, tt.wantLate) {
+				t.Errorf("Late = %v, want %v", r.data.Late, tt.wantLate)
+			}
+			if r.data.Changes.Added != 10 {
+				t.Errorf("Changes.Added = %d, want 10", r.data.Changes.Added)
+			}
+		})
+	}
+}
+
+func TestStatusLineService_History(t *testing.T) {
+	fetchedAt := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name        string
+		layout      model.Layout
+		recorded    bool
+		wantHistory int
+	}{
+		{name: "recorded without sparkline", layout: model.Layout{{model.SegmentModel}}, wantHistory: 0},
+		{name: "recorded and shown", layout: model.Layout{{model.SegmentSparkline}}, wantHistory: 2},
+		{name: "cached reading shown once", layout: model.Layout{{model.SegmentSparkline}}, recorded: true, wantHistory: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Layout = tt.layout
+			store := &recordingHistory{samples: model.UsageHistory{{Time: time.Now().Add(-time.Hour), Session: 10}}}
+			if tt.recorded {
+				store.samples = append(store.samples, model.UsageSample{Time: fetchedAt, Session: 40, Weekly: 20})
+			}
+			deps := application.ServiceDeps{
+				Git:         &mockGitRepo{},
+				System:      &mockSystemProv{},
+				Terminal:    &mockTerminalProv{},
+				MCP:         &mockMCPProv{},
+				Taskwarrior: &mockTaskwarriorProv{},
+				Usage:       &freshUsageProv{fetchedAt: fetchedAt},
+				History:     store,
+			}
+			r := &capturingRenderer{}
+			application.NewStatusLineService(cfg, deps, r).Generate(context.Background(), &mockInputProvider{})
+			if last := store.samples[len(store.samples)-1]; last.Session != 40 || last.Weekly != 20 || !last.Time.Equal(fetchedAt) {
+				t.Errorf("recorded %+v, want a 40%%/20%% sample dated by the fetch", store.samples)
+			}
+			if len(r.data.History) != tt.wantHistory {
+				t.Errorf("History = %d samples, want %d", len(r.data.History), tt.wantHistory)
+			}
+		})
+	}
+}
+
+func TestStatusLineService_Transcript(t *testing.T) {
+	tests := []struct {
+		name        string
+		layout      model.Layout
+		path        string
+		wantRead    bool
+		wantPercent int
+	}{
+		{name: "refines context bar", layout: model.Layout{{model.SegmentModel}}, path: "/tmp/s.jsonl", wantRead: true, wantPercent: 25},
+		{name: "no transcript path", layout: model.Layout{{model.SegmentModel}}, wantRead: false, wantPercent: 50},
+		{name: "activity segment only", layout: model.Layout{{model.SegmentActivity}}, path: "/tmp/s.jsonl", wantRead: true, wantPercent: 25},
+		{name: "model segment disabled", layout: model.Layout{{model.SegmentPath}}, path: "/tmp/s.jsonl", wantRead: false, wantPercent: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Layout = tt.layout
+			reader := &mockTranscript{stats: model.TranscriptStats{Tokens: model.TokenUsage{Input: 10000, CacheRead: 35000, Output: 5000}}}
+			deps := application.ServiceDeps{
+				Git:         &mockGitRepo{},
+				System:      &mockSystemProv{},
+				Terminal:    &mockTerminalProv{},
+				MCP:         &mockMCPProv{},
+				Taskwarrior: &mockTaskwarriorProv{},
+				Usage:       &mockUsageProv{},
+				Transcript:  reader,
+			}
+			r := &capturingRenderer{}
+			application.NewStatusLineService(cfg, deps, r).Generate(context.Background(), &mockInputProvider{transcript: tt.path})
+			if read := len(reader.sessions) > 0; read != tt.wantRead {
+				t.Fatalf("transcript read = %v, want %v", read, tt.wantRead)
+			}
+			if tt.wantRead && reader.sessions[0].TranscriptPath != tt.path {
+				t.Errorf("Stats() path = %q, want %q", reader.sessions[0].TranscriptPath, tt.path)
+			}
+			if r.data.Progress.Percent != tt.wantPercent {
+				t.Errorf("Progress = %d%%, want %d%%", r.data.Progress.Percent, tt.wantPercent)
+			}
+		})
+	}
+}
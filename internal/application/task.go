@@ -0,0 +1,83 @@
+// Package application contains application services.
+package application
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// task is a provider call running in its own goroutine under a deadline.
+// The deadline is the provider timeout, cut short by the render budget.
+type task[T any] struct {
+	name   string
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan T
+}
+
+// startTask runs a provider call in the background.
+//
+// Params:
+//   - ctx: render budget context
+//   - name: provider name reported when the call is late
+//   - timeout: provider timeout
+//   - call: provider call, given the task deadline
+//
+// Returns:
+//   - *task[T]: running task
+func startTask[T any](ctx context.Context, name string, timeout time.Duration, call func(ctx context.Context) T) *task[T] {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	t := &task[T]{name: name, ctx: ctx, cancel: cancel, done: make(chan T, 1)}
+	go func() {
+		result := call(ctx)
+		// Drop results of calls cut short by the deadline (buffered, never blocks)
+		if ctx.Err() == nil {
+			t.done <- result
+		}
+	}()
+	// Return running task
+	return t
+}
+
+// wait returns the call result, or the zero value once the deadline passes.
+// A nil task was never started and yields the zero value without being late.
+//
+// Params:
+//   - late: provider names that missed their deadline, appended to
+//
+// Returns:
+//   - T: call result or zero value
+func (t *task[T]) wait(late *[]string) T {
+	var zero T
+	// Disabled providers are not started
+	if t == nil {
+		// Return placeholder
+		return zero
+	}
+	defer t.cancel()
+	// Take whichever comes first
+	select {
+	// Call finished in time
+	case result := <-t.done:
+		// Return result
+		return result
+	// Deadline passed first
+	case <-t.ctx.Done():
+		// Keep a result that was ready before the deadline
+		select {
+		// Call finished in time
+		case result := <-t.done:
+			// Return result
+			return result
+		// Nothing was ready
+		default:
+		}
+		// Report each provider once
+		if !slices.Contains(*late, t.name) {
+			*late = append(*late, t.name)
+		}
+		// Return placeholder
+		return zero
+	}
+}
@@ -0,0 +1,150 @@
+// Package model contains domain entities and value objects.
+package model
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ActivityTarget tells how the target of a tool call is shortened.
+type ActivityTarget string
+
+// Activity target kinds.
+const (
+	// ActivityTargetText is free text, shortened at its end.
+	ActivityTargetText ActivityTarget = ""
+	// ActivityTargetPath is a file path, shown by its base name.
+	ActivityTargetPath ActivityTarget = "path"
+	// ActivityTargetCommand is a shell command, shown by its first line.
+	ActivityTargetCommand ActivityTarget = "command"
+	// ActivityTargetURL is a web address, shown by its host.
+	ActivityTargetURL ActivityTarget = "url"
+)
+
+// Activity display constants.
+const (
+	// activityEllipsis marks a shortened target.
+	activityEllipsis string = "…"
+	// mcpToolPrefix starts the names of MCP server tools.
+	mcpToolPrefix string = "mcp__"
+	// mcpToolSeparator separates the server and tool parts of MCP tool names.
+	mcpToolSeparator string = "__"
+)
+
+// Activity is what Claude is doing in the main conversation: the last tool
+// it called with what the call targets, and how many sub-agents it started
+// that have not returned yet.
+type Activity struct {
+	Tool   string         `json:"tool,omitempty"`
+	Target string         `json:"target,omitempty"`
+	Kind   ActivityTarget `json:"kind,omitempty"`
+	Agents int            `json:"agents,omitempty"`
+}
+
+// IsZero reports whether no tool was called yet.
+//
+// Returns:
+//   - bool: true without a tool and running sub-agents
+func (a Activity) IsZero() bool {
+	// Check every field
+	return a == Activity{}
+}
+
+// ToolName returns the tool name for display.
+// MCP tools, named mcp__server__tool, are shown as server:tool.
+//
+// Returns:
+//   - string: display name of the tool
+func (a Activity) ToolName() string {
+	rest, ok := strings.CutPrefix(a.Tool, mcpToolPrefix)
+	// Keep built-in tool names
+	if !ok {
+		// Return name unchanged
+		return a.Tool
+	}
+	server, tool, found := strings.Cut(rest, mcpToolSeparator)
+	// Keep names without a tool part
+	if !found {
+		// Return server name
+		return rest
+	}
+	// Return server and tool
+	return server + ":" + tool
+}
+
+// ShortTarget returns the target reduced to its telling part and shortened
+// to maxLen characters. Paths keep their base name and lose their start so
+// the extension stays visible; commands keep their first line, URLs their
+// host, and both lose their end like free text.
+//
+// Params:
+//   - maxLen: maximum length in characters, ellipsis included
+//
+// Returns:
+//   - string: shortened target, empty without a target or room
+func (a Activity) ShortTarget(maxLen int) string {
+	// Nothing to show without a target or room
+	if a.Target == "" || maxLen < 1 {
+		// Return empty target
+		return ""
+	}
+	text := a.Target
+	// Reduce the target by kind
+	switch a.Kind {
+	// File path
+	case ActivityTargetPath:
+		text = path.Base(strings.ReplaceAll(strings.TrimRight(text, `/\`), `\`, "/"))
+		// Return base name shortened from its start
+		return shortenStart(text, maxLen)
+	// Shell command
+	case ActivityTargetCommand:
+		text, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	// Web address
+	case ActivityTargetURL:
+		// Keep the host of parsable addresses
+		if u, err := url.Parse(text); err == nil && u.Host != "" {
+			text = u.Host
+		}
+	}
+	// Return text shortened from its end
+	return shortenEnd(strings.Join(strings.Fields(text), " "), maxLen)
+}
+
+// shortenEnd shortens text to maxLen characters, ending with an ellipsis.
+//
+// Params:
+//   - text: text to shorten
+//   - maxLen: maximum length in characters, ellipsis included
+//
+// Returns:
+//   - string: text unchanged if short enough, otherwise its start
+func shortenEnd(text string, maxLen int) string {
+	runes := []rune(text)
+	// Return as-is if already short enough
+	if len(runes) <= maxLen {
+		// Return original text
+		return text
+	}
+	// Return prefix with ellipsis, dropping the space before it
+	return strings.TrimRight(string(runes[:maxLen-1]), " ") + activityEllipsis
+}
+
+// shortenStart shortens text to maxLen characters, starting with an ellipsis.
+//
+// Params:
+//   - text: text to shorten
+//   - maxLen: maximum length in characters, ellipsis included
+//
+// Returns:
+//   - string: text unchanged if short enough, otherwise its end
+func shortenStart(text string, maxLen int) string {
+	runes := []rune(text)
+	// Return as-is if already short enough
+	if len(runes) <= maxLen {
+		// Return original text
+		return text
+	}
+	// Return ellipsis with suffix
+	return activityEllipsis + string(runes[len(runes)-maxLen+1:])
+}
@@ -0,0 +1,55 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestActivity_ToolName(t *testing.T) {
+	tests := []struct {
+		name string
+		tool string
+		want string
+	}{
+		{name: "built-in", tool: "Edit", want: "Edit"},
+		{name: "mcp tool", tool: "mcp__github__create_issue", want: "github:create_issue"},
+		{name: "mcp without tool part", tool: "mcp__github", want: "github"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (model.Activity{Tool: tt.tool}).ToolName(); got != tt.want {
+				t.Errorf("ToolName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivity_ShortTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		kind   model.ActivityTarget
+		maxLen int
+		want   string
+	}{
+		{name: "path base name", target: "/repo/internal/renderer/powerline.go", kind: model.ActivityTargetPath, maxLen: 24, want: "powerline.go"},
+		{name: "long path keeps extension", target: "/repo/a_very_long_file_name.go", kind: model.ActivityTargetPath, maxLen: 10, want: "…e_name.go"},
+		{name: "windows path", target: `C:\repo\main.go`, kind: model.ActivityTargetPath, maxLen: 24, want: "main.go"},
+		{name: "command first line", target: "go test ./...\necho done", kind: model.ActivityTargetCommand, maxLen: 24, want: "go test ./..."},
+		{name: "long command", target: "go test ./internal/...   -run TestFoo", kind: model.ActivityTargetCommand, maxLen: 24, want: "go test ./internal/...…"},
+		{name: "url host", target: "https://pkg.go.dev/net/url", kind: model.ActivityTargetURL, maxLen: 24, want: "pkg.go.dev"},
+		{name: "unparsable url", target: "not a url", kind: model.ActivityTargetURL, maxLen: 24, want: "not a url"},
+		{name: "text", target: "Explore the renderer package", maxLen: 12, want: "Explore the…"},
+		{name: "no target", maxLen: 24, want: ""},
+		{name: "no room", target: "main.go", kind: model.ActivityTargetPath, maxLen: 0, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := model.Activity{Tool: "Tool", Target: tt.target, Kind: tt.kind}
+			if got := a.ShortTarget(tt.maxLen); got != tt.want {
+				t.Errorf("ShortTarget(%d) = %q, want %q", tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,8 +4,8 @@ package model
 // CodeChanges represents lines of code added and removed.
 // It tracks code modifications in the session.
 type CodeChanges struct {
-	Added   int
-	Removed int
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
 }
 
 // HasChanges returns true if there are any code changes.
@@ -0,0 +1,146 @@
+// Package model contains domain entities and value objects.
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color parsing constants.
+const (
+	// hexPrefix starts a 24-bit color value.
+	hexPrefix string = "#"
+	// hexDigits is the number of hex digits in a 24-bit color.
+	hexDigits int = 6
+	// hexBase is the base for hex color parsing.
+	hexBase int = 16
+	// decimalBase is the base for palette index parsing.
+	decimalBase int = 10
+	// byteBits is the bit size of a color channel or palette index.
+	byteBits int = 8
+	// redShift is the bit offset of the red channel in a packed RGB value.
+	redShift int = 16
+	// greenShift is the bit offset of the green channel in a packed RGB value.
+	greenShift int = 8
+	// channelMask extracts one channel from a packed RGB value.
+	channelMask uint32 = 0xff
+)
+
+// Color is a terminal color: a 256-color palette index or a 24-bit RGB value.
+type Color struct {
+	rgb   bool
+	index uint8
+	r     uint8
+	g     uint8
+	b     uint8
+}
+
+// IndexedColor creates a 256-color palette color.
+//
+// Params:
+//   - index: palette index (0-255)
+//
+// Returns:
+//   - Color: palette color
+func IndexedColor(index uint8) Color {
+	// Return palette color
+	return Color{index: index}
+}
+
+// HexColor creates a 24-bit color from a packed 0xRRGGBB value.
+//
+// Params:
+//   - value: packed RGB value
+//
+// Returns:
+//   - Color: true color
+func HexColor(value uint32) Color {
+	// Unpack channels
+	return Color{
+		rgb: true,
+		r:   uint8(value >> redShift & channelMask),
+		g:   uint8(value >> greenShift & channelMask),
+		b:   uint8(value & channelMask),
+	}
+}
+
+// ParseColor parses a configured color value.
+// Accepted forms are "#rrggbb" for true color and "0" to "255" for palette indexes.
+//
+// Params:
+//   - s: color value
+//
+// Returns:
+//   - Color: parsed color
+//   - error: if the value is not a valid color
+func ParseColor(s string) (Color, error) {
+	s = strings.TrimSpace(s)
+	// Parse hex form
+	if hex, ok := strings.CutPrefix(s, hexPrefix); ok {
+		// Require exactly six digits
+		if len(hex) != hexDigits {
+			// Return length error
+			return Color{}, fmt.Errorf("color %q: want #rrggbb", s)
+		}
+		value, err := strconv.ParseUint(hex, hexBase, 32)
+		// Check for non-hex digits
+		if err != nil {
+			// Return syntax error
+			return Color{}, fmt.Errorf("color %q: want #rrggbb", s)
+		}
+		// Return true color
+		return HexColor(uint32(value)), nil
+	}
+	index, err := strconv.ParseUint(s, decimalBase, byteBits)
+	// Check for invalid or out of range index
+	if err != nil {
+		// Return syntax error
+		return Color{}, fmt.Errorf("color %q: want #rrggbb or a 256-color index 0-255", s)
+	}
+	// Return palette color
+	return IndexedColor(uint8(index)), nil
+}
+
+// IsRGB returns true for 24-bit colors.
+//
+// Returns:
+//   - bool: true if the color is an RGB value, false for a palette index
+func (c Color) IsRGB() bool {
+	// Return color kind
+	return c.rgb
+}
+
+// Index returns the palette index of an indexed color.
+//
+// Returns:
+//   - uint8: palette index (0 for RGB colors)
+func (c Color) Index() uint8 {
+	// Return palette index
+	return c.index
+}
+
+// RGB returns the channels of a 24-bit color.
+//
+// Returns:
+//   - r: red channel (0 for indexed colors)
+//   - g: green channel (0 for indexed colors)
+//   - b: blue channel (0 for indexed colors)
+func (c Color) RGB() (r, g, b uint8) {
+	// Return channels
+	return c.r, c.g, c.b
+}
+
+// String returns the color in its configuration form.
+//
+// Returns:
+//   - string: "#rrggbb" or palette index
+func (c Color) String() string {
+	// Format RGB colors as hex
+	if c.rgb {
+		// Return hex form
+		return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+	}
+	// Return palette index
+	return strconv.Itoa(int(c.index))
+}
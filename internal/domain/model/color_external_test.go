@@ -0,0 +1,63 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantRGB bool
+		want    string
+		wantErr bool
+	}{
+		{name: "palette index", input: "111", want: "111"},
+		{name: "palette index with spaces", input: " 0 ", want: "0"},
+		{name: "hex", input: "#88C0D0", wantRGB: true, want: "#88c0d0"},
+		{name: "index out of range", input: "256", wantErr: true},
+		{name: "negative index", input: "-1", wantErr: true},
+		{name: "short hex", input: "#fff", wantErr: true},
+		{name: "bad hex digits", input: "#gggggg", wantErr: true},
+		{name: "color name", input: "teal", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := model.ParseColor(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColor(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.IsRGB() != tt.wantRGB {
+				t.Errorf("ParseColor(%q).IsRGB() = %v, want %v", tt.input, got.IsRGB(), tt.wantRGB)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseColor(%q).String() = %q, want %q", tt.input, got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHexColor(t *testing.T) {
+	tests := []struct {
+		name                string
+		value               uint32
+		wantR, wantG, wantB uint8
+	}{
+		{name: "nord frost", value: 0x88c0d0, wantR: 0x88, wantG: 0xc0, wantB: 0xd0},
+		{name: "black", value: 0x000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, g, b := model.HexColor(tt.value).RGB()
+			if r != tt.wantR || g != tt.wantG || b != tt.wantB {
+				t.Errorf("HexColor(%#06x).RGB() = (%d, %d, %d), want (%d, %d, %d)", tt.value, r, g, b, tt.wantR, tt.wantG, tt.wantB)
+			}
+		})
+	}
+}
@@ -2,17 +2,515 @@
 package model
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// Segment identifiers used in configuration and rendering.
+const (
+	// SegmentOS is the operating system segment.
+	SegmentOS string = "os"
+	// SegmentModel is the AI model segment with its progress bar.
+	SegmentModel string = "model"
+	// SegmentWeekly is the weekly API usage segment.
+	SegmentWeekly string = "weekly"
+	// SegmentSparkline is the recent usage trend segment.
+	SegmentSparkline string = "sparkline"
+	// SegmentPath is the working directory segment.
+	SegmentPath string = "path"
+	// SegmentGit is the git branch and status segment.
+	SegmentGit string = "git"
+	// SegmentChanges is the lines added/removed segment.
+	SegmentChanges string = "changes"
+	// SegmentCost is the session cost and duration segment.
+	SegmentCost string = "cost"
+	// SegmentActivity is the current tool call and running sub-agents segment.
+	SegmentActivity string = "activity"
+	// SegmentTasks is the Taskwarrior pill segment.
+	SegmentTasks string = "tasks"
+	// SegmentMCP is the MCP server pills segment.
+	SegmentMCP string = "mcp"
+	// SegmentUpdate is the update notification pill segment.
+	SegmentUpdate string = "update"
+)
+
+// Progress bar style names accepted in configuration.
+const (
+	// ProgressStyleHeavy uses heavy horizontal line characters.
+	ProgressStyleHeavy string = "heavy"
+	// ProgressStyleBlock uses Unicode block characters.
+	ProgressStyleBlock string = "block"
+	// ProgressStyleBraille uses braille pattern characters.
+	ProgressStyleBraille string = "braille"
+)
+
+// Lines-changed sources accepted in configuration.
+const (
+	// ChangesSourceSession counts the lines Claude Code reports for the session.
+	ChangesSourceSession string = "session"
+	// ChangesSourceWorktree counts uncommitted changes against HEAD.
+	ChangesSourceWorktree string = "worktree"
+	// ChangesSourceBranch counts changes since the merge-base with the base branch.
+	ChangesSourceBranch string = "branch"
+	// ChangesSourceCombined shows the session and worktree counts side by side.
+	ChangesSourceCombined string = "combined"
+)
+
+// Configuration defaults and limits.
+const (
+	// defaultPathMaxLength is the default maximum displayed path length.
+	defaultPathMaxLength int = 30
+	// defaultProgressWidth is the default progress bar width in characters.
+	defaultProgressWidth int = 20
+	// maxProgressWidth is the largest accepted progress bar width.
+	maxProgressWidth int = 100
+	// defaultTaskSessionDir is the default Taskwarrior session directory.
+	defaultTaskSessionDir string = "/workspace/.claude/sessions"
+	// defaultTaskNameLength is the default maximum task name length.
+	defaultTaskNameLength int = 15
+	// defaultUpdateInterval is the default time between update checks.
+	defaultUpdateInterval time.Duration = 1 * time.Hour
+	// defaultUpdateTimeout is the default timeout for release lookups and downloads.
+	defaultUpdateTimeout time.Duration = 10 * time.Second
+	// defaultUpdateAPIURL is the GitHub API base URL for release lookups.
+	defaultUpdateAPIURL string = "https://api.github.com"
+	// defaultUpdateDownloadURL is the GitHub base URL for release downloads.
+	defaultUpdateDownloadURL string = "https://github.com"
+	// defaultUsageBaseURL is the Anthropic API base URL.
+	defaultUsageBaseURL string = "https://api.anthropic.com"
+	// defaultUsageTimeout is the default timeout for usage API requests.
+	defaultUsageTimeout time.Duration = 5 * time.Second
+	// defaultUsageCacheTTL is how long cached usage data is served without a refresh.
+	defaultUsageCacheTTL time.Duration = 1 * time.Minute
+	// defaultUsageStaleAfter is the cache age after which usage data is shown as stale.
+	defaultUsageStaleAfter time.Duration = 15 * time.Minute
+	// defaultUsageTokenURL is the OAuth endpoint renewing expired access tokens.
+	defaultUsageTokenURL string = "https://console.anthropic.com/v1/oauth/token"
+	// defaultKeychainService is the keychain item Claude Code stores its credentials in.
+	defaultKeychainService string = "Claude Code-credentials"
+	// defaultUsageClientID is the OAuth client the Claude Code credentials belong to.
+	defaultUsageClientID string = "9d1c250a-e61b-44d9-88ed-5944d1962f5e"
+	// defaultHistoryInterval is the minimum time between two history samples.
+	defaultHistoryInterval time.Duration = 5 * time.Minute
+	// defaultHistoryRetention is how long history samples are kept (90 days).
+	defaultHistoryRetention time.Duration = 90 * 24 * time.Hour
+	// defaultSparklineSpan is the period covered by the sparkline.
+	defaultSparklineSpan time.Duration = 24 * time.Hour
+	// defaultSparklineWidth is the default sparkline width in characters.
+	defaultSparklineWidth int = 12
+	// defaultActivityMaxLength is the default maximum displayed tool call target length.
+	defaultActivityMaxLength int = 24
+	// defaultCostSymbol is the default currency symbol.
+	defaultCostSymbol string = "$"
+	// defaultCostRate is the default conversion rate from US dollars.
+	defaultCostRate float64 = 1
+	// defaultCostDecimals is the default number of decimals of amounts.
+	defaultCostDecimals int = 2
+	// maxCostDecimals is the largest accepted number of decimals.
+	maxCostDecimals int = 6
+	// defaultCostDecimalMark is the default decimal separator.
+	defaultCostDecimalMark string = "."
+	// defaultRenderTimeout is the default time budget for collecting provider data.
+	defaultRenderTimeout time.Duration = 300 * time.Millisecond
+	// defaultGitTimeout is the default timeout for git commands.
+	defaultGitTimeout time.Duration = 250 * time.Millisecond
+	// defaultMCPTimeout is the default timeout for reading MCP settings.
+	defaultMCPTimeout time.Duration = 100 * time.Millisecond
+	// defaultTaskwarriorTimeout is the default timeout for Taskwarrior commands.
+	defaultTaskwarriorTimeout time.Duration = 250 * time.Millisecond
+	// defaultTranscriptTimeout is the default timeout for reading the session transcript.
+	defaultTranscriptTimeout time.Duration = 100 * time.Millisecond
+	// listSeparator separates values in list environment variables.
+	listSeparator string = ","
+	// lineSeparator separates lines in the layout environment variable.
+	lineSeparator string = ";"
+)
+
+// Provider names reported when a provider misses its deadline.
+const (
+	// ProviderGit is the git repository provider (status and diff stats).
+	ProviderGit string = "git"
+	// ProviderUsage is the usage API provider.
+	ProviderUsage string = "usage"
+	// ProviderMCP is the MCP settings provider.
+	ProviderMCP string = "mcp"
+	// ProviderTaskwarrior is the Taskwarrior provider.
+	ProviderTaskwarrior string = "taskwarrior"
+	// ProviderHistory is the usage history ledger.
+	ProviderHistory string = "history"
+	// ProviderTranscript is the session transcript reader.
+	ProviderTranscript string = "transcript"
+)
+
+// AlignRight is the layout entry moving the segments after it to the right edge.
+const AlignRight string = ">"
+
+// Environment variable names overriding configuration file values.
+const (
+	// envIconOS toggles the OS icon.
+	envIconOS string = "STATUSLINE_ICON_OS"
+	// envIconPath toggles the folder icon.
+	envIconPath string = "STATUSLINE_ICON_PATH"
+	// envIconGit toggles the git branch icon.
+	envIconGit string = "STATUSLINE_ICON_GIT"
+	// envIconModel toggles the model icon.
+	envIconModel string = "STATUSLINE_ICON_MODEL"
+	// envLayout sets the layout as comma-separated segments and semicolon-separated lines.
+	envLayout string = "STATUSLINE_LAYOUT"
+	// envPathMaxLength sets the maximum displayed path length.
+	envPathMaxLength string = "STATUSLINE_PATH_MAX_LENGTH"
+	// envProgressStyle sets the progress bar style.
+	envProgressStyle string = "STATUSLINE_PROGRESS_STYLE"
+	// envTheme sets the color theme.
+	envTheme string = "STATUSLINE_THEME"
+	// envColorDepth overrides the detected terminal color depth.
+	envColorDepth string = "STATUSLINE_COLOR_DEPTH"
+	// envIconSet sets the icon set.
+	envIconSet string = "STATUSLINE_ICON_SET"
+	// envSeparators sets the separator set.
+	envSeparators string = "STATUSLINE_SEPARATORS"
+	// envAnthropicBaseURL points the usage API at a gateway, as for Claude Code.
+	envAnthropicBaseURL string = "ANTHROPIC_BASE_URL"
+	// envProfile selects the credential profile.
+	envProfile string = "STATUSLINE_PROFILE"
+)
+
+// Config is the user configuration for the status line.
+// It is loaded from a config file and may be overridden by environment variables.
+type Config struct {
+	Layout      Layout            `json:"layout"`
+	Theme       string            `json:"theme"`
+	Colors      map[string]string `json:"colors,omitempty"`
+	ColorDepth  string            `json:"color_depth"`
+	IconSet     string            `json:"icon_set"`
+	Separators  string            `json:"separators"`
+	Icons       IconConfig        `json:"icons"`
+	Path        PathConfig        `json:"path"`
+	Progress    ProgressConfig    `json:"progress"`
+	Changes     ChangesConfig     `json:"changes"`
+	Taskwarrior TaskwarriorConfig `json:"taskwarrior"`
+	Update      UpdateConfig      `json:"update"`
+	Usage       UsageConfig       `json:"usage"`
+	History     HistoryConfig     `json:"history"`
+	Cost        CostConfig        `json:"cost"`
+	Activity    ActivityConfig    `json:"activity"`
+	Timeouts    TimeoutConfig     `json:"timeouts"`
+}
+
 // IconConfig holds configuration for icon visibility per component.
 // It controls which icons are displayed in the status line.
 type IconConfig struct {
-	OS    bool
-	Path  bool
-	Git   bool
-	Model bool
+	OS    bool `json:"os"`
+	Path  bool `json:"path"`
+	Git   bool `json:"git"`
+	Model bool `json:"model"`
+}
+
+// PathConfig holds options for the path segment.
+type PathConfig struct {
+	MaxLength int `json:"max_length"`
+}
+
+// ProgressConfig holds options for progress bars.
+// Forecast adds when the usage limit will be reached, or when the window
+// resets, after the usage percentages.
+type ProgressConfig struct {
+	Style    string `json:"style"`
+	Width    int    `json:"width"`
+	Forecast bool   `json:"forecast"`
+}
+
+// ChangesConfig holds options for the lines-changed segment.
+// Source selects where the counts come from; BaseBranch is the branch the
+// branch source compares against, origin/HEAD, main or master when empty.
+type ChangesConfig struct {
+	Source     string `json:"source"`
+	BaseBranch string `json:"base_branch,omitempty"`
+}
+
+// ShowsSession reports whether the session counts from Claude Code are shown.
+//
+// Returns:
+//   - bool: true for the session and combined sources
+func (cc ChangesConfig) ShowsSession() bool {
+	// Check session sources
+	return cc.Source == ChangesSourceSession || cc.Source == ChangesSourceCombined
+}
+
+// ShowsGit reports whether counts from git are shown.
+//
+// Returns:
+//   - bool: true for every source but session
+func (cc ChangesConfig) ShowsGit() bool {
+	// Check git sources
+	return cc.Source != ChangesSourceSession
+}
+
+// TaskwarriorConfig holds options for the Taskwarrior provider.
+type TaskwarriorConfig struct {
+	SessionDir     string `json:"session_dir"`
+	TaskNameLength int    `json:"task_name_length"`
+}
+
+// HTTPConfig holds the network options shared by the HTTP clients.
+// An empty Proxy uses HTTPS_PROXY, HTTP_PROXY and NO_PROXY; CAFile adds a
+// PEM bundle to the system certificate pool.
+type HTTPConfig struct {
+	Proxy  string `json:"proxy,omitempty"`
+	CAFile string `json:"ca_file,omitempty"`
+}
+
+// UpdateConfig holds options for the self-updater.
+// Releases are looked up under APIURL and downloaded from DownloadURL, which
+// may point at a GitHub Enterprise instance or a mirror.
+type UpdateConfig struct {
+	CheckInterval Duration `json:"check_interval"`
+	Timeout       Duration `json:"timeout"`
+	APIURL        string   `json:"api_url"`
+	DownloadURL   string   `json:"download_url"`
+	HTTPConfig
+}
+
+// UsageConfig holds options for the usage API provider.
+// Usage is requested from BaseURL, a gateway in front of the Anthropic API
+// if needed. Responses are cached for CacheTTL (zero disables the cache);
+// cached data older than StaleAfter is flagged as stale. Expired access
+// tokens are renewed at TokenURL as OAuth client ClientID. The token comes
+// from Credentials, overlaid with the named entry of Profiles when Profile
+// is set.
+type UsageConfig struct {
+	Timeout     Duration                    `json:"timeout"`
+	CacheTTL    Duration                    `json:"cache_ttl"`
+	StaleAfter  Duration                    `json:"stale_after"`
+	BaseURL     string                      `json:"base_url"`
+	TokenURL    string                      `json:"token_url"`
+	ClientID    string                      `json:"client_id"`
+	Credentials CredentialConfig            `json:"credentials"`
+	Profile     string                      `json:"profile,omitempty"`
+	Profiles    map[string]CredentialConfig `json:"profiles,omitempty"`
+	HTTPConfig
+}
+
+// ActiveCredentials returns the credentials of the selected profile.
+//
+// Returns:
+//   - CredentialConfig: base credentials overlaid with the active profile
+func (uc UsageConfig) ActiveCredentials() CredentialConfig {
+	// Return profile credentials, or the base ones without a profile
+	return uc.Credentials.Merge(uc.Profiles[uc.Profile])
+}
+
+// HistoryConfig holds options for the usage history ledger and sparkline.
+// Fresh usage readings are recorded at most every Interval and dropped after
+// Retention; samples older than a day are merged per hour. The sparkline
+// shows the peak session usage over SparklineSpan in SparklineWidth columns.
+type HistoryConfig struct {
+	Enabled        bool     `json:"enabled"`
+	Interval       Duration `json:"interval"`
+	Retention      Duration `json:"retention"`
+	SparklineSpan  Duration `json:"sparkline_span"`
+	SparklineWidth int      `json:"sparkline_width"`
+}
+
+// CostConfig holds options for the session cost segment.
+// Amounts are converted from US dollars at Rate and shown with Decimals
+// digits after DecimalMark, with Symbol before the amount or after it when
+// SymbolAfter is set. Warning and Critical are thresholds in the converted
+// currency that change the segment colors; zero disables a threshold.
+type CostConfig struct {
+	Symbol      string  `json:"symbol"`
+	SymbolAfter bool    `json:"symbol_after"`
+	Rate        float64 `json:"rate"`
+	Decimals    int     `json:"decimals"`
+	DecimalMark string  `json:"decimal_mark"`
+	Warning     float64 `json:"warning"`
+	Critical    float64 `json:"critical"`
+}
+
+// Convert converts a US dollar amount to the configured currency.
+//
+// Params:
+//   - usd: amount in US dollars
+//
+// Returns:
+//   - float64: amount in the configured currency
+func (cc CostConfig) Convert(usd float64) float64 {
+	// Apply the conversion rate
+	return usd * cc.Rate
+}
+
+// ActivityConfig holds options for the activity segment.
+// MaxLength bounds the target shown after the tool name.
+type ActivityConfig struct {
+	MaxLength int `json:"max_length"`
+}
+
+// TimeoutConfig bounds how long data collection may delay the status line.
+// Providers run concurrently; Render is the budget for all of them and the
+// others bound each provider. The usage provider is bounded by usage.timeout.
+type TimeoutConfig struct {
+	Render      Duration `json:"render"`
+	Git         Duration `json:"git"`
+	MCP         Duration `json:"mcp"`
+	Taskwarrior Duration `json:"taskwarrior"`
+	Transcript  Duration `json:"transcript"`
+}
+
+// Layout lists the lines of the status line, each as segment IDs in display order.
+// Segments after an AlignRight entry form the right-aligned group of their line.
+// Segments that appear in no line are disabled.
+type Layout [][]string
+
+// DefaultLayout returns the built-in two-line layout.
+//
+// Returns:
+//   - Layout: system segments on line one, pills on line two
+func DefaultLayout() Layout {
+	// Return default line assignment
+	return Layout{
+		{SegmentOS, SegmentModel, SegmentWeekly, SegmentPath, SegmentGit, SegmentChanges},
+		{SegmentTasks, SegmentMCP, SegmentUpdate},
+	}
+}
+
+// KnownSegments returns every built-in segment identifier.
+// The renderer registers one segment for each of them.
+//
+// Returns:
+//   - []string: all segment identifiers in default display order
+func KnownSegments() []string {
+	// Return every known segment
+	return []string{
+		SegmentOS, SegmentModel, SegmentWeekly, SegmentSparkline, SegmentPath, SegmentGit, SegmentChanges,
+		SegmentCost, SegmentActivity, SegmentTasks, SegmentMCP, SegmentUpdate,
+	}
+}
+
+// Contains returns true if the segment appears on any line.
+//
+// Params:
+//   - id: segment identifier
+//
+// Returns:
+//   - bool: true if the segment is part of the layout
+func (l Layout) Contains(id string) bool {
+	// Search every line
+	for _, line := range l {
+		// Check membership in this line
+		if slices.Contains(line, id) {
+			// Found segment
+			return true
+		}
+	}
+	// Segment not in layout
+	return false
+}
+
+// SplitLine separates a layout line into its left and right groups.
+//
+// Params:
+//   - line: segment identifiers, optionally with one AlignRight entry
+//
+// Returns:
+//   - left: identifiers before AlignRight (the whole line without it)
+//   - right: identifiers after AlignRight, nil without it
+func SplitLine(line []string) (left, right []string) {
+	idx := slices.Index(line, AlignRight)
+	// Keep lines without a right group whole
+	if idx < 0 {
+		// Return left group only
+		return line, nil
+	}
+	// Return both groups
+	return line[:idx], line[idx+1:]
+}
+
+// Clone returns a deep copy of the layout.
+//
+// Returns:
+//   - Layout: independent copy
+func (l Layout) Clone() Layout {
+	// Preserve nil layouts
+	if l == nil {
+		// Return nil copy
+		return nil
+	}
+	out := make(Layout, len(l))
+	// Copy each line
+	for idx, line := range l {
+		out[idx] = slices.Clone(line)
+	}
+	// Return copy
+	return out
+}
+
+// DefaultConfig returns the configuration used when no file is present.
+//
+// Returns:
+//   - Config: configuration with all segments enabled and default options
+func DefaultConfig() Config {
+	// Return built-in defaults
+	return Config{
+		Layout:     DefaultLayout(),
+		Theme:      ThemeDefault,
+		ColorDepth: ColorDepthAuto,
+		IconSet:    IconSetNerd,
+		Separators: SeparatorsAuto,
+		Icons:      DefaultIconConfig(),
+		Path:       PathConfig{MaxLength: defaultPathMaxLength},
+		Progress:   ProgressConfig{Style: ProgressStyleHeavy, Width: defaultProgressWidth},
+		Changes:    ChangesConfig{Source: ChangesSourceWorktree},
+		Taskwarrior: TaskwarriorConfig{
+			SessionDir:     defaultTaskSessionDir,
+			TaskNameLength: defaultTaskNameLength,
+		},
+		Update: UpdateConfig{
+			CheckInterval: Duration(defaultUpdateInterval),
+			Timeout:       Duration(defaultUpdateTimeout),
+			APIURL:        defaultUpdateAPIURL,
+			DownloadURL:   defaultUpdateDownloadURL,
+		},
+		Usage: UsageConfig{
+			Timeout:    Duration(defaultUsageTimeout),
+			CacheTTL:   Duration(defaultUsageCacheTTL),
+			StaleAfter: Duration(defaultUsageStaleAfter),
+			BaseURL:    defaultUsageBaseURL,
+			TokenURL:   defaultUsageTokenURL,
+			ClientID:   defaultUsageClientID,
+			Credentials: CredentialConfig{
+				Sources:         []string{CredentialEnv, CredentialCommand, CredentialKeychain, CredentialFile},
+				KeychainService: defaultKeychainService,
+			},
+		},
+		History: HistoryConfig{
+			Enabled:        true,
+			Interval:       Duration(defaultHistoryInterval),
+			Retention:      Duration(defaultHistoryRetention),
+			SparklineSpan:  Duration(defaultSparklineSpan),
+			SparklineWidth: defaultSparklineWidth,
+		},
+		Cost: CostConfig{
+			Symbol:      defaultCostSymbol,
+			Rate:        defaultCostRate,
+			Decimals:    defaultCostDecimals,
+			DecimalMark: defaultCostDecimalMark,
+		},
+		Activity: ActivityConfig{MaxLength: defaultActivityMaxLength},
+		Timeouts: TimeoutConfig{
+			Render:      Duration(defaultRenderTimeout),
+			Git:         Duration(defaultGitTimeout),
+			MCP:         Duration(defaultMCPTimeout),
+			Taskwarrior: Duration(defaultTaskwarriorTimeout),
+			Transcript:  Duration(defaultTranscriptTimeout),
+		},
+	}
 }
 
 // DefaultIconConfig returns the default configuration with all icons enabled.
@@ -29,32 +527,397 @@ func DefaultIconConfig() IconConfig {
 	}
 }
 
-// IconConfigFromEnv reads icon configuration from environment variables.
+// EffectiveColorDepth returns the configured color depth, or the detected one for auto.
+//
+// Params:
+//   - detected: depth detected from the terminal
 //
 // Returns:
-//   - IconConfig: configuration based on environment variables
-func IconConfigFromEnv() IconConfig {
-	config := DefaultIconConfig()
+//   - ColorDepth: depth to render with
+func (c Config) EffectiveColorDepth(detected ColorDepth) ColorDepth {
+	// Explicit configuration wins over detection
+	if depth, ok := ParseColorDepth(c.ColorDepth); ok {
+		// Return configured depth
+		return depth
+	}
+	// Return detected depth
+	return detected
+}
+
+// SegmentEnabled returns true if the segment appears in the layout.
+//
+// Params:
+//   - id: segment identifier
+//
+// Returns:
+//   - bool: true if the segment should be rendered
+func (c Config) SegmentEnabled(id string) bool {
+	// Check membership in the configured layout
+	return c.Layout.Contains(id)
+}
+
+// WithEnv returns a copy of the configuration with environment overrides applied.
+// Unparseable numeric values are ignored so a bad variable never hides the line.
+//
+// Returns:
+//   - Config: configuration with environment values taking precedence
+func (c Config) WithEnv() Config {
+	c.Icons = c.Icons.withEnv()
+	c.Layout = c.Layout.Clone()
+
+	// Check layout override
+	if val := os.Getenv(envLayout); val != "" {
+		c.Layout = parseLayout(val)
+	}
+	// Check path length override
+	if val := os.Getenv(envPathMaxLength); val != "" {
+		// Only apply valid integers
+		if n, err := strconv.Atoi(strings.TrimSpace(val)); err == nil {
+			c.Path.MaxLength = n
+		}
+	}
+	// Check progress style override
+	if val := os.Getenv(envProgressStyle); val != "" {
+		c.Progress.Style = strings.ToLower(strings.TrimSpace(val))
+	}
+	// Check theme override
+	if val := os.Getenv(envTheme); val != "" {
+		c.Theme = strings.TrimSpace(val)
+	}
+	// Check color depth override
+	if val := os.Getenv(envColorDepth); val != "" {
+		c.ColorDepth = strings.ToLower(strings.TrimSpace(val))
+	}
+	// Check icon set override
+	if val := os.Getenv(envIconSet); val != "" {
+		c.IconSet = strings.ToLower(strings.TrimSpace(val))
+	}
+	// Check separator set override
+	if val := os.Getenv(envSeparators); val != "" {
+		c.Separators = strings.ToLower(strings.TrimSpace(val))
+	}
+	// Check API gateway override
+	if val := os.Getenv(envAnthropicBaseURL); val != "" {
+		c.Usage.BaseURL = strings.TrimSpace(val)
+	}
+	// Check credential profile override
+	if val := os.Getenv(envProfile); val != "" {
+		c.Usage.Profile = strings.TrimSpace(val)
+	}
+
+	// Return overridden copy
+	return c
+}
+
+// Validate checks the configuration and reports every invalid field.
+// Layout identifiers are checked against the given segment IDs, which
+// callers take from the renderer's segment registry.
+//
+// Params:
+//   - segments: identifiers of every registered segment
+//
+// Returns:
+//   - error: joined validation errors, or nil if the configuration is valid
+func (c Config) Validate(segments []string) error {
+	var errs []error
+
+	errs = append(errs, c.Layout.validate(segments)...)
+	// Check theme name (theme files are resolved to their base by the loader)
+	if !slices.Contains(BuiltinThemes(), c.Theme) {
+		errs = append(errs, fmt.Errorf("theme: no built-in theme or theme file named %q", c.Theme))
+	}
+	errs = append(errs, validateColors("colors", c.Colors)...)
+	// Check color depth
+	if _, ok := ParseColorDepth(c.ColorDepth); !ok && c.ColorDepth != ColorDepthAuto {
+		errs = append(errs, fmt.Errorf("color_depth: must be one of auto, none, 16, 256, truecolor, got %q", c.ColorDepth))
+	}
+	// Check icon set
+	if !slices.Contains(IconSets(), c.IconSet) {
+		errs = append(errs, fmt.Errorf("icon_set: must be one of nerd, unicode, ascii, got %q", c.IconSet))
+	}
+	// Check separator set
+	if !slices.Contains(SeparatorSets(), c.Separators) && c.Separators != SeparatorsAuto {
+		errs = append(errs, fmt.Errorf("separators: must be one of auto, powerline, rounded, slanted, flat, got %q", c.Separators))
+	}
+	// Check path length
+	if c.Path.MaxLength < 1 {
+		errs = append(errs, fmt.Errorf("path.max_length: must be at least 1, got %d", c.Path.MaxLength))
+	}
+	// Check progress style
+	if !slices.Contains([]string{ProgressStyleHeavy, ProgressStyleBlock, ProgressStyleBraille}, c.Progress.Style) {
+		errs = append(errs, fmt.Errorf("progress.style: must be one of heavy, block, braille, got %q", c.Progress.Style))
+	}
+	// Check progress width
+	if c.Progress.Width < 1 || c.Progress.Width > maxProgressWidth {
+		errs = append(errs, fmt.Errorf("progress.width: must be between 1 and %d, got %d", maxProgressWidth, c.Progress.Width))
+	}
+	// Check lines-changed source
+	if !slices.Contains([]string{ChangesSourceSession, ChangesSourceWorktree, ChangesSourceBranch, ChangesSourceCombined}, c.Changes.Source) {
+		errs = append(errs, fmt.Errorf("changes.source: must be one of session, worktree, branch, combined, got %q", c.Changes.Source))
+	}
+	// Check task name length
+	if c.Taskwarrior.TaskNameLength < 1 {
+		errs = append(errs, fmt.Errorf("taskwarrior.task_name_length: must be at least 1, got %d", c.Taskwarrior.TaskNameLength))
+	}
+	// Check update interval
+	if c.Update.CheckInterval < 0 {
+		errs = append(errs, fmt.Errorf("update.check_interval: must not be negative, got %s", c.Update.CheckInterval))
+	}
+	// Check update request timeout
+	if c.Update.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("update.timeout: must be positive, got %s", c.Update.Timeout))
+	}
+	// Check release lookup URL
+	if !isHTTPURL(c.Update.APIURL) {
+		errs = append(errs, fmt.Errorf("update.api_url: must be an http or https URL, got %q", c.Update.APIURL))
+	}
+	// Check release download URL
+	if !isHTTPURL(c.Update.DownloadURL) {
+		errs = append(errs, fmt.Errorf("update.download_url: must be an http or https URL, got %q", c.Update.DownloadURL))
+	}
+	errs = append(errs, c.Update.HTTPConfig.validate("update")...)
+	// Check usage timeout
+	if c.Usage.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("usage.timeout: must be positive, got %s", c.Usage.Timeout))
+	}
+	// Check usage cache lifetime
+	if c.Usage.CacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("usage.cache_ttl: must not be negative, got %s", c.Usage.CacheTTL))
+	}
+	// Check usage staleness threshold
+	if c.Usage.StaleAfter <= 0 {
+		errs = append(errs, fmt.Errorf("usage.stale_after: must be positive, got %s", c.Usage.StaleAfter))
+	}
+	// Check API base URL
+	if !isHTTPURL(c.Usage.BaseURL) {
+		errs = append(errs, fmt.Errorf("usage.base_url: must be an http or https URL, got %q", c.Usage.BaseURL))
+	}
+	// Check token endpoint
+	if !isHTTPURL(c.Usage.TokenURL) {
+		errs = append(errs, fmt.Errorf("usage.token_url: must be an http or https URL, got %q", c.Usage.TokenURL))
+	}
+	// Check OAuth client
+	if c.Usage.ClientID == "" {
+		errs = append(errs, errors.New("usage.client_id: must not be empty"))
+	}
+	errs = append(errs, c.Usage.Credentials.validate("usage.credentials", true)...)
+	// Check each profile in a stable order; profiles may keep the base sources
+	for _, name := range slices.Sorted(maps.Keys(c.Usage.Profiles)) {
+		errs = append(errs, c.Usage.Profiles[name].validate("usage.profiles."+name, false)...)
+	}
+	// Check the selected profile
+	if _, ok := c.Usage.Profiles[c.Usage.Profile]; c.Usage.Profile != "" && !ok {
+		errs = append(errs, fmt.Errorf("usage.profile: no profile named %q", c.Usage.Profile))
+	}
+	errs = append(errs, c.Usage.HTTPConfig.validate("usage")...)
+	// Check history sampling interval
+	if c.History.Interval <= 0 {
+		errs = append(errs, fmt.Errorf("history.interval: must be positive, got %s", c.History.Interval))
+	}
+	// Check history retention
+	if c.History.Retention <= 0 {
+		errs = append(errs, fmt.Errorf("history.retention: must be positive, got %s", c.History.Retention))
+	}
+	// Check sparkline span
+	if c.History.SparklineSpan <= 0 {
+		errs = append(errs, fmt.Errorf("history.sparkline_span: must be positive, got %s", c.History.SparklineSpan))
+	}
+	// Check sparkline width
+	if c.History.SparklineWidth < 1 || c.History.SparklineWidth > maxProgressWidth {
+		errs = append(errs, fmt.Errorf("history.sparkline_width: must be between 1 and %d, got %d", maxProgressWidth, c.History.SparklineWidth))
+	}
+	errs = append(errs, c.Cost.validate()...)
+	// Check activity target length
+	if c.Activity.MaxLength < 1 {
+		errs = append(errs, fmt.Errorf("activity.max_length: must be at least 1, got %d", c.Activity.MaxLength))
+	}
+	errs = append(errs, c.Timeouts.validate()...)
+
+	// Return all collected errors
+	return errors.Join(errs...)
+}
 
+// validate checks the proxy URL.
+//
+// Params:
+//   - section: configuration section prefixed to error messages
+//
+// Returns:
+//   - []error: validation errors
+func (hc HTTPConfig) validate(section string) []error {
+	// No proxy uses the environment
+	if hc.Proxy == "" {
+		// Return no errors
+		return nil
+	}
+	u, err := url.Parse(hc.Proxy)
+	// Accept the schemes supported by the HTTP transport
+	if err != nil || u.Host == "" || !slices.Contains([]string{"http", "https", "socks5", "socks5h"}, u.Scheme) {
+		// Return proxy error
+		return []error{fmt.Errorf("%s.proxy: must be an http, https or socks5 URL, got %q", section, hc.Proxy)}
+	}
+	// Return no errors
+	return nil
+}
+
+// validate checks the currency format and thresholds.
+//
+// Returns:
+//   - []error: validation errors
+func (cc CostConfig) validate() []error {
+	var errs []error
+	// Check conversion rate
+	if cc.Rate <= 0 {
+		errs = append(errs, fmt.Errorf("cost.rate: must be positive, got %g", cc.Rate))
+	}
+	// Check decimals
+	if cc.Decimals < 0 || cc.Decimals > maxCostDecimals {
+		errs = append(errs, fmt.Errorf("cost.decimals: must be between 0 and %d, got %d", maxCostDecimals, cc.Decimals))
+	}
+	// Check decimal separator
+	if cc.DecimalMark == "" && cc.Decimals > 0 {
+		errs = append(errs, errors.New("cost.decimal_mark: must not be empty"))
+	}
+	// Check warning threshold
+	if cc.Warning < 0 {
+		errs = append(errs, fmt.Errorf("cost.warning: must not be negative, got %g", cc.Warning))
+	}
+	// Check critical threshold
+	if cc.Critical < 0 {
+		errs = append(errs, fmt.Errorf("cost.critical: must not be negative, got %g", cc.Critical))
+	}
+	// Check threshold order when both are set
+	if cc.Warning > 0 && cc.Critical > 0 && cc.Critical < cc.Warning {
+		errs = append(errs, fmt.Errorf("cost.critical: must not be below cost.warning (%g), got %g", cc.Warning, cc.Critical))
+	}
+	// Return collected errors
+	return errs
+}
+
+// validate checks that every timeout is positive.
+//
+// Returns:
+//   - []error: one error per invalid timeout
+func (tc TimeoutConfig) validate() []error {
+	var errs []error
+	timeouts := []struct {
+		field string
+		value Duration
+	}{
+		{field: "render", value: tc.Render},
+		{field: "git", value: tc.Git},
+		{field: "mcp", value: tc.MCP},
+		{field: "taskwarrior", value: tc.Taskwarrior},
+		{field: "transcript", value: tc.Transcript},
+	}
+	// Check each timeout
+	for _, timeout := range timeouts {
+		// Reject zero and negative timeouts
+		if timeout.value <= 0 {
+			errs = append(errs, fmt.Errorf("timeouts.%s: must be positive, got %s", timeout.field, timeout.value))
+		}
+	}
+	// Return collected errors
+	return errs
+}
+
+// validate checks segment identifiers and line contents.
+//
+// Params:
+//   - segments: identifiers of every registered segment
+//
+// Returns:
+//   - []error: one error per problem found
+func (l Layout) validate(segments []string) []error {
+	var errs []error
+
+	// Require something to render
+	if len(l) == 0 {
+		// Return early, nothing else to check
+		return []error{errors.New("layout: must contain at least one line")}
+	}
+	seen := make(map[string]bool, len(segments))
+	// Check each line
+	for lineIdx, line := range l {
+		// Reject empty lines
+		if len(line) == 0 {
+			errs = append(errs, fmt.Errorf("layout[%d]: line is empty", lineIdx))
+		}
+		markers := countEntries(line, AlignRight)
+		// Allow a single right group per line
+		if markers > 1 {
+			errs = append(errs, fmt.Errorf("layout[%d]: %q may appear once, got %d", lineIdx, AlignRight, markers))
+		}
+		// Reject lines holding nothing but the alignment marker
+		if len(line) > 0 && markers == len(line) {
+			errs = append(errs, fmt.Errorf("layout[%d]: line is empty", lineIdx))
+		}
+		// Check each segment identifier
+		for _, id := range line {
+			// Skip the alignment marker
+			if id == AlignRight {
+				continue
+			}
+			// Reject unknown identifiers
+			if !slices.Contains(segments, id) {
+				errs = append(errs, fmt.Errorf("layout[%d]: unknown segment %q", lineIdx, id))
+			}
+			// Reject segments placed twice
+			if seen[id] {
+				errs = append(errs, fmt.Errorf("layout[%d]: duplicate segment %q", lineIdx, id))
+			}
+			seen[id] = true
+		}
+	}
+	// Return collected errors
+	return errs
+}
+
+// countEntries counts occurrences of an entry in a layout line.
+//
+// Params:
+//   - line: segment identifiers
+//   - entry: entry to count
+//
+// Returns:
+//   - int: number of occurrences
+func countEntries(line []string, entry string) int {
+	count := 0
+	// Check each entry
+	for _, id := range line {
+		// Count matches
+		if id == entry {
+			count++
+		}
+	}
+	// Return count
+	return count
+}
+
+// withEnv applies STATUSLINE_ICON_* environment overrides.
+//
+// Returns:
+//   - IconConfig: configuration based on environment variables
+func (ic IconConfig) withEnv() IconConfig {
 	// Check OS icon setting
-	if val := os.Getenv("STATUSLINE_ICON_OS"); val != "" {
-		config.OS = parseBool(val)
+	if val := os.Getenv(envIconOS); val != "" {
+		ic.OS = parseBool(val)
 	}
 	// Check Path icon setting
-	if val := os.Getenv("STATUSLINE_ICON_PATH"); val != "" {
-		config.Path = parseBool(val)
+	if val := os.Getenv(envIconPath); val != "" {
+		ic.Path = parseBool(val)
 	}
 	// Check Git icon setting
-	if val := os.Getenv("STATUSLINE_ICON_GIT"); val != "" {
-		config.Git = parseBool(val)
+	if val := os.Getenv(envIconGit); val != "" {
+		ic.Git = parseBool(val)
 	}
 	// Check Model icon setting
-	if val := os.Getenv("STATUSLINE_ICON_MODEL"); val != "" {
-		config.Model = parseBool(val)
+	if val := os.Getenv(envIconModel); val != "" {
+		ic.Model = parseBool(val)
 	}
 
 	// Return configured settings
-	return config
+	return ic
 }
 
 // parseBool parses a string to boolean, defaulting to true.
@@ -69,3 +932,56 @@ func parseBool(s string) bool {
 	// Return false only for explicit false values
 	return lower != "false" && lower != "0" && lower != "no"
 }
+
+// parseLayout parses lines separated by semicolons of comma-separated segments.
+//
+// Params:
+//   - s: layout string like "os,model;tasks,mcp"
+//
+// Returns:
+//   - Layout: parsed layout, skipping blank lines
+func parseLayout(s string) Layout {
+	var layout Layout
+	// Parse each line
+	for line := range strings.SplitSeq(s, lineSeparator) {
+		// Skip blank lines
+		if ids := parseList(line); len(ids) > 0 {
+			layout = append(layout, ids)
+		}
+	}
+	// Return parsed layout
+	return layout
+}
+
+// parseList splits a comma-separated list, trimming blanks.
+//
+// Params:
+//   - s: comma-separated values
+//
+// Returns:
+//   - []string: non-empty trimmed values
+func parseList(s string) []string {
+	var items []string
+	// Collect non-empty items
+	for item := range strings.SplitSeq(s, listSeparator) {
+		// Skip blank entries
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	// Return parsed items
+	return items
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL.
+//
+// Params:
+//   - s: URL to check
+//
+// Returns:
+//   - bool: true for http(s) URLs with a host
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	// Require a scheme the HTTP client supports and a host
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -1,6 +1,8 @@
 package model_test
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/florent/status-line/internal/domain/model"
@@ -22,16 +24,271 @@ func TestDefaultIconConfig(t *testing.T) {
 	}
 }
 
-func TestIconConfigFromEnv(t *testing.T) {
+func TestDefaultConfig(t *testing.T) {
 	tests := []struct {
 		name string
 	}{
-		{name: "returns config from env"},
+		{name: "default config is valid"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			config := model.IconConfigFromEnv()
-			_ = config // Just verify no panic
+			if err := model.DefaultConfig().Validate(model.KnownSegments()); err != nil {
+				t.Errorf("DefaultConfig().Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestConfig_SegmentEnabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout model.Layout
+		id     string
+		want   bool
+	}{
+		{name: "segment on first line", layout: model.Layout{{"os", "git"}}, id: "git", want: true},
+		{name: "segment on second line", layout: model.Layout{{"os"}, {"mcp"}}, id: "mcp", want: true},
+		{name: "segment not placed", layout: model.Layout{{"os"}}, id: "git", want: false},
+		{name: "empty layout", layout: nil, id: "os", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.Config{Layout: tt.layout}
+			if got := cfg.SegmentEnabled(tt.id); got != tt.want {
+				t.Errorf("SegmentEnabled(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayout_Clone(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout model.Layout
+	}{
+		{name: "default layout", layout: model.DefaultLayout()},
+		{name: "nil layout", layout: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clone := tt.layout.Clone()
+			if len(clone) != len(tt.layout) {
+				t.Fatalf("Clone() len = %d, want %d", len(clone), len(tt.layout))
+			}
+			if len(clone) > 0 {
+				clone[0][0] = "changed"
+				if tt.layout[0][0] == "changed" {
+					t.Error("Clone() shares line storage with original")
+				}
+			}
+		})
+	}
+}
+
+func TestSplitLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      []string
+		wantLeft  []string
+		wantRight []string
+	}{
+		{name: "no right group", line: []string{"os", "model"}, wantLeft: []string{"os", "model"}},
+		{name: "both groups", line: []string{"os", ">", "update"}, wantLeft: []string{"os"}, wantRight: []string{"update"}},
+		{name: "right group only", line: []string{">", "mcp"}, wantLeft: []string{}, wantRight: []string{"mcp"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right := model.SplitLine(tt.line)
+			if !slices.Equal(left, tt.wantLeft) || !slices.Equal(right, tt.wantRight) {
+				t.Errorf("SplitLine() = %v, %v, want %v, %v", left, right, tt.wantLeft, tt.wantRight)
+			}
+		})
+	}
+}
+
+func TestConfig_WithEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		wantOS    bool
+		wantStyle string
+		wantLen   int
+		wantTheme string
+		wantDepth string
+		wantIcons string
+		wantBase  string
+	}{
+		{name: "no overrides", env: nil, wantOS: true, wantStyle: "heavy", wantLen: 30},
+		{name: "theme", env: map[string]string{"STATUSLINE_THEME": " nord "}, wantOS: true, wantStyle: "heavy", wantLen: 30, wantTheme: "nord"},
+		{name: "color depth", env: map[string]string{"STATUSLINE_COLOR_DEPTH": " TrueColor "}, wantOS: true, wantStyle: "heavy", wantLen: 30, wantDepth: "truecolor"},
+		{name: "icon set", env: map[string]string{"STATUSLINE_ICON_SET": "ASCII", "STATUSLINE_SEPARATORS": "flat"}, wantOS: true, wantStyle: "heavy", wantLen: 30, wantIcons: "ascii"},
+		{name: "icon disabled", env: map[string]string{"STATUSLINE_ICON_OS": "false"}, wantOS: false, wantStyle: "heavy", wantLen: 30},
+		{name: "style and length", env: map[string]string{"STATUSLINE_PROGRESS_STYLE": " Block ", "STATUSLINE_PATH_MAX_LENGTH": "50"}, wantOS: true, wantStyle: "block", wantLen: 50},
+		{name: "invalid length ignored", env: map[string]string{"STATUSLINE_PATH_MAX_LENGTH": "long"}, wantOS: true, wantStyle: "heavy", wantLen: 30},
+		{name: "api gateway", env: map[string]string{"ANTHROPIC_BASE_URL": "https://gateway.corp/anthropic "}, wantOS: true, wantStyle: "heavy", wantLen: 30, wantBase: "https://gateway.corp/anthropic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg := model.DefaultConfig().WithEnv()
+			if cfg.Icons.OS != tt.wantOS {
+				t.Errorf("WithEnv().Icons.OS = %v, want %v", cfg.Icons.OS, tt.wantOS)
+			}
+			if cfg.Progress.Style != tt.wantStyle {
+				t.Errorf("WithEnv().Progress.Style = %q, want %q", cfg.Progress.Style, tt.wantStyle)
+			}
+			if cfg.Path.MaxLength != tt.wantLen {
+				t.Errorf("WithEnv().Path.MaxLength = %d, want %d", cfg.Path.MaxLength, tt.wantLen)
+			}
+			if tt.wantTheme != "" && cfg.Theme != tt.wantTheme {
+				t.Errorf("WithEnv().Theme = %q, want %q", cfg.Theme, tt.wantTheme)
+			}
+			if tt.wantDepth != "" && cfg.ColorDepth != tt.wantDepth {
+				t.Errorf("WithEnv().ColorDepth = %q, want %q", cfg.ColorDepth, tt.wantDepth)
+			}
+			if tt.wantIcons != "" && cfg.IconSet != tt.wantIcons {
+				t.Errorf("WithEnv().IconSet = %q, want %q", cfg.IconSet, tt.wantIcons)
+			}
+			if tt.wantBase != "" && cfg.Usage.BaseURL != tt.wantBase {
+				t.Errorf("WithEnv().Usage.BaseURL = %q, want %q", cfg.Usage.BaseURL, tt.wantBase)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*model.Config)
+		segments []string
+		wantErr  string
+	}{
+		{name: "valid", modify: func(*model.Config) {}, wantErr: ""},
+		{name: "registered custom segment", modify: func(c *model.Config) { c.Layout = model.Layout{{"os", "clock"}} }, segments: append(model.KnownSegments(), "clock"), wantErr: ""},
+		{name: "unknown segment", modify: func(c *model.Config) { c.Layout = model.Layout{{"clock"}} }, wantErr: `layout[0]: unknown segment "clock"`},
+		{name: "duplicate across lines", modify: func(c *model.Config) { c.Layout = model.Layout{{"os"}, {"os"}} }, wantErr: `layout[1]: duplicate segment "os"`},
+		{name: "right group", modify: func(c *model.Config) { c.Layout = model.Layout{{"os", ">", "update"}, {">", "mcp"}} }, wantErr: ""},
+		{name: "two right groups", modify: func(c *model.Config) { c.Layout = model.Layout{{"os", ">", "git", ">", "update"}} }, wantErr: `layout[0]: ">" may appear once, got 2`},
+		{name: "only alignment marker", modify: func(c *model.Config) { c.Layout = model.Layout{{"os"}, {">"}} }, wantErr: "layout[1]: line is empty"},
+		{name: "empty line", modify: func(c *model.Config) { c.Layout = model.Layout{{"os"}, {}} }, wantErr: "layout[1]: line is empty"},
+		{name: "empty layout", modify: func(c *model.Config) { c.Layout = nil }, wantErr: "at least one line"},
+		{name: "path length", modify: func(c *model.Config) { c.Path.MaxLength = 0 }, wantErr: "path.max_length"},
+		{name: "bar style", modify: func(c *model.Config) { c.Progress.Style = "dots" }, wantErr: "progress.style"},
+		{name: "bar width", modify: func(c *model.Config) { c.Progress.Width = 500 }, wantErr: "progress.width"},
+		{name: "usage timeout", modify: func(c *model.Config) { c.Usage.Timeout = 0 }, wantErr: "usage.timeout"},
+		{name: "usage cache disabled", modify: func(c *model.Config) { c.Usage.CacheTTL = 0 }, wantErr: ""},
+		{name: "usage cache ttl", modify: func(c *model.Config) { c.Usage.CacheTTL = -1 }, wantErr: "usage.cache_ttl"},
+		{name: "usage stale after", modify: func(c *model.Config) { c.Usage.StaleAfter = 0 }, wantErr: "usage.stale_after"},
+		{name: "history interval", modify: func(c *model.Config) { c.History.Interval = 0 }, wantErr: "history.interval"},
+		{name: "sparkline width", modify: func(c *model.Config) { c.History.SparklineWidth = 0 }, wantErr: "history.sparkline_width"},
+		{name: "changes source", modify: func(c *model.Config) { c.Changes.Source = "staged" }, wantErr: "changes.source"},
+		{name: "cost rate", modify: func(c *model.Config) { c.Cost.Rate = 0 }, wantErr: "cost.rate"},
+		{name: "cost decimals", modify: func(c *model.Config) { c.Cost.Decimals = 7 }, wantErr: "cost.decimals"},
+		{name: "cost threshold order", modify: func(c *model.Config) { c.Cost.Warning, c.Cost.Critical = 10, 5 }, wantErr: "cost.critical: must not be below cost.warning"},
+		{name: "cost warning only", modify: func(c *model.Config) { c.Cost.Warning = 5 }, wantErr: ""},
+		{name: "usage token url", modify: func(c *model.Config) { c.Usage.TokenURL = "console.anthropic.com/token" }, wantErr: "usage.token_url: must be an http or https URL"},
+		{name: "local token url", modify: func(c *model.Config) { c.Usage.TokenURL = "http://127.0.0.1:8080/token" }, wantErr: ""},
+		{name: "usage client id", modify: func(c *model.Config) { c.Usage.ClientID = "" }, wantErr: "usage.client_id: must not be empty"},
+		{name: "usage base url", modify: func(c *model.Config) { c.Usage.BaseURL = "" }, wantErr: "usage.base_url: must be an http or https URL"},
+		{name: "usage proxy", modify: func(c *model.Config) { c.Usage.Proxy = "http://proxy.corp:3128" }, wantErr: ""},
+		{name: "invalid usage proxy", modify: func(c *model.Config) { c.Usage.Proxy = "proxy.corp:3128" }, wantErr: "usage.proxy: must be an http, https or socks5 URL"},
+		{name: "update timeout", modify: func(c *model.Config) { c.Update.Timeout = 0 }, wantErr: "update.timeout: must be positive"},
+		{name: "update api url", modify: func(c *model.Config) { c.Update.APIURL = "ftp://github.corp" }, wantErr: "update.api_url"},
+		{name: "update download url", modify: func(c *model.Config) { c.Update.DownloadURL = "github.corp" }, wantErr: "update.download_url"},
+		{name: "update socks proxy", modify: func(c *model.Config) { c.Update.Proxy = "socks5://127.0.0.1:1080" }, wantErr: ""},
+		{name: "credential sources", modify: func(c *model.Config) { c.Usage.Credentials.Sources = []string{"command", "secret_service"} }, wantErr: ""},
+		{name: "no credential sources", modify: func(c *model.Config) { c.Usage.Credentials.Sources = nil }, wantErr: "usage.credentials.sources: must list at least one source"},
+		{name: "unknown credential source", modify: func(c *model.Config) { c.Usage.Credentials.Sources = []string{"vault"} }, wantErr: `usage.credentials.sources[0]: must be one of env, command, keychain, secret_service, file, got "vault"`},
+		{name: "profile", modify: func(c *model.Config) {
+			c.Usage.Profile = "work"
+			c.Usage.Profiles = map[string]model.CredentialConfig{"work": {ConfigDir: "~/.claude-work"}}
+		}, wantErr: ""},
+		{name: "unknown profile", modify: func(c *model.Config) { c.Usage.Profile = "work" }, wantErr: `usage.profile: no profile named "work"`},
+		{name: "profile source", modify: func(c *model.Config) {
+			c.Usage.Profiles = map[string]model.CredentialConfig{"work": {Sources: []string{"vault"}}}
+		}, wantErr: "usage.profiles.work.sources[0]"},
+		{name: "render timeout", modify: func(c *model.Config) { c.Timeouts.Render = 0 }, wantErr: "timeouts.render: must be positive"},
+		{name: "git timeout", modify: func(c *model.Config) { c.Timeouts.Git = -1 }, wantErr: "timeouts.git: must be positive"},
+		{name: "activity max length", modify: func(c *model.Config) { c.Activity.MaxLength = 0 }, wantErr: "activity.max_length: must be at least 1"},
+		{name: "transcript timeout", modify: func(c *model.Config) { c.Timeouts.Transcript = 0 }, wantErr: "timeouts.transcript: must be positive"},
+		{name: "built-in theme", modify: func(c *model.Config) { c.Theme = "high-contrast" }, wantErr: ""},
+		{name: "unknown theme", modify: func(c *model.Config) { c.Theme = "dracula" }, wantErr: `theme: no built-in theme or theme file named "dracula"`},
+		{name: "valid colors", modify: func(c *model.Config) { c.Colors = map[string]string{"git.bg": "#88c0d0", "cursor": "196"} }, wantErr: ""},
+		{name: "unknown color role", modify: func(c *model.Config) { c.Colors = map[string]string{"git.background": "1"} }, wantErr: `colors: unknown color role "git.background"`},
+		{name: "fixed color depth", modify: func(c *model.Config) { c.ColorDepth = "16" }, wantErr: ""},
+		{name: "ascii icons", modify: func(c *model.Config) { c.IconSet = "ascii" }, wantErr: ""},
+		{name: "unknown icon set", modify: func(c *model.Config) { c.IconSet = "emoji" }, wantErr: `icon_set: must be one of nerd, unicode, ascii, got "emoji"`},
+		{name: "slanted separators", modify: func(c *model.Config) { c.Separators = "slanted" }, wantErr: ""},
+		{name: "unknown separators", modify: func(c *model.Config) { c.Separators = "wavy" }, wantErr: `separators: must be one of auto, powerline, rounded, slanted, flat, got "wavy"`},
+		{name: "unknown color depth", modify: func(c *model.Config) { c.ColorDepth = "88" }, wantErr: `color_depth: must be one of auto, none, 16, 256, truecolor, got "88"`},
+		{name: "invalid color value", modify: func(c *model.Config) { c.Colors = map[string]string{"git.bg": "teal"} }, wantErr: "colors.git.bg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			tt.modify(&cfg)
+			segments := tt.segments
+			// Default to built-in segments
+			if segments == nil {
+				segments = model.KnownSegments()
+			}
+			err := cfg.Validate(segments)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_EffectiveColorDepth(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		detected   model.ColorDepth
+		want       model.ColorDepth
+	}{
+		{name: "auto uses detected", configured: model.ColorDepthAuto, detected: model.ColorDepth16, want: model.ColorDepth16},
+		{name: "auto keeps unknown", configured: model.ColorDepthAuto, detected: model.ColorDepthUnknown, want: model.ColorDepthUnknown},
+		{name: "configured wins", configured: "truecolor", detected: model.ColorDepthNone, want: model.ColorDepthTrueColor},
+		{name: "configured none", configured: "none", detected: model.ColorDepth256, want: model.ColorDepthNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.ColorDepth = tt.configured
+			if got := cfg.EffectiveColorDepth(tt.detected); got != tt.want {
+				t.Errorf("EffectiveColorDepth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_EffectiveSeparators(t *testing.T) {
+	tests := []struct {
+		name       string
+		iconSet    string
+		separators string
+		want       string
+	}{
+		{name: "auto with nerd icons", iconSet: model.IconSetNerd, separators: model.SeparatorsAuto, want: model.SeparatorsPowerline},
+		{name: "auto with unicode icons", iconSet: model.IconSetUnicode, separators: model.SeparatorsAuto, want: model.SeparatorsFlat},
+		{name: "explicit set kept", iconSet: model.IconSetASCII, separators: model.SeparatorsRounded, want: model.SeparatorsRounded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.IconSet, cfg.Separators = tt.iconSet, tt.separators
+			if got := cfg.EffectiveSeparators(); got != tt.want {
+				t.Errorf("EffectiveSeparators() = %q, want %q", got, tt.want)
+			}
 		})
 	}
 }
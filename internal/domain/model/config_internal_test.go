@@ -1,6 +1,9 @@
 package model
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestParseBool(t *testing.T) {
 	tests := []struct {
@@ -27,3 +30,42 @@ func TestParseBool(t *testing.T) {
 		})
 	}
 }
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "simple list", input: "os,model", want: []string{"os", "model"}},
+		{name: "spaces and blanks", input: " os , ,git ", want: []string{"os", "git"}},
+		{name: "empty", input: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseList(tt.input); !slices.Equal(got, tt.want) {
+				t.Errorf("parseList(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Layout
+	}{
+		{name: "two lines", input: "os,model;tasks", want: Layout{{"os", "model"}, {"tasks"}}},
+		{name: "blank lines skipped", input: " os ;; ", want: Layout{{"os"}}},
+		{name: "empty", input: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseLayout(tt.input)
+			if !slices.EqualFunc(got, tt.want, slices.Equal[[]string]) {
+				t.Errorf("parseLayout(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package model contains domain entities and value objects.
+package model
+
+import "time"
+
+// CostLevel ranks session spend against the configured thresholds.
+type CostLevel string
+
+// Cost levels, from normal to over the critical threshold.
+const (
+	// CostNormal is spend below the warning threshold.
+	CostNormal CostLevel = ""
+	// CostWarning is spend at or above the warning threshold.
+	CostWarning CostLevel = "warning"
+	// CostCritical is spend at or above the critical threshold.
+	CostCritical CostLevel = "critical"
+)
+
+// SessionCost is what the current Claude Code session has cost so far.
+// Duration is the wall-clock time since the session started; APIDuration
+// is the part spent waiting for API responses.
+type SessionCost struct {
+	USD         float64  `json:"usd"`
+	Duration    Duration `json:"duration"`
+	APIDuration Duration `json:"api_duration"`
+}
+
+// IsZero reports whether the session has neither cost nor duration yet.
+//
+// Returns:
+//   - bool: true before the first API call
+func (c SessionCost) IsZero() bool {
+	// Check every field
+	return c.USD == 0 && c.Duration == 0 && c.APIDuration == 0
+}
+
+// Level ranks the cost, converted to the configured currency, against the thresholds.
+//
+// Params:
+//   - cfg: cost options (rate and thresholds, zero thresholds disabled)
+//
+// Returns:
+//   - CostLevel: highest threshold reached
+func (c SessionCost) Level(cfg CostConfig) CostLevel {
+	amount := cfg.Convert(c.USD)
+	// Check the highest threshold first
+	switch {
+	// Over the critical threshold
+	case cfg.Critical > 0 && amount >= cfg.Critical:
+		// Return critical
+		return CostCritical
+	// Over the warning threshold
+	case cfg.Warning > 0 && amount >= cfg.Warning:
+		// Return warning
+		return CostWarning
+	// Below both
+	default:
+		// Return normal
+		return CostNormal
+	}
+}
+
+// SessionCost returns the session cost and durations.
+//
+// Returns:
+//   - SessionCost: dollar amount, wall-clock and API time
+func (i *Input) SessionCost() SessionCost {
+	// Convert milliseconds to durations
+	return SessionCost{
+		USD:         i.Cost.TotalCostUSD,
+		Duration:    Duration(time.Duration(i.Cost.TotalDurationMs) * time.Millisecond),
+		APIDuration: Duration(time.Duration(i.Cost.TotalAPIDurationMs) * time.Millisecond),
+	}
+}
@@ -0,0 +1,60 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestInput_SessionCost(t *testing.T) {
+	tests := []struct {
+		name  string
+		input model.Input
+		want  model.SessionCost
+	}{
+		{name: "empty", input: model.Input{}, want: model.SessionCost{}},
+		{
+			name:  "converts milliseconds",
+			input: model.Input{Cost: model.InputCost{TotalCostUSD: 1.25, TotalDurationMs: 90000, TotalAPIDurationMs: 1500}},
+			want:  model.SessionCost{USD: 1.25, Duration: model.Duration(90 * time.Second), APIDuration: model.Duration(1500 * time.Millisecond)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.input.SessionCost()
+			if got != tt.want {
+				t.Errorf("SessionCost() = %+v, want %+v", got, tt.want)
+			}
+			if got.IsZero() != (tt.want == model.SessionCost{}) {
+				t.Errorf("IsZero() = %v for %+v", got.IsZero(), got)
+			}
+		})
+	}
+}
+
+func TestSessionCost_Level(t *testing.T) {
+	tests := []struct {
+		name     string
+		usd      float64
+		rate     float64
+		warning  float64
+		critical float64
+		want     model.CostLevel
+	}{
+		{name: "no thresholds", usd: 100, rate: 1, want: model.CostNormal},
+		{name: "below warning", usd: 4.99, rate: 1, warning: 5, critical: 10, want: model.CostNormal},
+		{name: "at warning", usd: 5, rate: 1, warning: 5, critical: 10, want: model.CostWarning},
+		{name: "at critical", usd: 10, rate: 1, warning: 5, critical: 10, want: model.CostCritical},
+		{name: "critical only", usd: 20, rate: 1, critical: 10, want: model.CostCritical},
+		{name: "converted currency", usd: 5, rate: 0.9, warning: 5, want: model.CostNormal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.CostConfig{Rate: tt.rate, Warning: tt.warning, Critical: tt.critical}
+			if got := (model.SessionCost{USD: tt.usd}).Level(cfg); got != tt.want {
+				t.Errorf("Level() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
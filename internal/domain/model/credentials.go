@@ -0,0 +1,95 @@
+// Package model contains domain entities and value objects.
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Credential source names accepted in configuration.
+const (
+	// CredentialEnv reads STATUSLINE_OAUTH_TOKEN, then CLAUDE_CODE_OAUTH_TOKEN.
+	CredentialEnv string = "env"
+	// CredentialCommand runs the token command and reads the token from its output.
+	CredentialCommand string = "command"
+	// CredentialKeychain reads the macOS keychain item written by Claude Code.
+	CredentialKeychain string = "keychain"
+	// CredentialSecretService reads the Linux secret store through secret-tool.
+	CredentialSecretService string = "secret_service"
+	// CredentialFile reads .credentials.json in the Claude configuration directory.
+	CredentialFile string = "file"
+)
+
+// CredentialSources returns every credential source name.
+//
+// Returns:
+//   - []string: credential source names
+func CredentialSources() []string {
+	// Return every credential source
+	return []string{CredentialEnv, CredentialCommand, CredentialKeychain, CredentialSecretService, CredentialFile}
+}
+
+// CredentialConfig says where the usage provider finds its OAuth token.
+// Sources are tried in order until one yields a token. ConfigDir replaces
+// CLAUDE_CONFIG_DIR and ~/.claude, KeychainService names the keychain or
+// secret store item, and TokenCommand is run by the command source.
+type CredentialConfig struct {
+	Sources         []string `json:"sources,omitempty"`
+	ConfigDir       string   `json:"config_dir,omitempty"`
+	KeychainService string   `json:"keychain_service,omitempty"`
+	TokenCommand    string   `json:"token_command,omitempty"`
+}
+
+// Merge overlays the set fields of a profile on the credentials.
+//
+// Params:
+//   - profile: profile credentials, empty fields keep the base value
+//
+// Returns:
+//   - CredentialConfig: merged credentials
+func (cc CredentialConfig) Merge(profile CredentialConfig) CredentialConfig {
+	// Replace the source order
+	if len(profile.Sources) > 0 {
+		cc.Sources = slices.Clone(profile.Sources)
+	}
+	// Replace the configuration directory
+	if profile.ConfigDir != "" {
+		cc.ConfigDir = profile.ConfigDir
+	}
+	// Replace the keychain item
+	if profile.KeychainService != "" {
+		cc.KeychainService = profile.KeychainService
+	}
+	// Replace the token command
+	if profile.TokenCommand != "" {
+		cc.TokenCommand = profile.TokenCommand
+	}
+	// Return merged credentials
+	return cc
+}
+
+// validate checks the source names.
+//
+// Params:
+//   - field: configuration path prefixed to error messages
+//   - required: whether an empty source list is an error
+//
+// Returns:
+//   - []error: validation errors
+func (cc CredentialConfig) validate(field string, required bool) []error {
+	var errs []error
+	// Check that some source is tried
+	if required && len(cc.Sources) == 0 {
+		errs = append(errs, fmt.Errorf("%s.sources: must list at least one source", field))
+	}
+	// Check each source
+	for i, source := range cc.Sources {
+		// Reject unknown sources
+		if !slices.Contains(CredentialSources(), source) {
+			errs = append(errs, fmt.Errorf("%s.sources[%d]: must be one of %s, got %q", field, i, strings.Join(CredentialSources(), ", "), source))
+		}
+	}
+	// Return collected errors
+	return errs
+}
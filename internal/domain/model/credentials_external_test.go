@@ -0,0 +1,41 @@
+package model_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestUsageConfig_ActiveCredentials(t *testing.T) {
+	base := model.CredentialConfig{Sources: []string{"env", "file"}, KeychainService: "Claude Code-credentials"}
+	tests := []struct {
+		name     string
+		profile  string
+		profiles map[string]model.CredentialConfig
+		want     model.CredentialConfig
+	}{
+		{name: "no profile", want: base},
+		{
+			name:     "profile overlays set fields",
+			profile:  "work",
+			profiles: map[string]model.CredentialConfig{"work": {ConfigDir: "~/.claude-work", TokenCommand: "pass claude"}},
+			want:     model.CredentialConfig{Sources: []string{"env", "file"}, ConfigDir: "~/.claude-work", KeychainService: "Claude Code-credentials", TokenCommand: "pass claude"},
+		},
+		{
+			name:     "profile replaces sources",
+			profile:  "ci",
+			profiles: map[string]model.CredentialConfig{"ci": {Sources: []string{"command"}}},
+			want:     model.CredentialConfig{Sources: []string{"command"}, KeychainService: "Claude Code-credentials"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.UsageConfig{Credentials: base, Profile: tt.profile, Profiles: tt.profiles}
+			got := cfg.ActiveCredentials()
+			if !slices.Equal(got.Sources, tt.want.Sources) || got.ConfigDir != tt.want.ConfigDir || got.KeychainService != tt.want.KeychainService || got.TokenCommand != tt.want.TokenCommand {
+				t.Errorf("ActiveCredentials() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package model contains domain entities and value objects.
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration that reads and writes as a string like "90s" or "1h".
+// It is used in configuration files where raw nanoseconds are unreadable.
+type Duration time.Duration
+
+// Std returns the value as a standard library duration.
+//
+// Returns:
+//   - time.Duration: equivalent duration
+func (d Duration) Std() time.Duration {
+	// Convert to standard type
+	return time.Duration(d)
+}
+
+// String returns the duration formatted like time.Duration.
+//
+// Returns:
+//   - string: formatted duration
+func (d Duration) String() string {
+	// Delegate to time.Duration formatting
+	return time.Duration(d).String()
+}
+
+// MarshalJSON encodes the duration as a string.
+//
+// Returns:
+//   - []byte: JSON string
+//   - error: encoding error if any
+func (d Duration) MarshalJSON() ([]byte, error) {
+	// Encode formatted string
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a duration from a string such as "5s".
+//
+// Params:
+//   - data: JSON value
+//
+// Returns:
+//   - error: decoding error if the value is not a valid duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	// Require a JSON string
+	if err := json.Unmarshal(data, &s); err != nil {
+		// Return descriptive error
+		return fmt.Errorf("duration must be a string like \"5s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	// Check for invalid duration syntax
+	if err != nil {
+		// Return parse error
+		return err
+	}
+	*d = Duration(parsed)
+	// Return success
+	return nil
+}
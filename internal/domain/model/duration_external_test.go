@@ -0,0 +1,54 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestDuration_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "seconds", input: `"5s"`, want: 5 * time.Second},
+		{name: "compound", input: `"1h30m"`, want: 90 * time.Minute},
+		{name: "number rejected", input: `5`, wantErr: true},
+		{name: "bad syntax", input: `"five"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d model.Duration
+			err := json.Unmarshal([]byte(tt.input), &d)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && d.Std() != tt.want {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.input, d.Std(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDuration_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input model.Duration
+		want  string
+	}{
+		{name: "hour", input: model.Duration(time.Hour), want: `"1h0m0s"`},
+		{name: "zero", input: 0, want: `"0s"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.input)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("Marshal(%v) = %s, %v, want %s", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
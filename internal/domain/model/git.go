@@ -4,9 +4,9 @@ package model
 // GitStatus represents the current state of a git repository.
 // It contains branch information and change counts.
 type GitStatus struct {
-	Branch    string
-	Modified  int
-	Untracked int
+	Branch    string `json:"branch"`
+	Modified  int    `json:"modified"`
+	Untracked int    `json:"untracked"`
 }
 
 // IsInRepo returns true if currently inside a git repository.
@@ -0,0 +1,64 @@
+// Package model contains domain entities and value objects.
+package model
+
+// Icon set names accepted in configuration.
+const (
+	// IconSetNerd uses Nerd Font glyphs and requires a patched font.
+	IconSetNerd string = "nerd"
+	// IconSetUnicode uses standard Unicode symbols and emoji.
+	IconSetUnicode string = "unicode"
+	// IconSetASCII uses plain ASCII labels.
+	IconSetASCII string = "ascii"
+)
+
+// Separator set names accepted in configuration.
+const (
+	// SeparatorsAuto picks powerline separators for the nerd icon set and flat ones otherwise.
+	SeparatorsAuto string = "auto"
+	// SeparatorsPowerline uses powerline arrows and rounded pill caps.
+	SeparatorsPowerline string = "powerline"
+	// SeparatorsRounded uses half circles for separators and caps.
+	SeparatorsRounded string = "rounded"
+	// SeparatorsSlanted uses diagonal separators and caps.
+	SeparatorsSlanted string = "slanted"
+	// SeparatorsFlat uses plain pipes and no caps.
+	SeparatorsFlat string = "flat"
+)
+
+// IconSets returns every icon set name.
+//
+// Returns:
+//   - []string: icon set names
+func IconSets() []string {
+	// Return every icon set
+	return []string{IconSetNerd, IconSetUnicode, IconSetASCII}
+}
+
+// SeparatorSets returns every separator set name, excluding auto.
+//
+// Returns:
+//   - []string: separator set names
+func SeparatorSets() []string {
+	// Return every separator set
+	return []string{SeparatorsPowerline, SeparatorsRounded, SeparatorsSlanted, SeparatorsFlat}
+}
+
+// EffectiveSeparators returns the separator set to render with.
+// Auto only keeps powerline glyphs when the icon set already needs a Nerd Font.
+//
+// Returns:
+//   - string: separator set name
+func (c Config) EffectiveSeparators() string {
+	// Keep explicit choices
+	if c.Separators != SeparatorsAuto {
+		// Return configured set
+		return c.Separators
+	}
+	// Powerline glyphs come with Nerd Fonts
+	if c.IconSet == IconSetNerd {
+		// Return powerline set
+		return SeparatorsPowerline
+	}
+	// Return font-independent set
+	return SeparatorsFlat
+}
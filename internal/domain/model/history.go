@@ -0,0 +1,188 @@
+// Package model contains domain entities and value objects.
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// History constants.
+const (
+	// daysPerWeek is the number of days in a week.
+	daysPerWeek int = 7
+	// noSample marks trend columns without any sample.
+	noSample int = -1
+)
+
+// UsageSample is one usage reading kept in the history ledger.
+// Keys are short because the ledger stores one sample per line.
+type UsageSample struct {
+	Time    time.Time `json:"t"`
+	Session int       `json:"s"`
+	Weekly  int       `json:"w"`
+}
+
+// NewUsageSample creates a sample dated by the fetch of the usage data, so
+// cached data served by several renders yields the same sample each time.
+//
+// Params:
+//   - data: usage data returned by the usage provider
+//
+// Returns:
+//   - UsageSample: session and weekly utilization when they were fetched
+//   - bool: false when the data is missing, undated, stale or from a failed refresh
+func NewUsageSample(data UsageData) (UsageSample, bool) {
+	// Only record readings the API confirmed at a known time
+	if !data.Session.IsValid() || !data.Weekly.IsValid() || data.Session.Stale || data.Weekly.Stale || data.Status != "" || data.FetchedAt.IsZero() {
+		// Return no sample
+		return UsageSample{}, false
+	}
+	// Return sample
+	return UsageSample{Time: data.FetchedAt, Session: data.Session.Utilization, Weekly: data.Weekly.Utilization}, true
+}
+
+// UsagePeriod summarizes the samples of one day or week.
+// Utilization only grows within a window, so the peak is what was used.
+type UsagePeriod struct {
+	Start   time.Time `json:"start"`
+	Session int       `json:"session"`
+	Weekly  int       `json:"weekly"`
+	Samples int       `json:"samples"`
+}
+
+// UsageHistory is a list of usage samples in time order.
+type UsageHistory []UsageSample
+
+// Since returns the samples taken at or after a time.
+//
+// Params:
+//   - t: earliest sample time
+//
+// Returns:
+//   - UsageHistory: later samples, sharing the receiver's storage
+func (h UsageHistory) Since(t time.Time) UsageHistory {
+	idx, _ := slices.BinarySearchFunc(h, t, func(s UsageSample, t time.Time) int {
+		// Order samples by time
+		return s.Time.Compare(t)
+	})
+	// Return the tail
+	return h[idx:]
+}
+
+// Compact drops samples older than the retention and merges samples older
+// than recent into one per bucket, keeping the peak values and the time of
+// the last sample merged.
+//
+// Params:
+//   - now: current time
+//   - recent: age under which every sample is kept
+//   - bucket: length of the merged periods
+//   - retention: age after which samples are dropped
+//
+// Returns:
+//   - UsageHistory: compacted copy
+func (h UsageHistory) Compact(now time.Time, recent, bucket, retention time.Duration) UsageHistory {
+	kept := h.Since(now.Add(-retention))
+	out := make(UsageHistory, 0, len(kept))
+	// Merge old samples per bucket, copy recent ones
+	for _, s := range kept {
+		last := len(out) - 1
+		// Merge into the previous sample when both are old and share a bucket
+		if last >= 0 && now.Sub(s.Time) > recent && s.Time.Truncate(bucket).Equal(out[last].Time.Truncate(bucket)) {
+			out[last] = UsageSample{Time: s.Time, Session: max(out[last].Session, s.Session), Weekly: max(out[last].Weekly, s.Weekly)}
+			continue
+		}
+		out = append(out, s)
+	}
+	// Return compacted history
+	return out
+}
+
+// Trend returns the peak session utilization of equal columns covering the
+// span before now, oldest first, for a sparkline.
+//
+// Params:
+//   - now: end of the span
+//   - span: covered duration
+//   - columns: number of columns
+//
+// Returns:
+//   - []int: peak utilization per column, -1 for columns without samples
+func (h UsageHistory) Trend(now time.Time, span time.Duration, columns int) []int {
+	// Nothing to split without a span or columns
+	if span <= 0 || columns <= 0 {
+		// Return no columns
+		return nil
+	}
+	trend := make([]int, columns)
+	// Start with empty columns
+	for i := range trend {
+		trend[i] = noSample
+	}
+	start := now.Add(-span)
+	// Place each sample of the span in its column
+	for _, s := range h.Since(start) {
+		col := int(s.Time.Sub(start) * time.Duration(columns) / span)
+		// Ignore samples from the future
+		if col >= columns {
+			continue
+		}
+		trend[col] = max(trend[col], s.Session)
+	}
+	// Return columns
+	return trend
+}
+
+// Summarize groups samples into periods, such as days or weeks.
+//
+// Params:
+//   - period: returns the start of the period a time belongs to
+//
+// Returns:
+//   - []UsagePeriod: periods with samples, oldest first
+func (h UsageHistory) Summarize(period func(time.Time) time.Time) []UsagePeriod {
+	var periods []UsagePeriod
+	// Accumulate samples into their period
+	for _, s := range h {
+		start := period(s.Time)
+		last := len(periods) - 1
+		// Open a new period when the sample is past the current one
+		if last < 0 || !periods[last].Start.Equal(start) {
+			periods = append(periods, UsagePeriod{Start: start})
+			last++
+		}
+		p := &periods[last]
+		p.Session = max(p.Session, s.Session)
+		p.Weekly = max(p.Weekly, s.Weekly)
+		p.Samples++
+	}
+	// Return periods
+	return periods
+}
+
+// StartOfDay returns midnight of the day of t, in t's location.
+//
+// Params:
+//   - t: any time
+//
+// Returns:
+//   - time.Time: start of the day
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	// Return midnight
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight of the Monday starting the week of t, in t's location.
+//
+// Params:
+//   - t: any time
+//
+// Returns:
+//   - time.Time: start of the week
+func StartOfWeek(t time.Time) time.Time {
+	// Count days since Monday (Sunday is the last day of the week)
+	offset := (int(t.Weekday()) + daysPerWeek - 1) % daysPerWeek
+	// Return Monday midnight
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
@@ -0,0 +1,163 @@
+package model_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestNewUsageSample(t *testing.T) {
+	now := time.Now()
+	fresh := model.UsageData{Session: model.NewSessionUsage(40, now.Add(time.Hour)), Weekly: model.NewWeeklyUsage(20, now.Add(time.Hour)), FetchedAt: now}
+	failed := fresh
+	failed.Status = model.UsageUnavailable
+	undated := fresh
+	undated.FetchedAt = time.Time{}
+	tests := []struct {
+		name   string
+		data   model.UsageData
+		wantOK bool
+	}{
+		{name: "fresh", data: fresh, wantOK: true},
+		{name: "missing", data: model.UsageData{}, wantOK: false},
+		{name: "stale", data: fresh.MarkStale(), wantOK: false},
+		{name: "failed refresh", data: failed, wantOK: false},
+		{name: "undated", data: undated, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := model.NewUsageSample(tt.data)
+			if ok != tt.wantOK {
+				t.Fatalf("NewUsageSample() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && (got.Session != 40 || got.Weekly != 20 || !got.Time.Equal(now)) {
+				t.Errorf("NewUsageSample() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestUsageHistory_Compact(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	h := model.UsageHistory{
+		{Time: now.Add(-100 * 24 * time.Hour), Session: 90},
+		{Time: now.Add(-48*time.Hour + 10*time.Minute), Session: 10, Weekly: 5},
+		{Time: now.Add(-48*time.Hour + 20*time.Minute), Session: 30, Weekly: 4},
+		{Time: now.Add(-48*time.Hour + 70*time.Minute), Session: 20},
+		{Time: now.Add(-time.Hour), Session: 50},
+		{Time: now.Add(-time.Hour + time.Minute), Session: 60},
+	}
+	tests := []struct {
+		name        string
+		wantSession []int
+	}{
+		{name: "drops expired, merges old per hour, keeps recent", wantSession: []int{30, 20, 50, 60}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := h.Compact(now, 24*time.Hour, time.Hour, 90*24*time.Hour)
+			var sessions []int
+			for _, s := range got {
+				sessions = append(sessions, s.Session)
+			}
+			if !slices.Equal(sessions, tt.wantSession) {
+				t.Errorf("Compact() sessions = %v, want %v", sessions, tt.wantSession)
+			}
+			if got[0].Weekly != 5 || !got[0].Time.Equal(h[2].Time) {
+				t.Errorf("Compact()[0] = %+v, want peak weekly 5 at the last merged time", got[0])
+			}
+		})
+	}
+}
+
+func TestUsageHistory_Trend(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	h := model.UsageHistory{
+		{Time: now.Add(-5 * time.Hour), Session: 99},
+		{Time: now.Add(-3*time.Hour - 30*time.Minute), Session: 10},
+		{Time: now.Add(-3*time.Hour - 10*time.Minute), Session: 30},
+		{Time: now.Add(-30 * time.Minute), Session: 70},
+	}
+	tests := []struct {
+		name    string
+		span    time.Duration
+		columns int
+		want    []int
+	}{
+		{name: "hourly columns", span: 4 * time.Hour, columns: 4, want: []int{30, -1, -1, 70}},
+		{name: "no span", span: 0, columns: 4, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.Trend(now, tt.span, tt.columns); !slices.Equal(got, tt.want) {
+				t.Errorf("Trend() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsageHistory_Summarize(t *testing.T) {
+	day := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC) // a Monday
+	h := model.UsageHistory{
+		{Time: day, Session: 10, Weekly: 5},
+		{Time: day.Add(5 * time.Hour), Session: 40, Weekly: 8},
+		{Time: day.Add(24 * time.Hour), Session: 20, Weekly: 12},
+		{Time: day.Add(7 * 24 * time.Hour), Session: 5, Weekly: 1},
+	}
+	tests := []struct {
+		name        string
+		period      func(time.Time) time.Time
+		wantStarts  []time.Time
+		wantSession []int
+		wantSamples []int
+	}{
+		{
+			name:        "daily",
+			period:      model.StartOfDay,
+			wantStarts:  []time.Time{model.StartOfDay(day), model.StartOfDay(day).AddDate(0, 0, 1), model.StartOfDay(day).AddDate(0, 0, 7)},
+			wantSession: []int{40, 20, 5},
+			wantSamples: []int{2, 1, 1},
+		},
+		{
+			name:        "weekly",
+			period:      model.StartOfWeek,
+			wantStarts:  []time.Time{model.StartOfDay(day), model.StartOfDay(day).AddDate(0, 0, 7)},
+			wantSession: []int{40, 5},
+			wantSamples: []int{3, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := h.Summarize(tt.period)
+			if len(got) != len(tt.wantStarts) {
+				t.Fatalf("Summarize() = %+v, want %d periods", got, len(tt.wantStarts))
+			}
+			for i, p := range got {
+				if !p.Start.Equal(tt.wantStarts[i]) || p.Session != tt.wantSession[i] || p.Samples != tt.wantSamples[i] {
+					t.Errorf("Summarize()[%d] = %+v, want start %v, session %d, %d samples", i, p, tt.wantStarts[i], tt.wantSession[i], tt.wantSamples[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		t    time.Time
+	}{
+		{name: "monday", t: monday.Add(13 * time.Hour)},
+		{name: "wednesday", t: monday.AddDate(0, 0, 2)},
+		{name: "sunday", t: monday.AddDate(0, 0, 6).Add(23 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := model.StartOfWeek(tt.t); !got.Equal(monday) {
+				t.Errorf("StartOfWeek(%v) = %v, want %v", tt.t, got, monday)
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 // Package model contains domain entities and value objects.
 package model
 
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
 // Default values for input fields.
 const (
 	// defaultModelName is the fallback model name.
@@ -11,13 +17,25 @@ const (
 	defaultContextWindowSize int = 200000
 )
 
+// inputFieldNames lists the top-level keys decoded into Input fields.
+var inputFieldNames []string = jsonFieldNames(reflect.TypeFor[Input]())
+
 // Input represents the JSON input from Claude Code.
 // It contains all the information needed to render the status line.
+// Top-level keys this version does not know are kept in Extra, so newer
+// Claude Code releases can add fields without breaking decoding.
 type Input struct {
-	Model         InputModel     `json:"model"`
-	Workspace     InputWorkspace `json:"workspace"`
-	ContextWindow InputContext   `json:"context_window"`
-	Cost          InputCost      `json:"cost"`
+	SessionID         string                     `json:"session_id"`
+	TranscriptPath    string                     `json:"transcript_path"`
+	CWD               string                     `json:"cwd"`
+	Version           string                     `json:"version"`
+	Model             InputModel                 `json:"model"`
+	Workspace         InputWorkspace             `json:"workspace"`
+	OutputStyle       InputOutputStyle           `json:"output_style"`
+	ContextWindow     InputContext               `json:"context_window"`
+	Cost              InputCost                  `json:"cost"`
+	Exceeds200KTokens bool                       `json:"exceeds_200k_tokens"`
+	Extra             map[string]json.RawMessage `json:"-"`
 }
 
 // InputCost contains cost and code change information from JSON.
@@ -30,16 +48,24 @@ type InputCost struct {
 	TotalLinesRemoved  int     `json:"total_lines_removed"`
 }
 
-// InputModel contains model display information from JSON.
-// It holds the display name of the AI model being used.
+// InputModel contains model information from JSON.
+// It holds the identifier and display name of the AI model being used.
 type InputModel struct {
+	ID          string `json:"id"`
 	DisplayName string `json:"display_name"`
 }
 
 // InputWorkspace contains workspace information from JSON.
-// It holds the current working directory path.
+// It holds the current working directory and the project root.
 type InputWorkspace struct {
 	CurrentDir string `json:"current_dir"`
+	ProjectDir string `json:"project_dir"`
+}
+
+// InputOutputStyle contains the output style from JSON.
+// It holds the name of the style Claude Code answers in.
+type InputOutputStyle struct {
+	Name string `json:"name"`
 }
 
 // InputContext contains context window information from JSON.
@@ -52,10 +78,77 @@ type InputContext struct {
 	RemainingPercentage *float64 `json:"remaining_percentage"`
 }
 
+// UnmarshalJSON decodes the input and keeps unknown top-level keys in Extra.
+//
+// Params:
+//   - data: JSON object sent by Claude Code
+//
+// Returns:
+//   - error: parsing error if any
+func (i *Input) UnmarshalJSON(data []byte) error {
+	// plain drops the methods so decoding does not recurse
+	type plain Input
+	var decoded plain
+	// Check for parsing errors
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		// Return parsing error
+		return err
+	}
+	var fields map[string]json.RawMessage
+	// Check for non-object input
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Return parsing error
+		return err
+	}
+	// Drop keys already decoded into fields
+	for _, name := range inputFieldNames {
+		delete(fields, name)
+	}
+	// Keep the remaining keys
+	if len(fields) > 0 {
+		decoded.Extra = fields
+	}
+	*i = Input(decoded)
+	// Return success
+	return nil
+}
+
+// MarshalJSON encodes the input with its unknown keys, so it round-trips.
+//
+// Returns:
+//   - []byte: JSON object
+//   - error: encoding error if any
+func (i Input) MarshalJSON() ([]byte, error) {
+	// plain drops the methods so encoding does not recurse
+	type plain Input
+	data, err := json.Marshal(plain(i))
+	// Nothing to merge without unknown keys
+	if err != nil || len(i.Extra) == 0 {
+		// Return known fields
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	// Check for decoding errors
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Return decoding error
+		return nil, err
+	}
+	// Add unknown keys without overriding known fields
+	for name, value := range i.Extra {
+		// Skip keys shadowed by known fields
+		if _, known := fields[name]; known {
+			continue
+		}
+		fields[name] = value
+	}
+	// Return merged object
+	return json.Marshal(fields)
+}
+
 // ModelInfo returns parsed model information.
 //
 // Returns:
-//   - ModelInfo: parsed model name and version
+//   - ModelInfo: model identifier and parsed name and version
 func (i *Input) ModelInfo() ModelInfo {
 	name := i.Model.DisplayName
 	// Use default if display name is empty
@@ -64,21 +157,65 @@ func (i *Input) ModelInfo() ModelInfo {
 	}
 	baseName, version := parseModelName(name)
 	// Return parsed model info
-	return ModelInfo{Name: baseName, Version: version}
+	return ModelInfo{ID: i.Model.ID, Name: baseName, Version: version}
 }
 
 // WorkingDir returns the working directory.
+// The workspace directory wins over the top-level cwd.
 //
 // Returns:
 //   - string: current working directory or default
 func (i *Input) WorkingDir() string {
-	// Use default if directory is empty
-	if i.Workspace.CurrentDir == "" {
+	// Select the first directory set
+	switch {
+	// Workspace directory
+	case i.Workspace.CurrentDir != "":
+		// Return workspace directory
+		return i.Workspace.CurrentDir
+	// Top-level cwd
+	case i.CWD != "":
+		// Return cwd
+		return i.CWD
+	// Nothing sent
+	default:
 		// Return fallback directory
 		return defaultWorkingDir
 	}
-	// Return configured directory
-	return i.Workspace.CurrentDir
+}
+
+// Session returns the session information.
+//
+// Returns:
+//   - SessionInfo: session identifiers, project root and client details
+func (i *Input) Session() SessionInfo {
+	projectDir := i.Workspace.ProjectDir
+	// Fall back to the working directory outside a project
+	if projectDir == "" {
+		projectDir = i.WorkingDir()
+	}
+	// Return session information
+	return SessionInfo{
+		ID:                i.SessionID,
+		TranscriptPath:    i.TranscriptPath,
+		ProjectDir:        projectDir,
+		ClientVersion:     i.Version,
+		OutputStyle:       i.OutputStyle.Name,
+		Exceeds200KTokens: i.Exceeds200KTokens,
+	}
+}
+
+// Field returns the raw value of a top-level key this version does not decode.
+//
+// Params:
+//   - name: JSON key
+//
+// Returns:
+//   - json.RawMessage: raw value
+//   - bool: false when the key was not sent or is a known field
+func (i *Input) Field(name string) (json.RawMessage, bool) {
+	value, ok := i.Extra[name]
+	// Return raw value
+	return value, ok
 }
 
 // ContextWindowSize returns the context window size.
@@ -111,13 +248,36 @@ func (i *Input) TotalTokens() int {
 // Returns:
 //   - Progress: calculated progress based on context usage
 func (i *Input) Progress() Progress {
+	// Return progress without transcript data
+	return i.ContextProgress(TranscriptStats{})
+}
+
+// ContextProgress returns the context usage progress, using the transcript
+// when Claude Code did not send used_percentage. The last response's tokens
+// reflect compactions, unlike the cumulative totals used as a last resort.
+//
+// Params:
+//   - stats: transcript summary, zero when unavailable
+//
+// Returns:
+//   - Progress: calculated progress based on context usage
+func (i *Input) ContextProgress(stats TranscriptStats) Progress {
+	// Select the most accurate source
+	switch {
 	// Prefer pre-calculated percentage (reflects actual context window state)
-	if i.ContextWindow.UsedPercentage != nil {
+	case i.ContextWindow.UsedPercentage != nil:
 		percent := int(*i.ContextWindow.UsedPercentage)
+		// Return reported percentage
 		return Progress{Percent: min(percent, maxPercent)}
-	}
+	// Use the tokens of the last response
+	case !stats.Tokens.IsZero():
+		// Return transcript progress
+		return stats.Progress(i.ContextWindowSize())
 	// Fallback to token-based calculation
-	return NewProgress(i.TotalTokens(), i.ContextWindowSize())
+	default:
+		// Return cumulative progress
+		return NewProgress(i.TotalTokens(), i.ContextWindowSize())
+	}
 }
 
 // CodeChanges returns the lines added and removed.
@@ -152,3 +312,30 @@ func parseModelName(name string) (baseName, version string) {
 	// Return name only if no version found
 	return name, ""
 }
+
+// jsonFieldNames returns the JSON keys of a struct's encoded fields.
+//
+// Params:
+//   - t: struct type
+//
+// Returns:
+//   - []string: keys from the json tags, or field names without a tag
+func jsonFieldNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	// Collect each field's key
+	for idx := range t.NumField() {
+		field := t.Field(idx)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		// Skip fields never encoded
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		// Untagged fields use their Go name
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	// Return keys
+	return names
+}
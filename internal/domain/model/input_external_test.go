@@ -1,6 +1,7 @@
 package model_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/florent/status-line/internal/domain/model"
@@ -50,6 +51,8 @@ func TestInput_WorkingDir(t *testing.T) {
 	}{
 		{name: "empty uses default", input: model.Input{}, want: "~"},
 		{name: "custom dir", input: model.Input{Workspace: model.InputWorkspace{CurrentDir: "/workspace"}}, want: "/workspace"},
+		{name: "cwd fallback", input: model.Input{CWD: "/cwd"}, want: "/cwd"},
+		{name: "workspace over cwd", input: model.Input{CWD: "/cwd", Workspace: model.InputWorkspace{CurrentDir: "/workspace"}}, want: "/workspace"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -119,6 +122,28 @@ func TestInput_Progress(t *testing.T) {
 	}
 }
 
+func TestInput_ContextProgress(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+	stats := model.TranscriptStats{Tokens: model.TokenUsage{Input: 2000, CacheRead: 40000, CacheCreation: 6000, Output: 2000}}
+	tests := []struct {
+		name        string
+		input       model.Input
+		stats       model.TranscriptStats
+		wantPercent int
+	}{
+		{name: "used_percentage preferred", input: model.Input{ContextWindow: model.InputContext{UsedPercentage: pct(10), ContextWindowSize: 200000}}, stats: stats, wantPercent: 10},
+		{name: "transcript over totals", input: model.Input{ContextWindow: model.InputContext{TotalInputTokens: 180000, ContextWindowSize: 200000}}, stats: stats, wantPercent: 25},
+		{name: "totals without transcript", input: model.Input{ContextWindow: model.InputContext{TotalInputTokens: 100000, ContextWindowSize: 200000}}, wantPercent: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p := tt.input.ContextProgress(tt.stats); p.Percent != tt.wantPercent {
+				t.Errorf("ContextProgress() = %d%%, want %d%%", p.Percent, tt.wantPercent)
+			}
+		})
+	}
+}
+
 func TestInput_CodeChanges(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -138,3 +163,101 @@ func TestInput_CodeChanges(t *testing.T) {
 		})
 	}
 }
+
+func TestInput_UnmarshalJSON(t *testing.T) {
+	const full = `{
+		"hook_event_name": "Status",
+		"session_id": "abc123",
+		"transcript_path": "/tmp/abc123.jsonl",
+		"cwd": "/work/sub",
+		"model": {"id": "claude-opus-4-5-20251101", "display_name": "Opus 4.5"},
+		"workspace": {"current_dir": "/work/sub", "project_dir": "/work"},
+		"version": "2.0.30",
+		"output_style": {"name": "Explanatory"},
+		"exceeds_200k_tokens": true,
+		"future": {"key": 1}
+	}`
+	tests := []struct {
+		name        string
+		data        string
+		wantSession model.SessionInfo
+		wantModelID string
+		wantExtra   []string
+	}{
+		{
+			name: "full schema",
+			data: full,
+			wantSession: model.SessionInfo{
+				ID:                "abc123",
+				TranscriptPath:    "/tmp/abc123.jsonl",
+				ProjectDir:        "/work",
+				ClientVersion:     "2.0.30",
+				OutputStyle:       "Explanatory",
+				Exceeds200KTokens: true,
+			},
+			wantModelID: "claude-opus-4-5-20251101",
+			wantExtra:   []string{"hook_event_name", "future"},
+		},
+		{
+			name:        "minimal input",
+			data:        `{"model": {"display_name": "Sonnet"}, "cwd": "/cwd"}`,
+			wantSession: model.SessionInfo{ProjectDir: "/cwd"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input model.Input
+			if err := json.Unmarshal([]byte(tt.data), &input); err != nil {
+				t.Fatalf("Unmarshal() = %v", err)
+			}
+			if got := input.Session(); got != tt.wantSession {
+				t.Errorf("Session() = %+v, want %+v", got, tt.wantSession)
+			}
+			if got := input.ModelInfo().ID; got != tt.wantModelID {
+				t.Errorf("ModelInfo().ID = %q, want %q", got, tt.wantModelID)
+			}
+			if len(input.Extra) != len(tt.wantExtra) {
+				t.Errorf("Extra = %v, want keys %v", input.Extra, tt.wantExtra)
+			}
+			for _, key := range tt.wantExtra {
+				if _, ok := input.Field(key); !ok {
+					t.Errorf("Field(%q) missing", key)
+				}
+			}
+			if _, ok := input.Field("session_id"); ok {
+				t.Error("Field(\"session_id\") returned a known field")
+			}
+		})
+	}
+}
+
+func TestInput_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		key  string
+		want string
+	}{
+		{name: "keeps unknown keys", data: `{"session_id":"s","future":{"key":1}}`, key: "future", want: `{"key":1}`},
+		{name: "keeps known keys", data: `{"session_id":"s","future":2}`, key: "session_id", want: `"s"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var input model.Input
+			if err := json.Unmarshal([]byte(tt.data), &input); err != nil {
+				t.Fatalf("Unmarshal() = %v", err)
+			}
+			out, err := json.Marshal(input)
+			if err != nil {
+				t.Fatalf("Marshal() = %v", err)
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(out, &fields); err != nil {
+				t.Fatalf("Unmarshal(Marshal()) = %v", err)
+			}
+			if got := string(fields[tt.key]); got != tt.want {
+				t.Errorf("Marshal()[%q] = %s, want %s", tt.key, got, tt.want)
+			}
+		})
+	}
+}
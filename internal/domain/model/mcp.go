@@ -4,8 +4,8 @@ package model
 // MCPServer represents an MCP server configuration.
 // It holds the server name and enabled status.
 type MCPServer struct {
-	Name    string
-	Enabled bool
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
 }
 
 // MCPServers is a list of MCP server configurations.
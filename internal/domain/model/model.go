@@ -1,11 +1,42 @@
 // Package model contains domain entities and value objects.
 package model
 
+import (
+	"strings"
+	"unicode"
+)
+
+// modelIDPrefix starts every Claude model identifier.
+const modelIDPrefix string = "claude"
+
 // ModelInfo contains AI model information.
-// It holds the model name and version separately.
+// It holds the model identifier, such as "claude-opus-4-5-20251101", and the
+// display name split into name and version.
 type ModelInfo struct {
-	Name    string
-	Version string
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Family returns the lowercase model family, such as "opus".
+// The identifier is preferred; the display name is used when the identifier
+// is missing or holds no family.
+//
+// Returns:
+//   - string: model family, empty when neither identifier nor name is set
+func (m ModelInfo) Family() string {
+	// Take the first word of the identifier that is neither the prefix nor a version
+	for part := range strings.SplitSeq(strings.ToLower(m.ID), "-") {
+		// Skip the prefix, version numbers and dates
+		if part == "" || part == modelIDPrefix || strings.IndexFunc(part, unicode.IsLetter) < 0 {
+			continue
+		}
+		// Return family from the identifier
+		return part
+	}
+	family, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(m.Name)), " ")
+	// Return family from the display name
+	return family
 }
 
 // FullName returns the complete model name.
@@ -24,3 +24,25 @@ func TestModelInfo_FullName(t *testing.T) {
 		})
 	}
 }
+
+func TestModelInfo_Family(t *testing.T) {
+	tests := []struct {
+		name string
+		info model.ModelInfo
+		want string
+	}{
+		{name: "current id", info: model.ModelInfo{ID: "claude-opus-4-5-20251101", Name: "Opus"}, want: "opus"},
+		{name: "legacy id", info: model.ModelInfo{ID: "claude-3-5-haiku-20241022"}, want: "haiku"},
+		{name: "id over name", info: model.ModelInfo{ID: "claude-sonnet-4-5", Name: "Custom"}, want: "sonnet"},
+		{name: "id without family", info: model.ModelInfo{ID: "claude-4", Name: "Opus 4"}, want: "opus"},
+		{name: "name only", info: model.ModelInfo{Name: "Sonnet 4"}, want: "sonnet"},
+		{name: "empty", info: model.ModelInfo{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.Family(); got != tt.want {
+				t.Errorf("Family() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
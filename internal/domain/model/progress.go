@@ -32,7 +32,7 @@ const (
 // Progress represents context window usage.
 // It tracks the percentage of context window capacity used.
 type Progress struct {
-	Percent int
+	Percent int `json:"percent"`
 }
 
 // NewProgress creates a Progress from token counts.
@@ -0,0 +1,14 @@
+// Package model contains domain entities and value objects.
+package model
+
+// SessionInfo describes the Claude Code session being rendered.
+// ProjectDir is the directory Claude Code was started in, which stays the
+// same when the working directory changes.
+type SessionInfo struct {
+	ID                string `json:"id,omitempty"`
+	TranscriptPath    string `json:"transcript_path,omitempty"`
+	ProjectDir        string `json:"project_dir,omitempty"`
+	ClientVersion     string `json:"client_version,omitempty"`
+	OutputStyle       string `json:"output_style,omitempty"`
+	Exceeds200KTokens bool   `json:"exceeds_200k_tokens,omitempty"`
+}
@@ -2,27 +2,39 @@
 package model
 
 // StatusLineData contains all data needed to render the status line.
-// It aggregates information from all sources for rendering.
+// It aggregates information from all sources for rendering. Late lists the
+// providers that missed their deadline; their fields hold zero values.
+// Changes holds the git counts of the configured changes source and
+// SessionChanges the counts Claude Code reported, each when shown.
+// Transcript summarises the session transcript when it could be read.
 type StatusLineData struct {
-	Model       ModelInfo
-	Progress    Progress
-	Session     Usage
-	Usage       Usage
-	Icons       IconConfig
-	Git         GitStatus
-	System      SystemInfo
-	Terminal    TerminalInfo
-	Dir         string
-	Time        string
-	Changes     CodeChanges
-	MCP         MCPServers
-	Taskwarrior TaskwarriorInfo
-	Update      UpdateInfo
+	Model          ModelInfo       `json:"model"`
+	SessionInfo    SessionInfo     `json:"session_info,omitzero"`
+	Progress       Progress        `json:"progress"`
+	Session        Usage           `json:"session,omitzero"`
+	Usage          Usage           `json:"weekly,omitzero"`
+	UsageWindows   UsageWindows    `json:"usage_windows,omitempty"`
+	UsageStatus    UsageStatus     `json:"usage_status,omitempty"`
+	History        UsageHistory    `json:"history,omitempty"`
+	Icons          IconConfig      `json:"icons"`
+	Git            GitStatus       `json:"git,omitzero"`
+	System         SystemInfo      `json:"system"`
+	Terminal       TerminalInfo    `json:"terminal"`
+	Dir            string          `json:"dir"`
+	Time           string          `json:"time"`
+	Changes        CodeChanges     `json:"changes"`
+	SessionChanges CodeChanges     `json:"session_changes,omitzero"`
+	Cost           SessionCost     `json:"cost,omitzero"`
+	Transcript     TranscriptStats `json:"transcript,omitzero"`
+	MCP            MCPServers      `json:"mcp"`
+	Taskwarrior    TaskwarriorInfo `json:"taskwarrior,omitzero"`
+	Update         UpdateInfo      `json:"update,omitzero"`
+	Late           []string        `json:"late,omitempty"`
 }
 
 // UpdateInfo contains information about available updates.
 // Used to display update notification in the status line.
 type UpdateInfo struct {
-	Available bool
-	Version   string
+	Available bool   `json:"available"`
+	Version   string `json:"version,omitempty"`
 }
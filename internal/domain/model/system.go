@@ -17,9 +17,57 @@ const (
 	OSUnknown
 )
 
+// Operating system names used in JSON output.
+const (
+	// osNameLinux is the name of OSLinux.
+	osNameLinux string = "linux"
+	// osNameDarwin is the name of OSDarwin.
+	osNameDarwin string = "darwin"
+	// osNameWindows is the name of OSWindows.
+	osNameWindows string = "windows"
+	// osNameUnknown is the name of OSUnknown.
+	osNameUnknown string = "unknown"
+)
+
 // SystemInfo contains system information.
 // It holds OS type and Docker status.
 type SystemInfo struct {
-	OS       OSType
-	IsDocker bool
+	OS       OSType `json:"os"`
+	IsDocker bool   `json:"is_docker"`
+}
+
+// String returns the operating system name.
+//
+// Returns:
+//   - string: OS name
+func (o OSType) String() string {
+	// Match by value
+	switch o {
+	// Linux
+	case OSLinux:
+		// Return name
+		return osNameLinux
+	// macOS
+	case OSDarwin:
+		// Return name
+		return osNameDarwin
+	// Windows
+	case OSWindows:
+		// Return name
+		return osNameWindows
+	// Anything else
+	default:
+		// Return name
+		return osNameUnknown
+	}
+}
+
+// MarshalText encodes the operating system as its name.
+//
+// Returns:
+//   - []byte: OS name
+//   - error: always nil
+func (o OSType) MarshalText() ([]byte, error) {
+	// Return name bytes
+	return []byte(o.String()), nil
 }
@@ -10,9 +10,9 @@ const (
 // TaskwarriorInfo contains Taskwarrior task information.
 // It holds project-level statistics and active session data.
 type TaskwarriorInfo struct {
-	Installed     bool
-	ActiveProject *TaskwarriorProject  // Project with active session (Epic/Task workflow)
-	Projects      []TaskwarriorProject // Legacy projects (simple pending/completed)
+	Installed     bool                 `json:"installed"`
+	ActiveProject *TaskwarriorProject  `json:"active_project,omitempty"` // Project with active session (Epic/Task workflow)
+	Projects      []TaskwarriorProject `json:"projects,omitempty"`       // Legacy projects (simple pending/completed)
 }
 
 // HasProjects returns true if there are any projects.
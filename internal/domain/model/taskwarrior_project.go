@@ -29,31 +29,31 @@ const (
 // It tracks pending and completed task counts for a single project.
 // Supports both legacy mode (simple counts) and session mode (Epic/Task workflow).
 type TaskwarriorProject struct {
-	Name        string
-	Pending     int
-	Completed   int
-	Mode        TaskwarriorMode   // Workflow mode (plan/bypass)
-	Epics       []TaskwarriorEpic // Epic hierarchy with tasks
-	CurrentEpic int               // Currently active epic number
-	CurrentTask string            // Currently active task ID (e.g., "2.1")
+	Name        string            `json:"name"`
+	Pending     int               `json:"pending"`
+	Completed   int               `json:"completed"`
+	Mode        TaskwarriorMode   `json:"mode,omitempty"`         // Workflow mode (plan/bypass)
+	Epics       []TaskwarriorEpic `json:"epics,omitempty"`        // Epic hierarchy with tasks
+	CurrentEpic int               `json:"current_epic,omitempty"` // Currently active epic number
+	CurrentTask string            `json:"current_task,omitempty"` // Currently active task ID (e.g., "2.1")
 }
 
 // TaskwarriorEpic represents an epic containing multiple tasks.
 type TaskwarriorEpic struct {
-	ID         int
-	Name       string
-	Status     TaskwarriorStatus
-	Tasks      []TaskwarriorTask
-	DoneCount  int
-	TotalCount int
+	ID         int               `json:"id"`
+	Name       string            `json:"name"`
+	Status     TaskwarriorStatus `json:"status"`
+	Tasks      []TaskwarriorTask `json:"tasks,omitempty"`
+	DoneCount  int               `json:"done_count"`
+	TotalCount int               `json:"total_count"`
 }
 
 // TaskwarriorTask represents a single task within an epic.
 type TaskwarriorTask struct {
-	ID       string // e.g., "1.1", "2.3"
-	Name     string
-	Status   TaskwarriorStatus
-	Parallel bool
+	ID       string            `json:"id"` // e.g., "1.1", "2.3"
+	Name     string            `json:"name"`
+	Status   TaskwarriorStatus `json:"status"`
+	Parallel bool              `json:"parallel,omitempty"`
 }
 
 // Total returns total tasks in the project.
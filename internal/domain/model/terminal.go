@@ -1,8 +1,117 @@
 // Package model contains domain entities and value objects.
 package model
 
+// ColorDepth is the number of colors a terminal can display.
+type ColorDepth int
+
+// Color depth values.
+const (
+	// ColorDepthUnknown means the depth was not detected; renderers use 256 colors.
+	ColorDepthUnknown ColorDepth = iota
+	// ColorDepthNone disables all colors and text attributes.
+	ColorDepthNone
+	// ColorDepth16 uses the 16 basic ANSI colors.
+	ColorDepth16
+	// ColorDepth256 uses the 256-color palette.
+	ColorDepth256
+	// ColorDepthTrueColor uses 24-bit colors.
+	ColorDepthTrueColor
+)
+
+// Color depth names accepted in configuration.
+const (
+	// ColorDepthAuto detects the depth from the environment.
+	ColorDepthAuto string = "auto"
+	// colorDepthNameNone is the name of ColorDepthNone.
+	colorDepthNameNone string = "none"
+	// colorDepthName16 is the name of ColorDepth16.
+	colorDepthName16 string = "16"
+	// colorDepthName256 is the name of ColorDepth256.
+	colorDepthName256 string = "256"
+	// colorDepthNameTrueColor is the name of ColorDepthTrueColor.
+	colorDepthNameTrueColor string = "truecolor"
+	// colorDepthNameUnknown is the name of ColorDepthUnknown.
+	colorDepthNameUnknown string = "unknown"
+)
+
 // TerminalInfo contains terminal information.
-// It holds terminal dimensions for rendering.
+// It holds terminal dimensions and capabilities for rendering.
 type TerminalInfo struct {
-	Width int
+	Width      int        `json:"width"`
+	ColorDepth ColorDepth `json:"color_depth"`
+}
+
+// ParseColorDepth converts a configured depth name to a ColorDepth.
+// "auto" is not a depth and is handled by the caller.
+//
+// Params:
+//   - name: depth name (none, 16, 256, truecolor)
+//
+// Returns:
+//   - ColorDepth: matching depth
+//   - bool: true if the name is known
+func ParseColorDepth(name string) (ColorDepth, bool) {
+	// Match by name
+	switch name {
+	// No colors
+	case colorDepthNameNone:
+		// Return no colors
+		return ColorDepthNone, true
+	// Basic colors
+	case colorDepthName16:
+		// Return 16 colors
+		return ColorDepth16, true
+	// Palette colors
+	case colorDepthName256:
+		// Return 256 colors
+		return ColorDepth256, true
+	// 24-bit colors
+	case colorDepthNameTrueColor:
+		// Return true color
+		return ColorDepthTrueColor, true
+	// Unknown name
+	default:
+		// Return not found
+		return ColorDepthUnknown, false
+	}
+}
+
+// String returns the configuration name of the depth.
+//
+// Returns:
+//   - string: depth name
+func (d ColorDepth) String() string {
+	// Match by value
+	switch d {
+	// No colors
+	case ColorDepthNone:
+		// Return name
+		return colorDepthNameNone
+	// Basic colors
+	case ColorDepth16:
+		// Return name
+		return colorDepthName16
+	// Palette colors
+	case ColorDepth256:
+		// Return name
+		return colorDepthName256
+	// 24-bit colors
+	case ColorDepthTrueColor:
+		// Return name
+		return colorDepthNameTrueColor
+	// Not detected
+	default:
+		// Return name
+		return colorDepthNameUnknown
+	}
+}
+
+// MarshalText encodes the depth as its name.
+//
+// Returns:
+//   - []byte: depth name
+//   - error: always nil
+func (d ColorDepth) MarshalText() ([]byte, error) {
+	// Return name bytes
+	return []byte(d.String()), nil
 }
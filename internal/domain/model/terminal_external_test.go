@@ -0,0 +1,34 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestParseColorDepth(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   model.ColorDepth
+		wantOK bool
+	}{
+		{name: "none", input: "none", want: model.ColorDepthNone, wantOK: true},
+		{name: "basic", input: "16", want: model.ColorDepth16, wantOK: true},
+		{name: "palette", input: "256", want: model.ColorDepth256, wantOK: true},
+		{name: "true color", input: "truecolor", want: model.ColorDepthTrueColor, wantOK: true},
+		{name: "auto is not a depth", input: "auto", want: model.ColorDepthUnknown, wantOK: false},
+		{name: "unknown", input: "88", want: model.ColorDepthUnknown, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := model.ParseColorDepth(tt.input)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseColorDepth(%q) = %v, %v, want %v, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+			if ok && got.String() != tt.input {
+				t.Errorf("String() = %q, want %q", got.String(), tt.input)
+			}
+		})
+	}
+}
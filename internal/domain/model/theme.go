@@ -0,0 +1,197 @@
+// Package model contains domain entities and value objects.
+package model
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Built-in theme names accepted in configuration.
+const (
+	// ThemeDefault is the original pastel 256-color theme.
+	ThemeDefault string = "default"
+	// ThemeSolarized uses the Solarized accent palette.
+	ThemeSolarized string = "solarized"
+	// ThemeNord uses the Nord palette.
+	ThemeNord string = "nord"
+	// ThemeHighContrast uses saturated backgrounds with black or white text.
+	ThemeHighContrast string = "high-contrast"
+	// ThemeMonochrome uses shades of gray only.
+	ThemeMonochrome string = "monochrome"
+)
+
+// Semantic color roles a theme maps to colors.
+// Background roles also color the caps and separators next to them.
+const (
+	// RoleOSBg is the OS segment background.
+	RoleOSBg string = "os.bg"
+	// RoleOSFg is the OS segment text.
+	RoleOSFg string = "os.fg"
+	// RoleModelHaikuBg is the model segment background for Haiku.
+	RoleModelHaikuBg string = "model.haiku.bg"
+	// RoleModelHaikuFg is the model segment text for Haiku.
+	RoleModelHaikuFg string = "model.haiku.fg"
+	// RoleModelSonnetBg is the model segment background for Sonnet.
+	RoleModelSonnetBg string = "model.sonnet.bg"
+	// RoleModelSonnetFg is the model segment text for Sonnet.
+	RoleModelSonnetFg string = "model.sonnet.fg"
+	// RoleModelOpusBg is the model segment background for Opus.
+	RoleModelOpusBg string = "model.opus.bg"
+	// RoleModelOpusFg is the model segment text for Opus.
+	RoleModelOpusFg string = "model.opus.fg"
+	// RoleModelOtherBg is the model segment background for unknown models.
+	RoleModelOtherBg string = "model.other.bg"
+	// RoleModelOtherFg is the model segment text for unknown models.
+	RoleModelOtherFg string = "model.other.fg"
+	// RoleWeeklyBg is the weekly usage segment background.
+	RoleWeeklyBg string = "weekly.bg"
+	// RoleWeeklyFg is the weekly usage segment text.
+	RoleWeeklyFg string = "weekly.fg"
+	// RoleSparklineBg is the usage trend segment background.
+	RoleSparklineBg string = "sparkline.bg"
+	// RoleSparklineFg is the usage trend segment text.
+	RoleSparklineFg string = "sparkline.fg"
+	// RolePathBg is the path segment background.
+	RolePathBg string = "path.bg"
+	// RolePathFg is the path segment text.
+	RolePathFg string = "path.fg"
+	// RoleGitBg is the git segment background.
+	RoleGitBg string = "git.bg"
+	// RoleGitFg is the git segment text.
+	RoleGitFg string = "git.fg"
+	// RoleChangesAddedBg is the lines added background.
+	RoleChangesAddedBg string = "changes.added.bg"
+	// RoleChangesAddedFg is the lines added text.
+	RoleChangesAddedFg string = "changes.added.fg"
+	// RoleChangesRemovedBg is the lines removed background.
+	RoleChangesRemovedBg string = "changes.removed.bg"
+	// RoleChangesRemovedFg is the lines removed text.
+	RoleChangesRemovedFg string = "changes.removed.fg"
+	// RoleMCPEnabledBg is the enabled MCP server pill background.
+	RoleMCPEnabledBg string = "mcp.enabled.bg"
+	// RoleMCPEnabledFg is the enabled MCP server pill text.
+	RoleMCPEnabledFg string = "mcp.enabled.fg"
+	// RoleMCPDisabledBg is the disabled MCP server pill background.
+	RoleMCPDisabledBg string = "mcp.disabled.bg"
+	// RoleMCPDisabledFg is the disabled MCP server pill text.
+	RoleMCPDisabledFg string = "mcp.disabled.fg"
+	// RoleCostBg is the session cost segment background.
+	RoleCostBg string = "cost.bg"
+	// RoleCostFg is the session cost segment text.
+	RoleCostFg string = "cost.fg"
+	// RoleCostWarningBg is the cost segment background over the warning threshold.
+	RoleCostWarningBg string = "cost.warning.bg"
+	// RoleCostWarningFg is the cost segment text over the warning threshold.
+	RoleCostWarningFg string = "cost.warning.fg"
+	// RoleCostCriticalBg is the cost segment background over the critical threshold.
+	RoleCostCriticalBg string = "cost.critical.bg"
+	// RoleCostCriticalFg is the cost segment text over the critical threshold.
+	RoleCostCriticalFg string = "cost.critical.fg"
+	// RoleActivityBg is the activity segment background.
+	RoleActivityBg string = "activity.bg"
+	// RoleActivityFg is the activity segment text.
+	RoleActivityFg string = "activity.fg"
+	// RoleTasksBg is the Taskwarrior pill background.
+	RoleTasksBg string = "tasks.bg"
+	// RoleTasksFg is the Taskwarrior pill text and completed progress.
+	RoleTasksFg string = "tasks.fg"
+	// RoleTasksBarBg is the nested progress pill background.
+	RoleTasksBarBg string = "tasks.bar.bg"
+	// RoleTasksBarFg is the nested progress pill task count text.
+	RoleTasksBarFg string = "tasks.bar.fg"
+	// RoleTasksProgress is the incomplete project progress bar.
+	RoleTasksProgress string = "tasks.progress"
+	// RoleTasksMuted is used for separators and secondary text.
+	RoleTasksMuted string = "tasks.muted"
+	// RoleTasksDone is the done part of the epic bar.
+	RoleTasksDone string = "tasks.done"
+	// RoleTasksWip is the in-progress marker of the epic bar.
+	RoleTasksWip string = "tasks.wip"
+	// RoleTasksTodo is the todo part of the epic bar.
+	RoleTasksTodo string = "tasks.todo"
+	// RoleTasksCurrent is the current task indicator.
+	RoleTasksCurrent string = "tasks.current"
+	// RoleUpdateBg is the update pill background.
+	RoleUpdateBg string = "update.bg"
+	// RoleUpdateFg is the update pill text.
+	RoleUpdateFg string = "update.fg"
+	// RoleCursor is the burn-rate cursor on progress bars.
+	RoleCursor string = "cursor"
+	// RolePaceOnTrack is the usage bar and forecast when usage is sustainable.
+	RolePaceOnTrack string = "pace.on_track"
+	// RolePaceAhead is the usage bar and forecast when usage runs slightly fast.
+	RolePaceAhead string = "pace.ahead"
+	// RolePaceOver is the usage bar and forecast when usage runs much too fast.
+	RolePaceOver string = "pace.over"
+)
+
+// ThemeFile is a user-supplied theme read from a JSON file.
+// Roles missing from Colors keep the value from the Base theme.
+type ThemeFile struct {
+	Base   string            `json:"base"`
+	Colors map[string]string `json:"colors"`
+}
+
+// BuiltinThemes returns the names of the built-in themes.
+//
+// Returns:
+//   - []string: built-in theme names
+func BuiltinThemes() []string {
+	// Return every built-in theme
+	return []string{ThemeDefault, ThemeSolarized, ThemeNord, ThemeHighContrast, ThemeMonochrome}
+}
+
+// ThemeRoles returns every semantic color role.
+//
+// Returns:
+//   - []string: role identifiers
+func ThemeRoles() []string {
+	// Return every role
+	return []string{
+		RoleOSBg, RoleOSFg,
+		RoleModelHaikuBg, RoleModelHaikuFg, RoleModelSonnetBg, RoleModelSonnetFg,
+		RoleModelOpusBg, RoleModelOpusFg, RoleModelOtherBg, RoleModelOtherFg,
+		RoleWeeklyBg, RoleWeeklyFg, RoleSparklineBg, RoleSparklineFg, RolePathBg, RolePathFg, RoleGitBg, RoleGitFg,
+		RoleChangesAddedBg, RoleChangesAddedFg, RoleChangesRemovedBg, RoleChangesRemovedFg,
+		RoleMCPEnabledBg, RoleMCPEnabledFg, RoleMCPDisabledBg, RoleMCPDisabledFg,
+		RoleCostBg, RoleCostFg, RoleCostWarningBg, RoleCostWarningFg, RoleCostCriticalBg, RoleCostCriticalFg,
+		RoleActivityBg, RoleActivityFg,
+		RoleTasksBg, RoleTasksFg, RoleTasksBarBg, RoleTasksBarFg, RoleTasksProgress,
+		RoleTasksMuted, RoleTasksDone, RoleTasksWip, RoleTasksTodo, RoleTasksCurrent,
+		RoleUpdateBg, RoleUpdateFg, RoleCursor, RolePaceOnTrack, RolePaceAhead, RolePaceOver,
+	}
+}
+
+// validateColors checks role names and color values of a color override map.
+//
+// Params:
+//   - field: config field name used in messages
+//   - colors: role to color value map
+//
+// Returns:
+//   - []error: one error per invalid entry, in role order
+func validateColors(field string, colors map[string]string) []error {
+	var errs []error
+	roles := make([]string, 0, len(colors))
+	// Collect roles for a stable error order
+	for role := range colors {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	// Check each override
+	for _, role := range roles {
+		// Reject unknown roles
+		if !slices.Contains(ThemeRoles(), role) {
+			errs = append(errs, fmt.Errorf("%s: unknown color role %q", field, role))
+			continue
+		}
+		// Reject unparseable values
+		if _, err := ParseColor(colors[role]); err != nil {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", field, role, err))
+		}
+	}
+	// Return collected errors
+	return errs
+}
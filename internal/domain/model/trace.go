@@ -0,0 +1,46 @@
+// Package model contains domain entities and value objects.
+package model
+
+// Trace step kinds.
+const (
+	// TraceCommand is an external command run by a provider.
+	TraceCommand string = "command"
+	// TraceFile is a file read by a provider.
+	TraceFile string = "file"
+	// TraceDir is a directory listed by a provider.
+	TraceDir string = "dir"
+	// TraceRequest is an HTTP request sent by a provider.
+	TraceRequest string = "request"
+	// TraceEnv is an environment variable read by a provider.
+	TraceEnv string = "env"
+)
+
+// TraceStep is one attempt made by a provider while collecting data.
+type TraceStep struct {
+	Kind   string
+	Target string
+	Err    error
+}
+
+// Trace records the files, commands and requests a provider tries.
+// Providers swallow errors so the status line always renders; a trace keeps
+// them for diagnostics. A nil *Trace records nothing, so providers can call
+// Record unconditionally.
+type Trace struct {
+	Steps []TraceStep
+}
+
+// Record appends an attempt to the trace.
+//
+// Params:
+//   - kind: TraceCommand, TraceFile, TraceDir, TraceRequest or TraceEnv
+//   - target: command line, path, URL or variable name
+//   - err: failure of the attempt, nil on success
+func (t *Trace) Record(kind, target string, err error) {
+	// Tracing is disabled
+	if t == nil {
+		// Nothing to record
+		return
+	}
+	t.Steps = append(t.Steps, TraceStep{Kind: kind, Target: target, Err: err})
+}
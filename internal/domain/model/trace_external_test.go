@@ -0,0 +1,35 @@
+package model_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestTrace_Record(t *testing.T) {
+	errMissing := errors.New("missing")
+	tests := []struct {
+		name      string
+		trace     *model.Trace
+		wantSteps int
+	}{
+		{name: "records steps", trace: &model.Trace{}, wantSteps: 2},
+		{name: "nil trace records nothing", trace: nil, wantSteps: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.trace.Record(model.TraceFile, "/etc/a.json", errMissing)
+			tt.trace.Record(model.TraceCommand, "git status", nil)
+			if tt.trace == nil {
+				return
+			}
+			if len(tt.trace.Steps) != tt.wantSteps {
+				t.Fatalf("Steps = %d, want %d", len(tt.trace.Steps), tt.wantSteps)
+			}
+			if got := tt.trace.Steps[0]; got.Kind != model.TraceFile || got.Target != "/etc/a.json" || !errors.Is(got.Err, errMissing) {
+				t.Errorf("Steps[0] = %+v, want file step with error", got)
+			}
+		})
+	}
+}
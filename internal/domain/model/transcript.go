@@ -0,0 +1,58 @@
+// Package model contains domain entities and value objects.
+package model
+
+import "time"
+
+// TokenUsage is the token breakdown the API reported for one request.
+// Input counts uncached prompt tokens; CacheRead and CacheCreation count the
+// prompt tokens served from and written to the prompt cache.
+type TokenUsage struct {
+	Input         int `json:"input"`
+	Output        int `json:"output"`
+	CacheRead     int `json:"cache_read"`
+	CacheCreation int `json:"cache_creation"`
+}
+
+// ContextTokens returns the tokens the conversation holds after the request:
+// the whole prompt, cached or not, plus the response.
+//
+// Returns:
+//   - int: tokens in the context window
+func (u TokenUsage) ContextTokens() int {
+	// Sum prompt and response tokens
+	return u.Input + u.CacheRead + u.CacheCreation + u.Output
+}
+
+// IsZero reports whether no usage was recorded.
+//
+// Returns:
+//   - bool: true when every count is zero
+func (u TokenUsage) IsZero() bool {
+	// Check every count
+	return u == TokenUsage{}
+}
+
+// TranscriptStats summarizes the main conversation of a session transcript.
+// Tokens comes from the last assistant message, so it reflects the context
+// after compactions rather than the cumulative totals. Sub-agent messages
+// are left out; Activity counts the sub-agents still running instead.
+type TranscriptStats struct {
+	Tokens            TokenUsage `json:"tokens"`
+	UserMessages      int        `json:"user_messages"`
+	AssistantMessages int        `json:"assistant_messages"`
+	Compactions       int        `json:"compactions"`
+	LastCompaction    time.Time  `json:"last_compaction,omitzero"`
+	Activity          Activity   `json:"activity,omitzero"`
+}
+
+// Progress returns the context window usage after the last response.
+//
+// Params:
+//   - contextSize: maximum context window size
+//
+// Returns:
+//   - Progress: context usage, capped at 100%
+func (s TranscriptStats) Progress(contextSize int) Progress {
+	// Return progress of the tokens in context
+	return NewProgress(s.Tokens.ContextTokens(), contextSize)
+}
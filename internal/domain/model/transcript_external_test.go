@@ -0,0 +1,51 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestTokenUsage_ContextTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		usage    model.TokenUsage
+		want     int
+		wantZero bool
+	}{
+		{name: "zero", usage: model.TokenUsage{}, want: 0, wantZero: true},
+		{name: "all counts", usage: model.TokenUsage{Input: 1, Output: 2, CacheRead: 30, CacheCreation: 400}, want: 433},
+		{name: "cache only", usage: model.TokenUsage{CacheRead: 1000}, want: 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.usage.ContextTokens(); got != tt.want {
+				t.Errorf("ContextTokens() = %d, want %d", got, tt.want)
+			}
+			if got := tt.usage.IsZero(); got != tt.wantZero {
+				t.Errorf("IsZero() = %v, want %v", got, tt.wantZero)
+			}
+		})
+	}
+}
+
+func TestTranscriptStats_Progress(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokens      model.TokenUsage
+		size        int
+		wantPercent int
+	}{
+		{name: "quarter", tokens: model.TokenUsage{Input: 10000, CacheRead: 40000}, size: 200000, wantPercent: 25},
+		{name: "capped", tokens: model.TokenUsage{Input: 300000}, size: 200000, wantPercent: 100},
+		{name: "no usage", size: 200000, wantPercent: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := model.TranscriptStats{Tokens: tt.tokens}.Progress(tt.size)
+			if p.Percent != tt.wantPercent {
+				t.Errorf("Progress() = %d%%, want %d%%", p.Percent, tt.wantPercent)
+			}
+		})
+	}
+}
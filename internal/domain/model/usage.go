@@ -1,7 +1,10 @@
 // Package model contains domain entities and value objects.
 package model
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Duration constants for burn rate calculation.
 const (
@@ -9,21 +12,166 @@ const (
 	sessionDuration time.Duration = 5 * time.Hour
 	// weekDuration is the duration of one week for burn rate calculation.
 	weekDuration time.Duration = 7 * 24 * time.Hour
+	// weeklyWindowPrefix starts the names of model-specific weekly windows.
+	weeklyWindowPrefix string = "seven_day_"
+	// paceMargin is how many points above the cursor usage is still ahead of
+	// pace rather than over it.
+	paceMargin int = 10
 )
 
+// Pace compares usage with the share of its window that has elapsed.
+type Pace string
+
+// Pace values, from sustainable to unsustainable.
+const (
+	// PaceOnTrack means usage is at or below the elapsed share of the window:
+	// the limit will not be reached before the reset at this rate.
+	PaceOnTrack Pace = "on_track"
+	// PaceAhead means usage is slightly above the elapsed share of the window.
+	PaceAhead Pace = "ahead"
+	// PaceOver means usage is well above the elapsed share of the window.
+	PaceOver Pace = "over"
+)
+
+// Forecast projects usage at its current rate to the end of its window.
+// LimitAt is when the limit will be reached, zero when it will not be
+// reached before ResetsAt.
+type Forecast struct {
+	Pace     Pace      `json:"pace"`
+	LimitAt  time.Time `json:"limit_at,omitzero"`
+	ResetsAt time.Time `json:"resets_at"`
+}
+
 // Usage represents API usage from Anthropic for a specific time window.
 // It contains utilization percentage, reset time, and window duration
 // for burn rate calculation. Used for both session (5h) and weekly (7d).
+// Stale is set when the value comes from a cache that could not be refreshed.
 type Usage struct {
-	Utilization    int
-	ResetsAt       time.Time
-	WindowDuration time.Duration
+	Utilization    int           `json:"utilization"`
+	ResetsAt       time.Time     `json:"resets_at"`
+	WindowDuration time.Duration `json:"window_duration"`
+	Stale          bool          `json:"stale,omitempty"`
+}
+
+// UsageStatus tells why usage data is missing or outdated.
+// The zero value means the last request succeeded or none was made.
+type UsageStatus string
+
+// Usage status values reported by the usage provider.
+const (
+	// UsageAuthExpired means the API rejected the OAuth token.
+	UsageAuthExpired UsageStatus = "auth_expired"
+	// UsageRateLimited means the API asked to slow down.
+	UsageRateLimited UsageStatus = "rate_limited"
+	// UsageUnavailable means the API could not be reached or failed.
+	UsageUnavailable UsageStatus = "unavailable"
+)
+
+// Label returns a short description for display.
+//
+// Returns:
+//   - string: description, empty for the zero value
+func (s UsageStatus) Label() string {
+	// Describe each status
+	switch s {
+	// Token rejected
+	case UsageAuthExpired:
+		// Return auth label
+		return "auth expired"
+	// Too many requests
+	case UsageRateLimited:
+		// Return rate limit label
+		return "rate limited"
+	// Request failed
+	case UsageUnavailable:
+		// Return unavailable label
+		return "usage unavailable"
+	// No problem
+	default:
+		// Return nothing
+		return ""
+	}
+}
+
+// UsageWindow is a named usage window reported besides the session and
+// weekly ones, such as a model-specific weekly limit ("seven_day_opus") or
+// extra usage ("extra_usage"). Windows without a reset time have a zero ResetsAt.
+type UsageWindow struct {
+	Name  string `json:"name"`
+	Usage Usage  `json:"usage"`
+}
+
+// UsageWindows is the open-ended list of named usage windows, sorted by name.
+type UsageWindows []UsageWindow
+
+// Find returns the window with the given name.
+//
+// Params:
+//   - name: window name as reported by the API
+//
+// Returns:
+//   - UsageWindow: matching window
+//   - bool: false when the API did not report it
+func (ws UsageWindows) Find(name string) (UsageWindow, bool) {
+	// Look for the name
+	for _, w := range ws {
+		// Check name
+		if w.Name == name {
+			// Return match
+			return w, true
+		}
+	}
+	// Return not found
+	return UsageWindow{}, false
+}
+
+// ForModel returns the weekly window limiting the given model, if any.
+// Model windows are named after the model family, such as "seven_day_opus"
+// for "claude-opus-4-5" or "Opus 4.5".
+//
+// Params:
+//   - m: current model
+//
+// Returns:
+//   - UsageWindow: model-specific weekly window
+//   - bool: false when the model has no window of its own
+func (ws UsageWindows) ForModel(m ModelInfo) (UsageWindow, bool) {
+	family := m.Family()
+	// Models without a name match nothing
+	if family == "" {
+		// Return not found
+		return UsageWindow{}, false
+	}
+	// Return the family's weekly window
+	return ws.Find(weeklyWindowPrefix + family)
 }
 
 // UsageData holds both session and weekly usage from the Anthropic API.
+// Windows lists the other windows the API reports. Status reports a failed
+// last request; the windows may then hold cached values. FetchedAt is when
+// the API returned the data, which cached data keeps.
 type UsageData struct {
-	Session Usage
-	Weekly  Usage
+	Session   Usage        `json:"session"`
+	Weekly    Usage        `json:"weekly"`
+	Windows   UsageWindows `json:"windows,omitempty"`
+	Status    UsageStatus  `json:"status,omitempty"`
+	FetchedAt time.Time    `json:"fetched_at,omitzero"`
+}
+
+// MarkStale returns a copy of the data with every window marked stale.
+//
+// Returns:
+//   - UsageData: data flagged as outdated
+func (d UsageData) MarkStale() UsageData {
+	d.Session.Stale = true
+	d.Weekly.Stale = true
+	d.Windows = slices.Clone(d.Windows)
+	// Flag named windows in the copy, leaving the original untouched
+	for i := range d.Windows {
+		d.Windows[i].Usage.Stale = true
+	}
+	// Return flagged copy
+	return d
 }
 
 // NewUsage creates a Usage with the given values and window duration.
@@ -67,12 +215,25 @@ func NewWeeklyUsage(utilization int, resetsAt time.Time) Usage {
 // Returns:
 //   - int: cursor position as percentage (0-100)
 func (u Usage) CursorPosition() int {
+	// Return cursor at the current time
+	return u.cursorAt(time.Now())
+}
+
+// cursorAt calculates the burn rate cursor at a given time: the share of
+// the window elapsed by then.
+//
+// Params:
+//   - now: reference time
+//
+// Returns:
+//   - int: cursor position as percentage (0-100)
+func (u Usage) cursorAt(now time.Time) int {
 	// Guard against missing window duration
 	if u.WindowDuration <= 0 {
 		return 0
 	}
 	// Calculate time remaining until reset
-	remaining := time.Until(u.ResetsAt)
+	remaining := u.ResetsAt.Sub(now)
 	// Check if reset already passed
 	if remaining <= 0 {
 		return maxPercent
@@ -84,17 +245,49 @@ func (u Usage) CursorPosition() int {
 	// Calculate elapsed time in current window
 	elapsed := u.WindowDuration - remaining
 	// Calculate cursor position as percentage
-	position := int(elapsed * time.Duration(maxPercent) / u.WindowDuration)
-	return position
+	return int(elapsed * time.Duration(maxPercent) / u.WindowDuration)
 }
 
 // IsOnTrack returns true if utilization is at or below expected usage.
 //
+// Params:
+//   - now: reference time
+//
 // Returns:
 //   - bool: true if consumption is sustainable
-func (u Usage) IsOnTrack() bool {
+func (u Usage) IsOnTrack(now time.Time) bool {
 	// Compare utilization to cursor position
-	return u.Utilization <= u.CursorPosition()
+	return u.Utilization <= u.cursorAt(now)
+}
+
+// Forecast projects usage to the end of the window, assuming it keeps
+// growing at its average rate since the window started.
+//
+// Params:
+//   - now: current time
+//
+// Returns:
+//   - Forecast: pace and projected limit time
+func (u Usage) Forecast(now time.Time) Forecast {
+	forecast := Forecast{Pace: PaceOnTrack, ResetsAt: u.ResetsAt}
+	remaining := u.ResetsAt.Sub(now)
+	// Nothing to project without a window in progress, nor for sustainable usage
+	if u.WindowDuration <= 0 || remaining <= 0 || remaining >= u.WindowDuration || u.Utilization <= 0 || u.IsOnTrack(now) {
+		// Return on track
+		return forecast
+	}
+	// Usage above the cursor is much too fast
+	forecast.Pace = PaceOver
+	// Slightly fast usage is only ahead of the cursor
+	if u.Utilization <= u.cursorAt(now)+paceMargin {
+		forecast.Pace = PaceAhead
+	}
+	// Time left at the average rate, utilization points per elapsed time
+	elapsed := u.WindowDuration - remaining
+	left := elapsed * time.Duration(maxPercent-u.Utilization) / time.Duration(u.Utilization)
+	forecast.LimitAt = now.Add(left)
+	// Return forecast
+	return forecast
 }
 
 // Progress returns a Progress value from the utilization.
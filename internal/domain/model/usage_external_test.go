@@ -114,17 +114,56 @@ func TestUsage_IsOnTrack(t *testing.T) {
 	}{
 		{name: "on track (usage below cursor)", utilization: 20, resetsAt: now.Add(84 * time.Hour), want: true},
 		{name: "not on track (usage above cursor)", utilization: 80, resetsAt: now.Add(84 * time.Hour), want: false},
+		{name: "on track after the reset", utilization: 80, resetsAt: now.Add(-time.Minute), want: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			u := model.NewWeeklyUsage(tt.utilization, tt.resetsAt)
-			if got := u.IsOnTrack(); got != tt.want {
+			if got := u.IsOnTrack(now); got != tt.want {
 				t.Errorf("IsOnTrack() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestUsage_Forecast(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		utilization int
+		resetsAt    time.Time
+		wantPace    model.Pace
+		wantLimitIn time.Duration
+	}{
+		{name: "below cursor", utilization: 30, resetsAt: now.Add(3 * time.Hour), wantPace: model.PaceOnTrack},
+		{name: "slightly above cursor", utilization: 45, resetsAt: now.Add(3 * time.Hour), wantPace: model.PaceAhead, wantLimitIn: 2*time.Hour + 26*time.Minute + 40*time.Second},
+		{name: "well above cursor", utilization: 80, resetsAt: now.Add(3 * time.Hour), wantPace: model.PaceOver, wantLimitIn: 30 * time.Minute},
+		{name: "limit reached", utilization: 100, resetsAt: now.Add(3 * time.Hour), wantPace: model.PaceOver, wantLimitIn: 0},
+		{name: "unused", utilization: 0, resetsAt: now.Add(3 * time.Hour), wantPace: model.PaceOnTrack},
+		{name: "reset passed", utilization: 80, resetsAt: now.Add(-time.Minute), wantPace: model.PaceOnTrack},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := model.NewSessionUsage(tt.utilization, tt.resetsAt).Forecast(now)
+			if got.Pace != tt.wantPace {
+				t.Errorf("Forecast().Pace = %q, want %q", got.Pace, tt.wantPace)
+			}
+			if !got.ResetsAt.Equal(tt.resetsAt) {
+				t.Errorf("Forecast().ResetsAt = %v, want %v", got.ResetsAt, tt.resetsAt)
+			}
+			if tt.wantPace == model.PaceOnTrack {
+				if !got.LimitAt.IsZero() {
+					t.Errorf("Forecast().LimitAt = %v, want zero", got.LimitAt)
+				}
+				return
+			}
+			if limitIn := got.LimitAt.Sub(now); limitIn != tt.wantLimitIn {
+				t.Errorf("Forecast() limit in %v, want %v", limitIn, tt.wantLimitIn)
+			}
+		})
+	}
+}
+
 func TestUsage_Progress(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -163,3 +202,83 @@ func TestUsage_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestUsageData_MarkStale(t *testing.T) {
+	tests := []struct {
+		name string
+		data model.UsageData
+	}{
+		{name: "fresh data", data: model.UsageData{Session: model.NewSessionUsage(10, time.Now()), Weekly: model.NewWeeklyUsage(20, time.Now())}},
+		{name: "named windows", data: model.UsageData{Windows: model.UsageWindows{{Name: "seven_day_opus", Usage: model.NewWeeklyUsage(5, time.Now())}}}},
+		{name: "empty data", data: model.UsageData{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.data.MarkStale()
+			if !got.Session.Stale || !got.Weekly.Stale {
+				t.Errorf("MarkStale() = %+v, want both windows stale", got)
+			}
+			for _, w := range got.Windows {
+				if !w.Usage.Stale {
+					t.Errorf("MarkStale() window %q not stale", w.Name)
+				}
+			}
+			if tt.data.Session.Stale || got.Weekly.Utilization != tt.data.Weekly.Utilization {
+				t.Errorf("MarkStale() changed the original or its values")
+			}
+			for _, w := range tt.data.Windows {
+				if w.Usage.Stale {
+					t.Errorf("MarkStale() changed the original window %q", w.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestUsageWindows_ForModel(t *testing.T) {
+	windows := model.UsageWindows{
+		{Name: "seven_day_opus", Usage: model.NewWeeklyUsage(40, time.Now())},
+		{Name: "seven_day_sonnet", Usage: model.NewWeeklyUsage(10, time.Now())},
+		{Name: "extra_usage", Usage: model.Usage{Utilization: 3}},
+	}
+	tests := []struct {
+		name     string
+		model    model.ModelInfo
+		wantName string
+		wantOK   bool
+	}{
+		{name: "opus", model: model.ModelInfo{Name: "Opus", Version: "4.5"}, wantName: "seven_day_opus", wantOK: true},
+		{name: "sonnet", model: model.ModelInfo{Name: "Sonnet"}, wantName: "seven_day_sonnet", wantOK: true},
+		{name: "id wins over name", model: model.ModelInfo{ID: "claude-sonnet-4-5-20250929", Name: "Claude"}, wantName: "seven_day_sonnet", wantOK: true},
+		{name: "no bucket", model: model.ModelInfo{Name: "Haiku"}, wantOK: false},
+		{name: "no name", model: model.ModelInfo{}, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := windows.ForModel(tt.model)
+			if ok != tt.wantOK || got.Name != tt.wantName {
+				t.Errorf("ForModel() = %q, %v, want %q, %v", got.Name, ok, tt.wantName, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestUsageStatus_Label(t *testing.T) {
+	tests := []struct {
+		name   string
+		status model.UsageStatus
+		want   string
+	}{
+		{name: "ok", status: "", want: ""},
+		{name: "auth expired", status: model.UsageAuthExpired, want: "auth expired"},
+		{name: "rate limited", status: model.UsageRateLimited, want: "rate limited"},
+		{name: "unavailable", status: model.UsageUnavailable, want: "usage unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.Label(); got != tt.want {
+				t.Errorf("Label() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
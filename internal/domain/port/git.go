@@ -1,20 +1,41 @@
 // Package port defines domain interfaces (contracts).
 package port
 
-import "github.com/florent/status-line/internal/domain/model"
+import (
+	"context"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
 
 // GitRepository defines the interface for git operations.
 // Implementations should retrieve git status information.
 type GitRepository interface {
 	// Status retrieves the current git repository status.
 	//
+	// Params:
+	//   - ctx: cancels the git commands when the deadline passes
+	//
 	// Returns:
 	//   - model.GitStatus: branch and change information
-	Status() model.GitStatus
+	Status(ctx context.Context) model.GitStatus
+
+	// DiffStats returns lines added and removed in the working tree since HEAD.
+	//
+	// Params:
+	//   - ctx: cancels the git command when the deadline passes
+	//
+	// Returns:
+	//   - model.CodeChanges: lines added and removed
+	DiffStats(ctx context.Context) model.CodeChanges
 
-	// DiffStats returns lines added and removed from git diff.
+	// BranchDiffStats returns lines added and removed since the merge-base
+	// of HEAD and the base branch, uncommitted changes included.
+	//
+	// Params:
+	//   - ctx: cancels the git commands when the deadline passes
+	//   - base: base branch, empty to use the default branch
 	//
 	// Returns:
 	//   - model.CodeChanges: lines added and removed
-	DiffStats() model.CodeChanges
+	BranchDiffStats(ctx context.Context, base string) model.CodeChanges
 }
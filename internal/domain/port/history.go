@@ -0,0 +1,33 @@
+// Package port defines domain interfaces (contracts).
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// UsageHistory defines the interface for the usage history ledger.
+// Implementations should append samples to local storage and compact it.
+type UsageHistory interface {
+	// Record appends a usage sample, skipping it when the last one is too recent.
+	//
+	// Params:
+	//   - sample: usage reading to keep
+	//
+	// Returns:
+	//   - error: storage error if any
+	Record(sample model.UsageSample) error
+
+	// History returns the samples recorded since a time.
+	//
+	// Params:
+	//   - ctx: cancels reading when the deadline passes
+	//   - since: earliest sample time
+	//
+	// Returns:
+	//   - model.UsageHistory: samples in time order
+	//   - error: storage error if any, fs.ErrNotExist before the first sample
+	History(ctx context.Context, since time.Time) (model.UsageHistory, error)
+}
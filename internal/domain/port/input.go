@@ -1,7 +1,11 @@
 // Package port defines domain interfaces.
 package port
 
-import "github.com/florent/status-line/internal/domain/model"
+import (
+	"encoding/json"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
 
 // InputProvider provides input data for status line generation.
 // It abstracts the source of input data.
@@ -12,4 +16,14 @@ type InputProvider interface {
 	WorkingDir() string
 	// Progress returns context usage progress (fallback when API unavailable).
 	Progress() model.Progress
+	// ContextProgress returns context usage progress, refined by the transcript.
+	ContextProgress(stats model.TranscriptStats) model.Progress
+	// CodeChanges returns the lines added and removed reported for the session.
+	CodeChanges() model.CodeChanges
+	// SessionCost returns the session cost and durations.
+	SessionCost() model.SessionCost
+	// Session returns the session identifiers, project root and client details.
+	Session() model.SessionInfo
+	// Field returns the raw value of an input key not decoded into a field.
+	Field(name string) (json.RawMessage, bool)
 }
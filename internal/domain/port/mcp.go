@@ -1,14 +1,21 @@
 // Package port defines domain interfaces (contracts).
 package port
 
-import "github.com/florent/status-line/internal/domain/model"
+import (
+	"context"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
 
 // MCPProvider defines the interface for reading MCP server configurations.
 // Implementations should read from Claude settings files.
 type MCPProvider interface {
 	// Servers returns the list of configured MCP servers.
 	//
+	// Params:
+	//   - ctx: stops reading config files when the deadline passes
+	//
 	// Returns:
 	//   - model.MCPServers: list of MCP server configurations
-	Servers() model.MCPServers
+	Servers(ctx context.Context) model.MCPServers
 }
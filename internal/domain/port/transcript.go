@@ -0,0 +1,23 @@
+// Package port defines domain interfaces (contracts).
+package port
+
+import (
+	"context"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// TranscriptReader defines the interface for session transcript access.
+// Implementations should read only what was appended since the last call.
+type TranscriptReader interface {
+	// Stats returns the token usage, message counts and compactions of a session.
+	//
+	// Params:
+	//   - ctx: stops reading when the deadline passes
+	//   - session: session ID and transcript path
+	//
+	// Returns:
+	//   - model.TranscriptStats: summary of the transcript read so far
+	//   - error: read error if any
+	Stats(ctx context.Context, session model.SessionInfo) (model.TranscriptStats, error)
+}
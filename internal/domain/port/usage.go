@@ -1,15 +1,22 @@
 // Package port defines domain interfaces (contracts).
 package port
 
-import "github.com/florent/status-line/internal/domain/model"
+import (
+	"context"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
 
 // UsageProvider defines the interface for API usage information.
 // Implementations should fetch usage data from Anthropic API.
 type UsageProvider interface {
 	// Usage returns session (5h) and weekly (7d) API usage.
 	//
+	// Params:
+	//   - ctx: cancels the request when the deadline passes
+	//
 	// Returns:
-	//   - model.UsageData: session and weekly utilization and reset times
+	//   - model.UsageData: session and weekly utilization and reset times, dated by their fetch
 	//   - error: any error during fetch
-	Usage() (model.UsageData, error)
+	Usage(ctx context.Context) (model.UsageData, error)
 }
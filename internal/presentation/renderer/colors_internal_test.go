@@ -0,0 +1,37 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestColorCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		color  model.Color
+		depth  model.ColorDepth
+		wantFg string
+		wantBg string
+	}{
+		{name: "palette index", color: model.IndexedColor(111), wantFg: "\033[38;5;111m", wantBg: "\033[48;5;111m"},
+		{name: "true color", color: model.HexColor(0x88c0d0), wantFg: "\033[38;2;136;192;208m", wantBg: "\033[48;2;136;192;208m"},
+		{name: "true color at truecolor depth", color: model.HexColor(0x88c0d0), depth: model.ColorDepthTrueColor, wantFg: "\033[38;2;136;192;208m", wantBg: "\033[48;2;136;192;208m"},
+		{name: "true color at 256 depth", color: model.HexColor(0x88c0d0), depth: model.ColorDepth256, wantFg: "\033[38;5;110m", wantBg: "\033[48;5;110m"},
+		{name: "palette index at 256 depth", color: model.IndexedColor(111), depth: model.ColorDepth256, wantFg: "\033[38;5;111m", wantBg: "\033[48;5;111m"},
+		{name: "basic color at 16 depth", color: model.IndexedColor(1), depth: model.ColorDepth16, wantFg: "\033[31m", wantBg: "\033[41m"},
+		{name: "bright color at 16 depth", color: model.IndexedColor(231), depth: model.ColorDepth16, wantFg: "\033[97m", wantBg: "\033[107m"},
+		{name: "true color at 16 depth", color: model.HexColor(0xdc322f), depth: model.ColorDepth16, wantFg: "\033[31m", wantBg: "\033[41m"},
+		{name: "no colors", color: model.IndexedColor(111), depth: model.ColorDepthNone, wantFg: "", wantBg: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fgCode(tt.color, tt.depth); got != tt.wantFg {
+				t.Errorf("fgCode() = %q, want %q", got, tt.wantFg)
+			}
+			if got := bgCode(tt.color, tt.depth); got != tt.wantBg {
+				t.Errorf("bgCode() = %q, want %q", got, tt.wantBg)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Package renderer provides status line rendering.
+package renderer
+
+import (
+	"strings"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Palette geometry of the xterm 256-color table.
+const (
+	// cubeStart is the first index of the 6x6x6 color cube.
+	cubeStart int = 16
+	// cubeSize is the number of levels per cube channel.
+	cubeSize int = 6
+	// grayStart is the first index of the grayscale ramp.
+	grayStart int = 232
+	// grayLevels is the number of grayscale ramp entries.
+	grayLevels int = 24
+	// grayBase is the value of the darkest ramp entry.
+	grayBase int = 8
+	// grayStep is the value step between ramp entries.
+	grayStep int = 10
+	// paletteColors is the number of basic colors.
+	paletteColors int = 16
+)
+
+// SGR stripping constants.
+const (
+	// escByte starts an escape sequence.
+	escByte byte = 0x1b
+	// csiByte follows escByte in a control sequence.
+	csiByte byte = '['
+	// sgrFinal ends a Select Graphic Rendition sequence.
+	sgrFinal byte = 'm'
+)
+
+// cubeLevels are the channel values of the 6x6x6 color cube.
+var cubeLevels [6]int = [6]int{0, 95, 135, 175, 215, 255}
+
+// basicPalette holds the xterm default values of the 16 basic colors.
+var basicPalette [16][3]int = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// rgbOf returns the channels of a color, resolving palette indexes.
+//
+// Params:
+//   - c: color to resolve
+//
+// Returns:
+//   - [3]int: red, green and blue channels
+func rgbOf(c model.Color) [3]int {
+	// Use channels of RGB colors directly
+	if c.IsRGB() {
+		r, g, b := c.RGB()
+		// Return channels
+		return [3]int{int(r), int(g), int(b)}
+	}
+	idx := int(c.Index())
+	// Check basic colors
+	if idx < paletteColors {
+		// Return basic color
+		return basicPalette[idx]
+	}
+	// Check grayscale ramp
+	if idx >= grayStart {
+		v := grayBase + (idx-grayStart)*grayStep
+		// Return gray
+		return [3]int{v, v, v}
+	}
+	idx -= cubeStart
+	// Return cube color
+	return [3]int{cubeLevels[idx/(cubeSize*cubeSize)], cubeLevels[idx/cubeSize%cubeSize], cubeLevels[idx%cubeSize]}
+}
+
+// to256 returns the nearest 256-color palette index.
+// Palette colors are returned unchanged.
+//
+// Params:
+//   - c: color to convert
+//
+// Returns:
+//   - uint8: palette index
+func to256(c model.Color) uint8 {
+	// Keep palette colors
+	if !c.IsRGB() {
+		// Return index
+		return c.Index()
+	}
+	rgb := rgbOf(c)
+	best, bestDist := 0, -1
+	// Search the color cube and the grayscale ramp (basic colors vary by terminal)
+	for idx := cubeStart; idx < grayStart+grayLevels; idx++ {
+		// Keep the closest candidate
+		if dist := distance(rgb, rgbOf(model.IndexedColor(uint8(idx)))); bestDist < 0 || dist < bestDist {
+			best, bestDist = idx, dist
+		}
+	}
+	// Return closest index
+	return uint8(best)
+}
+
+// to16 returns the nearest basic color index.
+//
+// Params:
+//   - c: color to convert
+//
+// Returns:
+//   - uint8: basic color index (0-15)
+func to16(c model.Color) uint8 {
+	// Keep basic palette colors
+	if !c.IsRGB() && int(c.Index()) < paletteColors {
+		// Return index
+		return c.Index()
+	}
+	rgb := rgbOf(c)
+	best, bestDist := 0, -1
+	// Search the basic palette
+	for idx, candidate := range basicPalette {
+		// Keep the closest candidate
+		if dist := distance(rgb, candidate); bestDist < 0 || dist < bestDist {
+			best, bestDist = idx, dist
+		}
+	}
+	// Return closest index
+	return uint8(best)
+}
+
+// distance returns the squared RGB distance between two colors.
+//
+// Params:
+//   - a: first color channels
+//   - b: second color channels
+//
+// Returns:
+//   - int: squared Euclidean distance
+func distance(a, b [3]int) int {
+	dr, dg, db := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	// Return squared distance
+	return dr*dr + dg*dg + db*db
+}
+
+// stripSGR removes every Select Graphic Rendition sequence from s.
+// Glyphs and spacing are kept so the segment structure stays readable.
+//
+// Params:
+//   - s: text with escape sequences
+//
+// Returns:
+//   - string: plain text
+func stripSGR(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	// Copy everything outside SGR sequences
+	for i := 0; i < len(s); i++ {
+		// Check for the start of a control sequence
+		if s[i] == escByte && i+1 < len(s) && s[i+1] == csiByte {
+			end := strings.IndexByte(s[i:], sgrFinal)
+			// Skip the sequence when it is terminated
+			if end >= 0 {
+				i += end
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	// Return plain text
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestTo256(t *testing.T) {
+	tests := []struct {
+		name  string
+		color model.Color
+		want  uint8
+	}{
+		{name: "palette index kept", color: model.IndexedColor(42), want: 42},
+		{name: "black", color: model.HexColor(0x000000), want: 16},
+		{name: "white", color: model.HexColor(0xffffff), want: 231},
+		{name: "cube color", color: model.HexColor(0xff8700), want: 208},
+		{name: "gray ramp", color: model.HexColor(0x808080), want: 244},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := to256(tt.color); got != tt.want {
+				t.Errorf("to256() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTo16(t *testing.T) {
+	tests := []struct {
+		name  string
+		color model.Color
+		want  uint8
+	}{
+		{name: "basic index kept", color: model.IndexedColor(12), want: 12},
+		{name: "cube red", color: model.IndexedColor(196), want: 9},
+		{name: "near black", color: model.IndexedColor(234), want: 0},
+		{name: "mid gray", color: model.IndexedColor(240), want: 8},
+		{name: "light gray", color: model.IndexedColor(252), want: 7},
+		{name: "rgb green", color: model.HexColor(0x00d000), want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := to16(tt.color); got != tt.want {
+				t.Errorf("to16() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripSGR(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain text", input: "main", want: "main"},
+		{name: "colors and reset", input: "\033[48;5;111m\033[38;5;25m path \033[0m", want: " path "},
+		{name: "keeps glyphs", input: "\033[1m\uE0B0\033[0m x", want: "\uE0B0 x"},
+		{name: "unterminated sequence", input: "a\033[31", want: "a\033[31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripSGR(tt.input); got != tt.want {
+				t.Errorf("stripSGR() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
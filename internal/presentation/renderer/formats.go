@@ -0,0 +1,426 @@
+// Package renderer provides status line rendering.
+package renderer
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/florent/status-line/internal/domain/model"
+	"github.com/florent/status-line/internal/domain/port"
+)
+
+// Output format names accepted by --format.
+const (
+	// FormatANSI renders powerline segments with raw ANSI escapes.
+	FormatANSI string = "ansi"
+	// FormatPlain renders the same layout without escape sequences.
+	FormatPlain string = "plain"
+	// FormatJSON renders the collected status line data as JSON.
+	FormatJSON string = "json"
+	// FormatTmux renders tmux #[...] style markup.
+	FormatTmux string = "tmux"
+	// FormatZsh renders escapes wrapped in %{...%} for zsh prompts.
+	FormatZsh string = "zsh"
+	// FormatBash renders escapes wrapped in \[...\] for bash prompts.
+	FormatBash string = "bash"
+)
+
+// Prompt wrapping constants.
+const (
+	// zshOpen starts a zero-width zsh prompt sequence.
+	zshOpen string = "%{"
+	// zshClose ends a zero-width zsh prompt sequence.
+	zshClose string = "%}"
+	// bashOpen starts a zero-width bash prompt sequence.
+	bashOpen string = `\[`
+	// bashClose ends a zero-width bash prompt sequence.
+	bashClose string = `\]`
+)
+
+// tmux style constants.
+const (
+	// tmuxOpen starts a tmux style directive.
+	tmuxOpen string = "#["
+	// tmuxClose ends a tmux style directive.
+	tmuxClose string = "]"
+	// tmuxDefault resets every tmux style attribute.
+	tmuxDefault string = "default"
+	// tmuxBold enables bold text.
+	tmuxBold string = "bold"
+	// tmuxColour prefixes a tmux palette color.
+	tmuxColour string = "colour"
+	// tmuxFg prefixes a tmux foreground color.
+	tmuxFg string = "fg="
+	// tmuxBg prefixes a tmux background color.
+	tmuxBg string = "bg="
+	// tmuxLineSeparator joins layout lines, since status-right holds a single line.
+	tmuxLineSeparator string = " "
+)
+
+// SGR parameters understood by the tmux conversion.
+const (
+	// sgrParamReset resets every attribute.
+	sgrParamReset int = 0
+	// sgrParamBold enables bold text.
+	sgrParamBold int = 1
+	// sgrParamFgDefault restores the default foreground.
+	sgrParamFgDefault int = 39
+	// sgrParamBgDefault restores the default background.
+	sgrParamBgDefault int = 49
+	// sgrParamFg selects an extended foreground color.
+	sgrParamFg int = 38
+	// sgrParamBg selects an extended background color.
+	sgrParamBg int = 48
+	// sgrModeIndexed selects a palette index after 38 or 48.
+	sgrModeIndexed int = 5
+	// sgrModeRGB selects a 24-bit color after 38 or 48.
+	sgrModeRGB int = 2
+	// rgbParams is the number of parameters of a 24-bit color.
+	rgbParams int = 3
+	// hexDigits is the number of hex digits per color channel.
+	hexDigits int = 2
+	// base16 is the hexadecimal base.
+	base16 int = 16
+)
+
+// Compile-time interface implementation checks.
+var (
+	_ port.Renderer = (*JSON)(nil)
+	_ port.Renderer = (*Markup)(nil)
+)
+
+// Formats returns every output format name.
+//
+// Returns:
+//   - []string: format names, default first
+func Formats() []string {
+	// Return every format
+	return []string{FormatANSI, FormatPlain, FormatJSON, FormatTmux, FormatZsh, FormatBash}
+}
+
+// NewFormatRenderer creates the renderer for an output format.
+//
+// Params:
+//   - cfg: user configuration
+//   - format: output format name
+//
+// Returns:
+//   - port.Renderer: renderer for the format
+//   - bool: false when the format is unknown
+func NewFormatRenderer(cfg model.Config, format string) (port.Renderer, bool) {
+	// Match by name
+	switch format {
+	// Terminal escapes
+	case FormatANSI:
+		// Return powerline renderer
+		return NewPowerline(cfg), true
+	// No escapes
+	case FormatPlain:
+		// Return plain renderer
+		return NewPlain(cfg), true
+	// Raw data
+	case FormatJSON:
+		// Return JSON renderer
+		return NewJSON(), true
+	// tmux markup
+	case FormatTmux:
+		// Return tmux renderer
+		return NewTmux(cfg), true
+	// zsh prompt
+	case FormatZsh:
+		// Return zsh renderer
+		return NewZsh(cfg), true
+	// bash prompt
+	case FormatBash:
+		// Return bash renderer
+		return NewBash(cfg), true
+	}
+	// Unknown format
+	return nil, false
+}
+
+// JSON implements port.Renderer by encoding the collected data.
+// Editor plugins draw their own status line from it.
+type JSON struct{}
+
+// NewJSON creates a JSON renderer.
+//
+// Returns:
+//   - *JSON: new renderer instance
+func NewJSON() *JSON {
+	// Return stateless renderer
+	return &JSON{}
+}
+
+// Render encodes data as a single JSON object followed by a newline.
+//
+// Params:
+//   - data: all information collected for rendering
+//
+// Returns:
+//   - string: JSON document, empty if encoding fails
+func (r *JSON) Render(data model.StatusLineData) string {
+	out, err := json.Marshal(data)
+	// Report nothing rather than a partial document
+	if err != nil {
+		// Return empty output
+		return ""
+	}
+	// Return document
+	return string(out) + "\n"
+}
+
+// Markup implements port.Renderer by rewriting powerline output.
+// Every SGR sequence is replaced by the target markup and the remaining
+// text is escaped for it, so all formats share the same layout and fitting.
+// A non-empty join puts every layout line on one output line.
+type Markup struct {
+	powerline *Powerline
+	sequence  func(params string) string
+	escape    *strings.Replacer
+	join      string
+}
+
+// NewPlain creates a renderer without escape sequences, for logs.
+//
+// Params:
+//   - cfg: user configuration
+//
+// Returns:
+//   - *Markup: new renderer instance
+func NewPlain(cfg model.Config) *Markup {
+	// Return renderer dropping every sequence
+	return &Markup{
+		powerline: NewPowerline(cfg),
+		sequence:  func(string) string { return "" },
+		escape:    strings.NewReplacer(),
+	}
+}
+
+// NewTmux creates a renderer emitting tmux #[fg=...,bg=...] markup, for status-right.
+// Layout lines are joined on one line, as tmux shows only the first one.
+//
+// Params:
+//   - cfg: user configuration
+//
+// Returns:
+//   - *Markup: new renderer instance
+func NewTmux(cfg model.Config) *Markup {
+	// Return renderer converting sequences to tmux styles
+	return &Markup{
+		powerline: NewPowerline(cfg),
+		sequence:  tmuxStyle,
+		escape:    strings.NewReplacer("#", "##"),
+		join:      tmuxLineSeparator,
+	}
+}
+
+// NewZsh creates a renderer wrapping escapes in %{...%}, for zsh prompts.
+//
+// Params:
+//   - cfg: user configuration
+//
+// Returns:
+//   - *Markup: new renderer instance
+func NewZsh(cfg model.Config) *Markup {
+	// Return renderer wrapping sequences for zsh
+	return &Markup{
+		powerline: NewPowerline(cfg),
+		sequence:  func(params string) string { return zshOpen + sgrStart + params + sgrEnd + zshClose },
+		escape:    strings.NewReplacer("%", "%%"),
+	}
+}
+
+// NewBash creates a renderer wrapping escapes in \[...\], for bash prompts.
+// Bash decodes prompt escapes then expands $ and ` in PS1 when promptvars is
+// on, so text is escaped twice: once for the decoding, once for the
+// expansion. Branch names and paths can then never run commands.
+//
+// Params:
+//   - cfg: user configuration
+//
+// Returns:
+//   - *Markup: new renderer instance
+func NewBash(cfg model.Config) *Markup {
+	// Return renderer wrapping sequences for bash
+	return &Markup{
+		powerline: NewPowerline(cfg),
+		sequence:  func(params string) string { return bashOpen + sgrStart + params + sgrEnd + bashClose },
+		escape:    strings.NewReplacer(`\`, `\\\\`, "$", `\\$`, "`", "\\\\`"),
+	}
+}
+
+// Render generates the powerline status line and rewrites it for the target.
+//
+// Params:
+//   - data: all information needed for rendering
+//
+// Returns:
+//   - string: status line in the target markup
+func (r *Markup) Render(data model.StatusLineData) string {
+	out := rewriteSGR(r.powerline.Render(data), r.sequence, r.escape)
+	// Keep one output line per layout line
+	if r.join == "" {
+		// Return rewritten status line
+		return out
+	}
+	// Skip lines left empty by the layout
+	lines := slices.DeleteFunc(strings.Split(out, "\n"), func(line string) bool { return line == "" })
+	// Return layout lines on a single line
+	return strings.Join(lines, r.join) + "\n"
+}
+
+// rewriteSGR replaces every SGR sequence of s and escapes the text between them.
+//
+// Params:
+//   - s: text with escape sequences
+//   - sequence: converts SGR parameters to the target markup
+//   - escape: escapes plain text for the target
+//
+// Returns:
+//   - string: rewritten text
+func rewriteSGR(s string, sequence func(params string) string, escape *strings.Replacer) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	start := 0
+	// Copy text runs and convert sequences between them
+	for i := 0; i < len(s); i++ {
+		// Check for the start of a control sequence
+		if s[i] != escByte || i+1 >= len(s) || s[i+1] != csiByte {
+			continue
+		}
+		end := strings.IndexByte(s[i:], sgrFinal)
+		// Leave unterminated sequences as text
+		if end < 0 {
+			break
+		}
+		sb.WriteString(escape.Replace(s[start:i]))
+		sb.WriteString(sequence(s[i+len(sgrStart) : i+end]))
+		i += end
+		start = i + 1
+	}
+	sb.WriteString(escape.Replace(s[start:]))
+	// Return rewritten text
+	return sb.String()
+}
+
+// tmuxStyle converts SGR parameters to a tmux style directive.
+// Unsupported parameters are ignored.
+//
+// Params:
+//   - params: semicolon separated SGR parameters
+//
+// Returns:
+//   - string: tmux #[...] directive, empty when nothing applies
+func tmuxStyle(params string) string {
+	var attrs []string
+	codes := sgrParams(params)
+	// Translate each parameter, consuming extended color arguments
+	for i := 0; i < len(codes); i++ {
+		code := codes[i]
+		// Select translation by parameter
+		switch {
+		// Full reset
+		case code == sgrParamReset:
+			attrs = append(attrs, tmuxDefault)
+		// Bold text
+		case code == sgrParamBold:
+			attrs = append(attrs, tmuxBold)
+		// Default foreground
+		case code == sgrParamFgDefault:
+			attrs = append(attrs, tmuxFg+tmuxDefault)
+		// Default background
+		case code == sgrParamBgDefault:
+			attrs = append(attrs, tmuxBg+tmuxDefault)
+		// Extended colors
+		case code == sgrParamFg || code == sgrParamBg:
+			prefix := tmuxFg
+			// Pick target
+			if code == sgrParamBg {
+				prefix = tmuxBg
+			}
+			color, used := tmuxColor(codes[i+1:])
+			// Keep only well-formed colors
+			if color != "" {
+				attrs = append(attrs, prefix+color)
+			}
+			i += used
+		// Basic foreground colors
+		case code >= ansiFgBase && code < ansiFgBase+basicColors:
+			attrs = append(attrs, tmuxFg+tmuxColour+strconv.Itoa(code-ansiFgBase))
+		// Bright foreground colors
+		case code >= ansiFgBase+brightOffset && code < ansiFgBase+brightOffset+basicColors:
+			attrs = append(attrs, tmuxFg+tmuxColour+strconv.Itoa(code-ansiFgBase-brightOffset+basicColors))
+		// Basic background colors
+		case code >= ansiBgBase && code < ansiBgBase+basicColors:
+			attrs = append(attrs, tmuxBg+tmuxColour+strconv.Itoa(code-ansiBgBase))
+		// Bright background colors
+		case code >= ansiBgBase+brightOffset && code < ansiBgBase+brightOffset+basicColors:
+			attrs = append(attrs, tmuxBg+tmuxColour+strconv.Itoa(code-ansiBgBase-brightOffset+basicColors))
+		}
+	}
+	// Emit nothing when no attribute applies
+	if len(attrs) == 0 {
+		// Return empty directive
+		return ""
+	}
+	// Return directive
+	return tmuxOpen + strings.Join(attrs, ",") + tmuxClose
+}
+
+// tmuxColor converts the arguments of an extended SGR color.
+//
+// Params:
+//   - args: parameters following 38 or 48
+//
+// Returns:
+//   - string: tmux color, empty when malformed
+//   - int: number of parameters consumed
+func tmuxColor(args []int) (string, int) {
+	// Require a mode
+	if len(args) == 0 {
+		// Return nothing consumed
+		return "", 0
+	}
+	// Palette color
+	if args[0] == sgrModeIndexed && len(args) > 1 {
+		// Return palette color
+		return tmuxColour + strconv.Itoa(args[1]), 2
+	}
+	// 24-bit color
+	if args[0] == sgrModeRGB && len(args) > rgbParams {
+		var sb strings.Builder
+		sb.WriteByte('#')
+		// Write each channel as two hex digits
+		for _, v := range args[1 : rgbParams+1] {
+			hex := strconv.FormatInt(int64(v), base16)
+			sb.WriteString(strings.Repeat("0", max(hexDigits-len(hex), 0)) + hex)
+		}
+		// Return hex color
+		return sb.String(), rgbParams + 1
+	}
+	// Return mode consumed only
+	return "", 1
+}
+
+// sgrParams parses semicolon separated SGR parameters.
+// An empty list is a reset, and empty or invalid entries count as 0.
+//
+// Params:
+//   - params: parameter string between ESC[ and m
+//
+// Returns:
+//   - []int: parsed parameters
+func sgrParams(params string) []int {
+	fields := strings.Split(params, ";")
+	codes := make([]int, 0, len(fields))
+	// Parse each field
+	for _, f := range fields {
+		n, _ := strconv.Atoi(f)
+		codes = append(codes, n)
+	}
+	// Return parsed parameters
+	return codes
+}
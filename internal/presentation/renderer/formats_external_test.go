@@ -0,0 +1,135 @@
+package renderer_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+	"github.com/florent/status-line/internal/presentation/renderer"
+)
+
+func TestNewFormatRenderer(t *testing.T) {
+	data := model.StatusLineData{
+		Model:    model.ModelInfo{Name: "Opus", Version: "4.5"},
+		Progress: model.Progress{Percent: 50},
+		System:   model.SystemInfo{OS: model.OSLinux},
+		Git:      model.GitStatus{Branch: "main"},
+		Dir:      "/workspace/#1",
+	}
+	tests := []struct {
+		name     string
+		format   string
+		wantOK   bool
+		want     []string
+		wantNone []string
+	}{
+		{name: "ansi", format: renderer.FormatANSI, wantOK: true, want: []string{"\033[", "Opus"}},
+		{name: "plain", format: renderer.FormatPlain, wantOK: true, want: []string{"Opus", "/workspace/#1"}, wantNone: []string{"\033"}},
+		{name: "tmux", format: renderer.FormatTmux, wantOK: true, want: []string{"#[fg=colour", "#[default]", "/workspace/##1"}, wantNone: []string{"\033"}},
+		{name: "zsh", format: renderer.FormatZsh, wantOK: true, want: []string{"%{\033[", "m%}", "50%%"}},
+		{name: "bash", format: renderer.FormatBash, wantOK: true, want: []string{`\[` + "\033[", `m\]`}},
+		{name: "json", format: renderer.FormatJSON, wantOK: true, want: []string{`"branch":"main"`, `"os":"linux"`}},
+		{name: "unknown", format: "html", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := renderer.NewFormatRenderer(model.DefaultConfig(), tt.format)
+			if ok != tt.wantOK {
+				t.Fatalf("NewFormatRenderer(%q) ok = %v, want %v", tt.format, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			got := r.Render(data)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() = %q, want to contain %q", got, want)
+				}
+			}
+			for _, notWant := range tt.wantNone {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Render() = %q, want no %q", got, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestBash_EscapesExpansions(t *testing.T) {
+	tests := []struct {
+		name   string
+		branch string
+		want   string
+	}{
+		{name: "command substitution", branch: "$(rm -rf ~)", want: `\\$(rm -rf ~)`},
+		{name: "backticks", branch: "`id`", want: "\\\\`id\\\\`"},
+		{name: "escaped dollar", branch: `\$HOME`, want: `\\\\\\$HOME`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Layout = model.Layout{{model.SegmentGit}}
+			got := renderer.NewBash(cfg).Render(model.StatusLineData{Git: model.GitStatus{Branch: tt.branch}})
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Render() = %q, want to contain %q", got, tt.want)
+			}
+			// Every $ and ` must follow the escaped backslash bash turns back into a quote
+			rest := strings.ReplaceAll(strings.ReplaceAll(got, `\\$`, ""), "\\\\`", "")
+			if strings.ContainsAny(rest, "$`") {
+				t.Errorf("Render() = %q, want no unescaped $ or `", got)
+			}
+		})
+	}
+}
+
+func TestTmux_SingleLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout model.Layout
+		want   []string
+	}{
+		{name: "one line", layout: model.Layout{{model.SegmentGit}}, want: []string{"main"}},
+		{name: "lines joined", layout: model.Layout{{model.SegmentGit}, {model.SegmentPath}}, want: []string{"main", "/workspace"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Layout = tt.layout
+			got := renderer.NewTmux(cfg).Render(model.StatusLineData{Git: model.GitStatus{Branch: "main"}, Dir: "/workspace"})
+			if n := strings.Count(got, "\n"); n != 1 || !strings.HasSuffix(got, "\n") {
+				t.Errorf("Render() = %q, want a single line", got)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() = %q, want to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestJSON_Render(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      model.StatusLineData
+		wantModel string
+		wantAdded float64
+	}{
+		{name: "encodes model and changes", data: model.StatusLineData{Model: model.ModelInfo{Name: "Opus"}, Changes: model.CodeChanges{Added: 3, Removed: 1}}, wantModel: "Opus", wantAdded: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				Model   map[string]any `json:"model"`
+				Changes map[string]any `json:"changes"`
+			}
+			if err := json.Unmarshal([]byte(renderer.NewJSON().Render(tt.data)), &got); err != nil {
+				t.Fatalf("Render() produced invalid JSON: %v", err)
+			}
+			if got.Model["name"] != tt.wantModel || got.Changes["added"] != tt.wantAdded {
+				t.Errorf("Render() decoded = %+v, want model %q and %v added", got, tt.wantModel, tt.wantAdded)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTmuxStyle(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+		want   string
+	}{
+		{name: "reset", params: "0", want: "#[default]"},
+		{name: "bold", params: "1", want: "#[bold]"},
+		{name: "palette foreground", params: "38;5;208", want: "#[fg=colour208]"},
+		{name: "true color background", params: "48;2;255;8;0", want: "#[bg=#ff0800]"},
+		{name: "basic and bright", params: "31;102", want: "#[fg=colour1,bg=colour10]"},
+		{name: "default colors", params: "39;49", want: "#[fg=default,bg=default]"},
+		{name: "unsupported", params: "4", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tmuxStyle(tt.params); got != tt.want {
+				t.Errorf("tmuxStyle(%q) = %q, want %q", tt.params, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewriteSGR(t *testing.T) {
+	wrap := func(params string) string { return "<" + params + ">" }
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain text", in: "a%b", want: "a%%b"},
+		{name: "sequences", in: "\033[1mx%\033[0m", want: "<1>x%%<0>"},
+		{name: "unterminated sequence", in: "x\033[1", want: "x\033[1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteSGR(tt.in, wrap, strings.NewReplacer("%", "%%")); got != tt.want {
+				t.Errorf("rewriteSGR(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,71 +3,237 @@ package renderer
 
 import "github.com/florent/status-line/internal/domain/model"
 
-// Icon and separator constants for powerline rendering.
-const (
-	// SepRight is the right-pointing arrow separator.
-	SepRight string = "\uE0B0"
-	// SepLeft is the left-pointing arrow separator.
-	SepLeft string = "\uE0B2"
-	// LeftRound is the left rounded cap.
-	LeftRound string = "\uE0B6"
-	// RightRound is the right rounded cap.
-	RightRound string = "\uE0B4"
-	// IconLinux is the Linux penguin icon.
-	IconLinux string = "\uf17c"
-	// IconDocker is the Docker whale icon.
-	IconDocker string = "\uf308"
-	// IconApple is the Apple logo icon.
-	IconApple string = "\uf179"
-	// IconWindows is the Windows logo icon.
-	IconWindows string = "\uf17a"
-	// IconDesktop is the generic desktop icon.
-	IconDesktop string = "\uf108"
-	// IconFolder is the folder icon.
-	IconFolder string = "\uf07b"
-	// IconGitBranch is the git branch icon.
-	IconGitBranch string = "\ue0a0"
-	// IconModel is the microchip icon for AI models.
-	IconModel string = "\uf2db"
-	// IconTaskwarrior is the tasks list icon for Taskwarrior.
-	IconTaskwarrior string = "\uf0ae"
-	// IconUpdate is the download/update icon.
-	IconUpdate string = "\uf019"
-	// IconWeekly is the calendar/clock icon for weekly usage.
-	IconWeekly string = "\uf073"
-)
+// IconSet holds the glyphs segments put in front of their text.
+// Segments ask the active set instead of using fixed characters.
+type IconSet struct {
+	// Linux is the Linux OS icon.
+	Linux string
+	// Docker is the icon for Linux containers.
+	Docker string
+	// Apple is the macOS icon.
+	Apple string
+	// Windows is the Windows OS icon.
+	Windows string
+	// Desktop is the icon for unknown operating systems.
+	Desktop string
+	// Folder is the working directory icon.
+	Folder string
+	// GitBranch is the git branch icon.
+	GitBranch string
+	// Model is the AI model icon.
+	Model string
+	// Tasks is the Taskwarrior icon.
+	Tasks string
+	// Update is the download/update icon.
+	Update string
+	// Weekly is the weekly usage icon.
+	Weekly string
+	// Stale marks usage data served from an outdated cache.
+	Stale string
+	// Warning marks usage data the API failed to refresh.
+	Warning string
+	// Agents counts the running sub-agents.
+	Agents string
+	// Spark lists the sparkline levels, lowest first.
+	Spark string
+	// Plan labels a Taskwarrior project in plan mode.
+	Plan string
+	// Current marks the Taskwarrior task in progress.
+	Current string
+	// EpicLeft opens the Taskwarrior epic bar.
+	EpicLeft string
+	// EpicRight closes the Taskwarrior epic bar.
+	EpicRight string
+	// EpicSeparator divides epics, and the task indicator from the percentage.
+	EpicSeparator string
+	// EpicDone fills the epic bar for each done task.
+	EpicDone string
+	// EpicWip marks the task in progress in the epic bar.
+	EpicWip string
+	// EpicTodo fills the epic bar for each remaining task.
+	EpicTodo string
+}
+
+// LookupIconSet returns a built-in icon set by name.
+//
+// Params:
+//   - name: icon set name
+//
+// Returns:
+//   - *IconSet: the icon set, nil if unknown
+//   - bool: true if the set exists
+func LookupIconSet(name string) (*IconSet, bool) {
+	// Match by name
+	switch name {
+	// Nerd Font glyphs
+	case model.IconSetNerd:
+		// Return Nerd Font set
+		return NerdIcons(), true
+	// Unicode symbols
+	case model.IconSetUnicode:
+		// Return Unicode set
+		return UnicodeIcons(), true
+	// ASCII labels
+	case model.IconSetASCII:
+		// Return ASCII set
+		return ASCIIIcons(), true
+	// Unknown name
+	default:
+		// Return not found
+		return nil, false
+	}
+}
+
+// NewIconSet returns the icon set selected by the configuration.
+// Unknown names fall back to Nerd Font glyphs, since the configuration has
+// been validated beforehand.
+//
+// Params:
+//   - cfg: user configuration
+//
+// Returns:
+//   - *IconSet: resolved icon set
+func NewIconSet(cfg model.Config) *IconSet {
+	set, ok := LookupIconSet(cfg.IconSet)
+	// Fall back to Nerd Font glyphs
+	if !ok {
+		// Return default set
+		return NerdIcons()
+	}
+	// Return configured set
+	return set
+}
+
+// NerdIcons returns the Nerd Font icon set.
+//
+// Returns:
+//   - *IconSet: icons requiring a patched font
+func NerdIcons() *IconSet {
+	// Return Nerd Font glyphs
+	return &IconSet{
+		Linux:         "\uf17c",
+		Docker:        "\uf308",
+		Apple:         "\uf179",
+		Windows:       "\uf17a",
+		Desktop:       "\uf108",
+		Folder:        "\uf07b",
+		GitBranch:     "\ue0a0",
+		Model:         "\uf2db",
+		Tasks:         "\uf0ae",
+		Update:        "\uf019",
+		Weekly:        "\uf073",
+		Stale:         "\uf017",
+		Warning:       "\uf071",
+		Agents:        "\uf0c0",
+		Spark:         "▁▂▃▄▅▆▇█",
+		Plan:          "\uf002 PLAN",
+		Current:       "▶",
+		EpicLeft:      "▐",
+		EpicRight:     "▌",
+		EpicSeparator: "│",
+		EpicDone:      "━",
+		EpicWip:       "●",
+		EpicTodo:      "─",
+	}
+}
+
+// UnicodeIcons returns the icon set built on standard Unicode symbols.
+//
+// Returns:
+//   - *IconSet: icons available in common fonts
+func UnicodeIcons() *IconSet {
+	// Return Unicode symbols
+	return &IconSet{
+		Linux:         "🐧",
+		Docker:        "🐳",
+		Apple:         "⌘",
+		Windows:       "⊞",
+		Desktop:       "💻",
+		Folder:        "📁",
+		GitBranch:     "⎇",
+		Model:         "◆",
+		Tasks:         "☑",
+		Update:        "⬇",
+		Weekly:        "📅",
+		Stale:         "◷",
+		Warning:       "⚠",
+		Agents:        "⧉",
+		Spark:         "▁▂▃▄▅▆▇█",
+		Plan:          "🔍 PLAN",
+		Current:       "▶",
+		EpicLeft:      "▐",
+		EpicRight:     "▌",
+		EpicSeparator: "│",
+		EpicDone:      "━",
+		EpicWip:       "●",
+		EpicTodo:      "─",
+	}
+}
+
+// ASCIIIcons returns the icon set built on plain ASCII labels.
+//
+// Returns:
+//   - *IconSet: icons rendering in any font
+func ASCIIIcons() *IconSet {
+	// Return ASCII labels
+	return &IconSet{
+		Linux:         "[linux]",
+		Docker:        "[docker]",
+		Apple:         "[mac]",
+		Windows:       "[win]",
+		Desktop:       "[pc]",
+		Folder:        "[dir]",
+		GitBranch:     "[git]",
+		Model:         "[ai]",
+		Tasks:         "[tasks]",
+		Update:        "[update]",
+		Weekly:        "[week]",
+		Stale:         "(old)",
+		Warning:       "(!)",
+		Agents:        "[agents]",
+		Spark:         "_.,-=+*#",
+		Plan:          "PLAN",
+		Current:       ">",
+		EpicLeft:      "[",
+		EpicRight:     "]",
+		EpicSeparator: "|",
+		EpicDone:      "=",
+		EpicWip:       "*",
+		EpicTodo:      "-",
+	}
+}
 
-// GetOSIcon returns the appropriate icon for the OS type.
+// OSIcon returns the appropriate icon for the OS type.
 //
 // Params:
 //   - osType: the operating system type
 //   - isDocker: whether running in Docker
 //
 // Returns:
-//   - string: Nerd Font icon character
-func GetOSIcon(osType model.OSType, isDocker bool) string {
+//   - string: icon of the set
+func (s *IconSet) OSIcon(osType model.OSType, isDocker bool) string {
 	// Check for Docker on Linux first
 	if osType == model.OSLinux && isDocker {
 		// Return Docker icon for containers
-		return IconDocker
+		return s.Docker
 	}
 	// Select icon based on OS type
 	switch osType {
 	// Linux penguin icon
 	case model.OSLinux:
 		// Return Linux icon
-		return IconLinux
+		return s.Linux
 	// Apple logo icon
 	case model.OSDarwin:
 		// Return Apple icon
-		return IconApple
+		return s.Apple
 	// Windows logo icon
 	case model.OSWindows:
 		// Return Windows icon
-		return IconWindows
+		return s.Windows
 	// Generic desktop icon for unknown OS
 	default:
 		// Return desktop icon
-		return IconDesktop
+		return s.Desktop
 	}
 }
@@ -7,24 +7,79 @@ import (
 	"github.com/florent/status-line/internal/presentation/renderer"
 )
 
-func TestGetOSIcon(t *testing.T) {
+func TestIconSet_OSIcon(t *testing.T) {
+	nerd := renderer.NerdIcons()
 	tests := []struct {
 		name     string
+		set      *renderer.IconSet
 		osType   model.OSType
 		isDocker bool
 		want     string
 	}{
-		{name: "linux", osType: model.OSLinux, isDocker: false, want: renderer.IconLinux},
-		{name: "linux in docker", osType: model.OSLinux, isDocker: true, want: renderer.IconDocker},
-		{name: "darwin", osType: model.OSDarwin, isDocker: false, want: renderer.IconApple},
-		{name: "windows", osType: model.OSWindows, isDocker: false, want: renderer.IconWindows},
-		{name: "unknown", osType: model.OSUnknown, isDocker: false, want: renderer.IconDesktop},
-		{name: "darwin with docker flag", osType: model.OSDarwin, isDocker: true, want: renderer.IconApple},
+		{name: "linux", set: nerd, osType: model.OSLinux, isDocker: false, want: nerd.Linux},
+		{name: "linux in docker", set: nerd, osType: model.OSLinux, isDocker: true, want: nerd.Docker},
+		{name: "darwin", set: nerd, osType: model.OSDarwin, isDocker: false, want: nerd.Apple},
+		{name: "windows", set: nerd, osType: model.OSWindows, isDocker: false, want: nerd.Windows},
+		{name: "unknown", set: nerd, osType: model.OSUnknown, isDocker: false, want: nerd.Desktop},
+		{name: "darwin with docker flag", set: nerd, osType: model.OSDarwin, isDocker: true, want: nerd.Apple},
+		{name: "ascii linux", set: renderer.ASCIIIcons(), osType: model.OSLinux, isDocker: false, want: "[linux]"},
+		{name: "unicode docker", set: renderer.UnicodeIcons(), osType: model.OSLinux, isDocker: true, want: "🐳"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := renderer.GetOSIcon(tt.osType, tt.isDocker); got != tt.want {
-				t.Errorf("GetOSIcon(%v, %v) = %q, want %q", tt.osType, tt.isDocker, got, tt.want)
+			if got := tt.set.OSIcon(tt.osType, tt.isDocker); got != tt.want {
+				t.Errorf("OSIcon(%v, %v) = %q, want %q", tt.osType, tt.isDocker, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupIconSet(t *testing.T) {
+	tests := []struct {
+		name       string
+		set        string
+		wantFolder string
+		wantOK     bool
+	}{
+		{name: "nerd", set: model.IconSetNerd, wantFolder: "\uf07b", wantOK: true},
+		{name: "unicode", set: model.IconSetUnicode, wantFolder: "📁", wantOK: true},
+		{name: "ascii", set: model.IconSetASCII, wantFolder: "[dir]", wantOK: true},
+		{name: "unknown", set: "emoji", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, ok := renderer.LookupIconSet(tt.set)
+			if ok != tt.wantOK {
+				t.Fatalf("LookupIconSet(%q) ok = %v, want %v", tt.set, ok, tt.wantOK)
+			}
+			if ok && set.Folder != tt.wantFolder {
+				t.Errorf("LookupIconSet(%q).Folder = %q, want %q", tt.set, set.Folder, tt.wantFolder)
+			}
+		})
+	}
+}
+
+func TestNewSeparatorSet(t *testing.T) {
+	tests := []struct {
+		name       string
+		iconSet    string
+		separators string
+		wantRight  string
+		wantCap    string
+	}{
+		{name: "auto with nerd icons", iconSet: model.IconSetNerd, separators: model.SeparatorsAuto, wantRight: "\uE0B0", wantCap: "\uE0B6"},
+		{name: "auto with ascii icons", iconSet: model.IconSetASCII, separators: model.SeparatorsAuto, wantRight: "|", wantCap: ""},
+		{name: "rounded", iconSet: model.IconSetNerd, separators: model.SeparatorsRounded, wantRight: "\uE0B4", wantCap: "\uE0B6"},
+		{name: "slanted", iconSet: model.IconSetUnicode, separators: model.SeparatorsSlanted, wantRight: "\uE0BC", wantCap: "\uE0BA"},
+		{name: "flat", iconSet: model.IconSetNerd, separators: model.SeparatorsFlat, wantRight: "|", wantCap: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.IconSet, cfg.Separators = tt.iconSet, tt.separators
+			seps := renderer.NewSeparatorSet(cfg)
+			if seps.Right != tt.wantRight || seps.CapLeft != tt.wantCap {
+				t.Errorf("NewSeparatorSet() = %+v, want right %q and cap %q", seps, tt.wantRight, tt.wantCap)
 			}
 		})
 	}
@@ -0,0 +1,290 @@
+// Package renderer provides status line rendering.
+package renderer
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// minGroupGap is the smallest number of spaces between the left and right groups.
+const minGroupGap int = 1
+
+// lineSegment is a visible segment of a line being fitted.
+type lineSegment struct {
+	seg      Segment
+	out      SegmentOutput
+	position int
+}
+
+// renderLine renders one layout line.
+// Each identifier is resolved in the registry; unknown, disabled and empty
+// segments are skipped so their neighbors join directly. Consecutive
+// powerline segments are chained with arrow separators colored from their
+// neighbors; pills are separated by spaces. Segments after the AlignRight
+// entry are padded against the right edge with left-facing separators.
+// When the line is too wide the right group shrinks first, then the left one.
+//
+// Params:
+//   - sb: string builder to write to
+//   - ids: segment identifiers in display order
+//   - data: status line data
+func (r *Powerline) renderLine(sb *strings.Builder, ids []string, data model.StatusLineData) {
+	ctx := &RenderContext{
+		Data:       data,
+		Config:     r.config,
+		Theme:      r.theme.WithDepth(r.colorDepth(data)),
+		Icons:      r.icons,
+		Separators: r.separators,
+	}
+	leftIDs, rightIDs := model.SplitLine(ids)
+	left := r.renderSegments(leftIDs, ctx)
+	right := r.renderSegments(rightIDs, ctx)
+	width := data.Terminal.Width
+
+	// Fit the right group in the room left by the full left group
+	if width > 0 {
+		leftWidth := r.lineWidth(left)
+		right = r.shrink(right, ctx, func(parts []lineSegment) bool {
+			// Require a gap between the groups
+			return len(parts) == 0 || leftWidth+minGroupGap+r.groupWidth(parts) <= width
+		}, false)
+		left = r.shrink(left, ctx, func(parts []lineSegment) bool {
+			// Check the left group alone once the right group is gone
+			return r.lineWidth(parts) <= width
+		}, true)
+	}
+
+	r.writeLine(sb, left)
+	// Write the right group flush with the terminal edge
+	if len(right) > 0 {
+		gap := minGroupGap
+		// Pad when the width is known
+		if width > 0 {
+			gap = max(width-r.lineWidth(left)-r.groupWidth(right), minGroupGap)
+		}
+		sb.WriteString(strings.Repeat(" ", gap))
+		r.writeGroup(sb, right)
+	}
+}
+
+// renderSegments renders the visible segments of a group.
+//
+// Params:
+//   - ids: segment identifiers in display order
+//   - ctx: render context
+//
+// Returns:
+//   - []lineSegment: segments with content, in display order
+func (r *Powerline) renderSegments(ids []string, ctx *RenderContext) []lineSegment {
+	var parts []lineSegment
+	// Render each visible segment
+	for _, id := range ids {
+		seg, ok := r.registry.Lookup(id)
+		// Skip unknown and hidden segments
+		if !ok || !seg.Enabled(ctx.Data) {
+			continue
+		}
+		out := seg.Render(ctx)
+		// Skip segments with nothing to show
+		if out.Text == "" {
+			continue
+		}
+		parts = append(parts, lineSegment{seg: seg, out: out, position: len(parts)})
+	}
+	// Return visible segments
+	return parts
+}
+
+// shrink reduces a group until fits reports true.
+// Segments switch to their compact form from the lowest priority up, then
+// are dropped in the same order. Ties go to the rightmost segment first.
+//
+// Params:
+//   - parts: visible segments in display order
+//   - ctx: render context used for compact rendering
+//   - fits: reports whether a candidate group fits
+//   - keepLast: true to always keep the last remaining segment
+//
+// Returns:
+//   - []lineSegment: segments to write, in display order
+func (r *Powerline) shrink(parts []lineSegment, ctx *RenderContext, fits func([]lineSegment) bool, keepLast bool) []lineSegment {
+	// Keep groups that already fit
+	if fits(parts) {
+		// Return group unchanged
+		return parts
+	}
+	order := slices.Clone(parts)
+	slices.SortStableFunc(order, func(a, b lineSegment) int {
+		// Lowest priority first, rightmost first among equals
+		if c := cmp.Compare(a.seg.Priority(), b.seg.Priority()); c != 0 {
+			// Return priority order
+			return c
+		}
+		// Return reverse position order
+		return cmp.Compare(b.position, a.position)
+	})
+
+	compactCtx := *ctx
+	compactCtx.Compact = true
+	// Switch segments to their compact form
+	for _, victim := range order {
+		// Keep the full form when the compact one is empty
+		if out := victim.seg.Render(&compactCtx); out.Text != "" {
+			parts[victim.position].out = out
+		}
+		// Stop as soon as the group fits
+		if fits(parts) {
+			// Return compacted group
+			return parts
+		}
+	}
+
+	// Never drop the last segment when it must be kept
+	if keepLast {
+		order = order[:len(order)-1]
+	}
+	kept := parts
+	// Drop segments until the group fits
+	for _, victim := range order {
+		kept = slices.DeleteFunc(kept, func(p lineSegment) bool { return p.position == victim.position })
+		// Stop as soon as the group fits
+		if fits(kept) {
+			break
+		}
+	}
+	// Return remaining segments
+	return kept
+}
+
+// lineWidth returns the visible width of a left group.
+//
+// Params:
+//   - parts: segments in display order
+//
+// Returns:
+//   - int: width in columns
+func (r *Powerline) lineWidth(parts []lineSegment) int {
+	var sb strings.Builder
+	r.writeLine(&sb, parts)
+	// Return visible width
+	return visibleWidth(sb.String())
+}
+
+// groupWidth returns the visible width of a right group.
+//
+// Params:
+//   - parts: segments in display order
+//
+// Returns:
+//   - int: width in columns
+func (r *Powerline) groupWidth(parts []lineSegment) int {
+	var sb strings.Builder
+	r.writeGroup(&sb, parts)
+	// Return visible width
+	return visibleWidth(sb.String())
+}
+
+// writeLine writes a left group with separators, caps and spacing between segments.
+//
+// Params:
+//   - sb: string builder to write to
+//   - parts: segments in display order
+func (r *Powerline) writeLine(sb *strings.Builder, parts []lineSegment) {
+	var prev *SegmentOutput
+
+	// Write each segment after its join
+	for idx := range parts {
+		cur := &parts[idx].out
+		writeJoin(sb, r.separators, prev, cur)
+		sb.WriteString(cur.Text)
+		prev = cur
+	}
+
+	// Close a trailing powerline run against the terminal background
+	if prev != nil && !prev.Pill {
+		sb.WriteString(prev.endFg() + r.separators.Right + Reset)
+	}
+}
+
+// writeGroup writes a right group, mirroring writeLine with left-facing separators.
+//
+// Params:
+//   - sb: string builder to write to
+//   - parts: segments in display order
+func (r *Powerline) writeGroup(sb *strings.Builder, parts []lineSegment) {
+	var prev *SegmentOutput
+
+	// Write each segment after its join
+	for idx := range parts {
+		cur := &parts[idx].out
+		writeJoinLeft(sb, r.separators, prev, cur)
+		sb.WriteString(cur.Text)
+		prev = cur
+	}
+
+	// Close a trailing powerline run at the terminal edge with a cap
+	if prev != nil && !prev.Pill {
+		sb.WriteString(prev.endFg() + r.separators.CapRight + Reset)
+	}
+}
+
+// writeJoin writes what goes between two neighboring segments.
+//
+// Params:
+//   - sb: string builder to write to
+//   - seps: active separator set
+//   - prev: previous visible segment, nil at line start
+//   - cur: segment about to be written
+func writeJoin(sb *strings.Builder, seps *SeparatorSet, prev, cur *SegmentOutput) {
+	// Two chained powerline segments share an arrow separator
+	if prev != nil && !prev.Pill && !cur.Pill {
+		sb.WriteString(cur.Bg + prev.endFg() + seps.Right + Reset)
+		// Return after separator
+		return
+	}
+	// Close a powerline run before a pill
+	if prev != nil && !prev.Pill {
+		sb.WriteString(prev.endFg() + seps.Right + Reset)
+	}
+	// Separate from previous content with a space
+	if prev != nil {
+		sb.WriteString(" ")
+	}
+	// Open a new powerline run with a cap
+	if !cur.Pill {
+		sb.WriteString(cur.Fg + seps.CapLeft + Reset)
+	}
+}
+
+// writeJoinLeft writes what goes between two neighboring right-group segments.
+// Left-facing separators point at the previous segment, so they take the
+// color of the segment after them.
+//
+// Params:
+//   - sb: string builder to write to
+//   - seps: active separator set
+//   - prev: previous visible segment, nil at group start
+//   - cur: segment about to be written
+func writeJoinLeft(sb *strings.Builder, seps *SeparatorSet, prev, cur *SegmentOutput) {
+	// Two chained powerline segments share an arrow separator
+	if prev != nil && !prev.Pill && !cur.Pill {
+		sb.WriteString(prev.endBg() + cur.Fg + seps.Left + Reset)
+		// Return after separator
+		return
+	}
+	// Close a powerline run before a pill
+	if prev != nil && !prev.Pill {
+		sb.WriteString(prev.endFg() + seps.CapRight + Reset)
+	}
+	// Separate from previous content with a space
+	if prev != nil {
+		sb.WriteString(" ")
+	}
+	// Open a new powerline run with an arrow from the terminal background
+	if !cur.Pill {
+		sb.WriteString(cur.Fg + seps.Left + Reset)
+	}
+}
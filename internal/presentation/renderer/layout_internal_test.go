@@ -0,0 +1,85 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestPowerline_renderLine_Separators(t *testing.T) {
+	th, seps := DefaultTheme(), PowerlineSeparators()
+	tests := []struct {
+		name    string
+		ids     []string
+		data    model.StatusLineData
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "hidden git joins path to changes",
+			ids:  []string{"path", "git", "changes"},
+			data: model.StatusLineData{Dir: "/w", Changes: model.CodeChanges{Removed: 2}},
+			want: []string{th.Bg(model.RoleChangesRemovedBg) + th.Fg(model.RolePathBg) + seps.Right, th.Fg(model.RoleChangesRemovedBg) + seps.Right + Reset},
+		},
+		{
+			name: "custom order uses neighbor colors",
+			ids:  []string{"git", "os"},
+			data: model.StatusLineData{Git: model.GitStatus{Branch: "main"}},
+			want: []string{th.Fg(model.RoleGitBg) + seps.CapLeft, th.Bg(model.RoleOSBg) + th.Fg(model.RoleGitBg) + seps.Right, th.Fg(model.RoleOSBg) + seps.Right + Reset},
+		},
+		{
+			name:    "hidden weekly is skipped",
+			ids:     []string{"weekly", "path"},
+			data:    model.StatusLineData{Dir: "/w"},
+			want:    []string{th.Fg(model.RolePathBg) + seps.CapLeft},
+			notWant: []string{th.Bg(model.RoleWeeklyBg)},
+		},
+		{
+			name: "pill after powerline closes the run",
+			ids:  []string{"path", "update"},
+			data: model.StatusLineData{Dir: "/w", Update: model.UpdateInfo{Available: true, Version: "v2"}},
+			want: []string{th.Fg(model.RolePathBg) + seps.Right + Reset + " " + th.Fg(model.RoleUpdateBg) + seps.CapLeft},
+		},
+		{
+			name: "right group uses left-facing separators",
+			ids:  []string{"os", model.AlignRight, "path", "git"},
+			data: model.StatusLineData{Dir: "/w", Git: model.GitStatus{Branch: "main"}},
+			want: []string{
+				th.Fg(model.RoleOSBg) + seps.Right + Reset + " " + th.Fg(model.RolePathBg) + seps.Left,
+				th.Bg(model.RolePathBg) + th.Fg(model.RoleGitBg) + seps.Left,
+				th.Fg(model.RoleGitBg) + seps.CapRight + Reset,
+			},
+		},
+		{
+			name: "right group joins two-tone segment on its end color",
+			ids:  []string{model.AlignRight, "changes", "path"},
+			data: model.StatusLineData{Dir: "/w", Changes: model.CodeChanges{Added: 1, Removed: 2}},
+			want: []string{th.Bg(model.RoleChangesRemovedBg) + th.Fg(model.RolePathBg) + seps.Left},
+		},
+		{
+			name:    "nothing visible renders nothing",
+			ids:     []string{"git", "mcp"},
+			data:    model.StatusLineData{},
+			notWant: []string{seps.Right, seps.CapLeft},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewPowerline(model.DefaultConfig())
+			var sb strings.Builder
+			r.renderLine(&sb, tt.ids, tt.data)
+			got := sb.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("renderLine() = %q, want to contain %q", got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("renderLine() = %q, want not to contain %q", got, notWant)
+				}
+			}
+		})
+	}
+}
@@ -14,6 +14,8 @@ const (
 	pathSeparator string = "/"
 	// defaultMaxPath is the default maximum path length.
 	defaultMaxPath int = 30
+	// textEllipsis marks text shortened at its end.
+	textEllipsis string = "…"
 )
 
 // TruncatePath shortens a path by removing leading segments.
@@ -68,3 +70,22 @@ func TruncatePath(path string, maxLen int) string {
 	// Fallback: return original path
 	return path
 }
+
+// truncateText shortens text to maxLen characters, ending with an ellipsis.
+//
+// Params:
+//   - text: text to shorten
+//   - maxLen: maximum length in characters, ellipsis included
+//
+// Returns:
+//   - string: text unchanged if short enough, otherwise shortened
+func truncateText(text string, maxLen int) string {
+	runes := []rune(text)
+	// Return as-is if already short enough
+	if len(runes) <= maxLen {
+		// Return original text
+		return text
+	}
+	// Return prefix with ellipsis
+	return string(runes[:maxLen-1]) + textEllipsis
+}
@@ -0,0 +1,444 @@
+// Package renderer provides status line rendering.
+package renderer
+
+import (
+	"strings"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Pill rendering constants.
+const (
+	// epicBarMinWidth is the minimum width for an epic bar segment.
+	epicBarMinWidth int = 2
+	// epicBarMaxWidth is the maximum width for an epic bar segment.
+	epicBarMaxWidth int = 8
+	// mcpSummaryLabel prefixes the server count of the compact MCP pill.
+	mcpSummaryLabel string = "mcp"
+)
+
+// Compile-time interface implementation checks.
+var (
+	_ Segment = tasksSegment{}
+	_ Segment = mcpSegment{}
+	_ Segment = updateSegment{}
+)
+
+// tasksSegment shows Taskwarrior projects as pills.
+type tasksSegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (tasksSegment) ID() string {
+	// Return identifier
+	return model.SegmentTasks
+}
+
+// Enabled returns true when Taskwarrior is installed and has projects.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: true if there are projects to show
+func (tasksSegment) Enabled(data model.StatusLineData) bool {
+	// Check installation and projects
+	return data.Taskwarrior.Installed && data.Taskwarrior.HasProjects()
+}
+
+// Priority returns PriorityNormal: tasks use the default priority.
+//
+// Returns:
+//   - int: segment priority
+func (tasksSegment) Priority() int {
+	// Return priority
+	return PriorityNormal
+}
+
+// Render renders Taskwarrior project pills.
+// An active project with session data is shown alone with a segmented bar.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: pill content
+func (s tasksSegment) Render(ctx *RenderContext) SegmentOutput {
+	tw := ctx.Data.Taskwarrior
+	var sb strings.Builder
+
+	// Render active project with session (segmented bar)
+	if tw.ActiveProject != nil && tw.ActiveProject.HasSession() {
+		s.renderSessionPill(&sb, ctx, tw.ActiveProject)
+		// Return session pill
+		return SegmentOutput{Text: sb.String(), Pill: true}
+	}
+
+	// Render each legacy project as a pill
+	for idx, project := range tw.Projects {
+		// Add space between pills
+		if idx > 0 {
+			sb.WriteString(" ")
+		}
+		s.renderProjectPill(&sb, ctx, project, ctx.Config.Progress)
+	}
+	// Return project pills
+	return SegmentOutput{Text: sb.String(), Pill: true}
+}
+
+// renderProjectPill renders a single project pill with progress.
+//
+// Params:
+//   - sb: string builder to write to
+//   - ctx: render context (theme, icon and separator sets)
+//   - project: project information
+//   - barCfg: progress bar style and width
+func (tasksSegment) renderProjectPill(sb *strings.Builder, ctx *RenderContext, project model.TaskwarriorProject, barCfg model.ProgressConfig) {
+	t, seps := ctx.Theme, ctx.Separators
+	// Create progress for the project
+	progress := model.NewProgress(project.Completed, project.Total())
+	// Use the progress color for incomplete, the text color for 100%
+	progressColor := t.Fg(model.RoleTasksProgress)
+	// Check if project is complete
+	if progress.Percent == percentComplete {
+		// Use themed color for completed projects
+		progressColor = t.Fg(model.RoleTasksFg)
+	}
+
+	// Write left cap
+	sb.WriteString(t.Fg(model.RoleTasksBg) + seps.CapLeft + Reset)
+	// Write icon and project name
+	sb.WriteString(t.Bg(model.RoleTasksBg) + t.Fg(model.RoleTasksFg) + Bold + " " + ctx.Icons.Tasks + " " + project.Name + " " + Reset)
+
+	// Keep only the task count in compact form
+	if ctx.Compact {
+		sb.WriteString(t.Bg(model.RoleTasksBg) + t.Fg(model.RoleTasksFg) + Bold + itoa(project.Completed) + "/" + itoa(project.Total()) + " " + Reset)
+		sb.WriteString(t.Fg(model.RoleTasksBg) + seps.CapRight + Reset)
+		// Return without nested bar pill
+		return
+	}
+	bar := RenderProgressBar(progress, ParseProgressBarStyle(barCfg.Style), barCfg.Width)
+
+	// Write nested progress bar pill
+	sb.WriteString(t.Bg(model.RoleTasksBg) + t.Fg(model.RoleTasksBarBg) + seps.CapLeft + Reset)
+	// Progress bar on the nested pill background with progress color
+	sb.WriteString(t.Bg(model.RoleTasksBarBg) + progressColor + " " + bar + " " + Reset)
+	// Add task count (completed/total)
+	sb.WriteString(t.Bg(model.RoleTasksBarBg) + t.Fg(model.RoleTasksBarFg) + Bold + itoa(project.Completed) + "/" + itoa(project.Total()) + " " + Reset)
+	// Write right cap
+	sb.WriteString(t.Fg(model.RoleTasksBarBg) + seps.CapRight + Reset)
+}
+
+// renderSessionPill renders a project with Epic/Task session data.
+// Format: 📋 feat-auth ▐━━━━│━━●─│────▌ 41% │ ▶ E2:T2 "AuthService", with the
+// glyphs of the icon set
+//
+// Params:
+//   - sb: string builder to write to
+//   - ctx: render context (theme, icon and separator sets)
+//   - project: project with session data
+func (s tasksSegment) renderSessionPill(sb *strings.Builder, ctx *RenderContext, project *model.TaskwarriorProject) {
+	t, seps, icons := ctx.Theme, ctx.Separators, ctx.Icons
+	// Write left cap
+	sb.WriteString(t.Fg(model.RoleTasksBg) + seps.CapLeft + Reset)
+
+	// Write icon and project name
+	sb.WriteString(t.Bg(model.RoleTasksBg) + t.Fg(model.RoleTasksFg) + Bold + " " + icons.Tasks + " " + project.Name + " " + Reset)
+
+	// Render segmented progress bar, left out of the compact form
+	pad := ""
+	if !ctx.Compact {
+		bar := s.renderSegmentedProgressBar(t, icons, project.Epics)
+		sb.WriteString(t.Bg(model.RoleTasksBg) + bar + Reset)
+		pad = " "
+	}
+
+	// Write percentage
+	sb.WriteString(t.Bg(model.RoleTasksBg) + t.Fg(model.RoleTasksFg) + Bold + pad + itoa(project.Percent()) + "% " + Reset)
+
+	// Render mode/task indicator
+	if project.IsPlanMode() {
+		// Show PLAN MODE indicator
+		sb.WriteString(t.Bg(model.RoleTasksBg) + t.Fg(model.RoleTasksMuted) + icons.EpicSeparator + " " + Reset)
+		sb.WriteString(t.Bg(model.RoleTasksBg) + t.Fg(model.RoleTasksMuted) + icons.Plan + Reset)
+	} else if project.CurrentTask != "" {
+		// Show current task indicator
+		sb.WriteString(t.Bg(model.RoleTasksBg) + t.Fg(model.RoleTasksMuted) + icons.EpicSeparator + " " + Reset)
+		sb.WriteString(t.Bg(model.RoleTasksBg) + t.Fg(model.RoleTasksCurrent) + Bold + icons.Current + " E" + itoa(project.CurrentEpic) + ":" + project.CurrentTask + Reset)
+		// Show task name if available and there is room
+		if taskName := project.CurrentTaskName(); taskName != "" && !ctx.Compact {
+			sb.WriteString(t.Bg(model.RoleTasksBg) + t.Fg(model.RoleTasksMuted) + " \"" + taskName + "\"" + Reset)
+		}
+	}
+
+	// Write right cap
+	sb.WriteString(t.Bg(model.RoleTasksBg) + " " + Reset)
+	sb.WriteString(t.Fg(model.RoleTasksBg) + seps.CapRight + Reset)
+}
+
+// renderSegmentedProgressBar renders a progress bar segmented by epics.
+// Format: ▐━━━━│━━●─│────▌
+//
+// Params:
+//   - t: active theme
+//   - icons: active icon set
+//   - epics: list of epics with task data
+//
+// Returns:
+//   - string: rendered segmented progress bar with ANSI codes
+func (s tasksSegment) renderSegmentedProgressBar(t *Theme, icons *IconSet, epics []model.TaskwarriorEpic) string {
+	var sb strings.Builder
+
+	// Left border
+	sb.WriteString(t.Fg(model.RoleTasksMuted) + icons.EpicLeft + Reset)
+
+	// Render each epic segment
+	for i, epic := range epics {
+		// Add separator between epics
+		if i > 0 {
+			sb.WriteString(t.Fg(model.RoleTasksMuted) + icons.EpicSeparator + Reset)
+		}
+		// Render epic bar
+		sb.WriteString(s.renderEpicBar(t, icons, &epic))
+	}
+
+	// Right border
+	sb.WriteString(t.Fg(model.RoleTasksMuted) + icons.EpicRight + Reset)
+
+	return sb.String()
+}
+
+// renderEpicBar renders a single epic's progress bar segment.
+//
+// Params:
+//   - t: active theme
+//   - icons: active icon set
+//   - epic: epic with task data
+//
+// Returns:
+//   - string: rendered epic bar segment
+func (tasksSegment) renderEpicBar(t *Theme, icons *IconSet, epic *model.TaskwarriorEpic) string {
+	// Calculate width (min 2, max 8)
+	width := epic.TotalCount
+	if width < epicBarMinWidth {
+		width = epicBarMinWidth
+	}
+	if width > epicBarMaxWidth {
+		width = epicBarMaxWidth
+	}
+
+	// Calculate proportions
+	doneWidth := 0
+	wipWidth := 0
+	todoWidth := width
+
+	if epic.TotalCount > 0 {
+		doneWidth = epic.DoneCount * width / epic.TotalCount
+		// Check for WIP task
+		for _, task := range epic.Tasks {
+			if task.Status == model.StatusWip {
+				wipWidth = 1
+				break
+			}
+		}
+		// Adjust done width if WIP present
+		if wipWidth > 0 && doneWidth > 0 {
+			doneWidth--
+		}
+		todoWidth = width - doneWidth - wipWidth
+	}
+
+	var sb strings.Builder
+
+	// Done characters (heavy line)
+	if doneWidth > 0 {
+		sb.WriteString(t.Fg(model.RoleTasksDone) + strings.Repeat(icons.EpicDone, doneWidth) + Reset)
+	}
+
+	// WIP character (cursor)
+	if wipWidth > 0 {
+		sb.WriteString(t.Fg(model.RoleTasksWip) + icons.EpicWip + Reset)
+	}
+
+	// Todo characters (light line)
+	if todoWidth > 0 {
+		sb.WriteString(t.Fg(model.RoleTasksTodo) + strings.Repeat(icons.EpicTodo, todoWidth) + Reset)
+	}
+
+	return sb.String()
+}
+
+// mcpSegment shows MCP servers as pills.
+type mcpSegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (mcpSegment) ID() string {
+	// Return identifier
+	return model.SegmentMCP
+}
+
+// Enabled returns true when MCP servers are configured.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: true if there is at least one server
+func (mcpSegment) Enabled(data model.StatusLineData) bool {
+	// Check for servers
+	return len(data.MCP) > 0
+}
+
+// Priority returns PriorityLowest: MCP pills go first on narrow terminals.
+//
+// Returns:
+//   - int: segment priority
+func (mcpSegment) Priority() int {
+	// Return priority
+	return PriorityLowest
+}
+
+// Render renders MCP server pills separated by spaces.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: pill content
+func (s mcpSegment) Render(ctx *RenderContext) SegmentOutput {
+	var sb strings.Builder
+	// Summarize all servers in one pill in compact form
+	if ctx.Compact {
+		s.renderSummaryPill(&sb, ctx, ctx.Data.MCP)
+		// Return summary pill
+		return SegmentOutput{Text: sb.String(), Pill: true}
+	}
+	// Render each server as a pill
+	for idx, server := range ctx.Data.MCP {
+		// Add space between pills
+		if idx > 0 {
+			sb.WriteString(" ")
+		}
+		// Render individual MCP pill
+		s.renderPill(&sb, ctx, server)
+	}
+	// Return server pills
+	return SegmentOutput{Text: sb.String(), Pill: true}
+}
+
+// renderSummaryPill renders one pill counting enabled servers.
+// Format: mcp 2/3
+//
+// Params:
+//   - sb: string builder to write to
+//   - ctx: render context (theme and separator set)
+//   - servers: MCP servers to count
+func (s mcpSegment) renderSummaryPill(sb *strings.Builder, ctx *RenderContext, servers []model.MCPServer) {
+	enabled := 0
+	// Count enabled servers
+	for _, server := range servers {
+		// Check status
+		if server.Enabled {
+			enabled++
+		}
+	}
+	// Reuse the single pill with enabled colors when any server is up
+	s.renderPill(sb, ctx, model.MCPServer{
+		Name:    mcpSummaryLabel + " " + itoa(enabled) + "/" + itoa(len(servers)),
+		Enabled: enabled > 0,
+	})
+}
+
+// renderPill renders a single MCP server pill.
+//
+// Params:
+//   - sb: string builder to write to
+//   - ctx: render context (theme and separator set)
+//   - server: MCP server information
+func (mcpSegment) renderPill(sb *strings.Builder, ctx *RenderContext, server model.MCPServer) {
+	t := ctx.Theme
+	var bgColor, fgColor, textColor string
+
+	// Select colors based on enabled status
+	if server.Enabled {
+		// Use enabled colors
+		bgColor = t.Bg(model.RoleMCPEnabledBg)
+		fgColor = t.Fg(model.RoleMCPEnabledBg)
+		textColor = t.Fg(model.RoleMCPEnabledFg)
+	} else {
+		// Use disabled colors
+		bgColor = t.Bg(model.RoleMCPDisabledBg)
+		fgColor = t.Fg(model.RoleMCPDisabledBg)
+		textColor = t.Fg(model.RoleMCPDisabledFg)
+	}
+
+	// Write left cap
+	sb.WriteString(fgColor + ctx.Separators.CapLeft + Reset)
+	// Write server name
+	sb.WriteString(bgColor + textColor + " " + server.Name + " " + Reset)
+	// Write right cap
+	sb.WriteString(fgColor + ctx.Separators.CapRight + Reset)
+}
+
+// updateSegment shows the update notification pill.
+type updateSegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (updateSegment) ID() string {
+	// Return identifier
+	return model.SegmentUpdate
+}
+
+// Enabled returns true when a newer version is available.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: true if an update is available
+func (updateSegment) Enabled(data model.StatusLineData) bool {
+	// Check for update
+	return data.Update.Available
+}
+
+// Priority returns PriorityLowest: the update notification can be missed.
+//
+// Returns:
+//   - int: segment priority
+func (updateSegment) Priority() int {
+	// Return priority
+	return PriorityLowest
+}
+
+// Render renders the update notification pill.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: pill content
+func (updateSegment) Render(ctx *RenderContext) SegmentOutput {
+	t := ctx.Theme
+	var sb strings.Builder
+	// Write left cap
+	sb.WriteString(t.Fg(model.RoleUpdateBg) + ctx.Separators.CapLeft + Reset)
+	label := " " + ctx.Data.Update.Version
+	// Show only the icon in compact form
+	if ctx.Compact {
+		label = ""
+	}
+	// Write update icon and version
+	sb.WriteString(t.Bg(model.RoleUpdateBg) + t.Fg(model.RoleUpdateFg) + Bold + " " + ctx.Icons.Update + label + " " + Reset)
+	// Write right cap
+	sb.WriteString(t.Fg(model.RoleUpdateBg) + ctx.Separators.CapRight + Reset)
+	// Return update pill
+	return SegmentOutput{Text: sb.String(), Pill: true}
+}
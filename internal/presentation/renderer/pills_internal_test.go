@@ -0,0 +1,196 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestTasksSegment(t *testing.T) {
+	tests := []struct {
+		name        string
+		tw          model.TaskwarriorInfo
+		wantEnabled bool
+		wantText    []string
+	}{
+		{
+			name:        "installed with projects",
+			tw:          model.TaskwarriorInfo{Installed: true, Projects: []model.TaskwarriorProject{{Name: "api", Pending: 5}, {Name: "web", Completed: 2}}},
+			wantEnabled: true,
+			wantText:    []string{"api", "web", "0/5", "2/2"},
+		},
+		{name: "installed without projects", tw: model.TaskwarriorInfo{Installed: true}, wantEnabled: false},
+		{name: "not installed", tw: model.TaskwarriorInfo{Installed: false}, wantEnabled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := model.StatusLineData{Taskwarrior: tt.tw}
+			if got := (tasksSegment{}).Enabled(data); got != tt.wantEnabled {
+				t.Errorf("Enabled() = %v, want %v", got, tt.wantEnabled)
+			}
+			if !tt.wantEnabled {
+				return
+			}
+			got := tasksSegment{}.Render(&RenderContext{Data: data, Config: model.DefaultConfig(), Theme: DefaultTheme(), Icons: NerdIcons(), Separators: PowerlineSeparators()})
+			if !got.Pill {
+				t.Error("Render() should produce a pill")
+			}
+			for _, want := range tt.wantText {
+				if !strings.Contains(got.Text, want) {
+					t.Errorf("Render() = %q, want to contain %q", got.Text, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTasksSegment_renderEpicBar(t *testing.T) {
+	tests := []struct {
+		name     string
+		epic     model.TaskwarriorEpic
+		wantDone int
+		wantWip  int
+		wantTodo int
+	}{
+		{name: "empty epic uses minimum width", epic: model.TaskwarriorEpic{}, wantTodo: 2},
+		{name: "half done", epic: model.TaskwarriorEpic{TotalCount: 4, DoneCount: 2}, wantDone: 2, wantTodo: 2},
+		{
+			name:     "wip takes a done slot",
+			epic:     model.TaskwarriorEpic{TotalCount: 4, DoneCount: 2, Tasks: []model.TaskwarriorTask{{Status: model.StatusWip}}},
+			wantDone: 1, wantWip: 1, wantTodo: 2,
+		},
+		{name: "wide epic is capped", epic: model.TaskwarriorEpic{TotalCount: 20}, wantTodo: 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tasksSegment{}.renderEpicBar(DefaultTheme(), NerdIcons(), &tt.epic)
+			if n := strings.Count(got, "━"); n != tt.wantDone {
+				t.Errorf("renderEpicBar() done = %d, want %d", n, tt.wantDone)
+			}
+			if n := strings.Count(got, "●"); n != tt.wantWip {
+				t.Errorf("renderEpicBar() wip = %d, want %d", n, tt.wantWip)
+			}
+			if n := strings.Count(got, "─"); n != tt.wantTodo {
+				t.Errorf("renderEpicBar() todo = %d, want %d", n, tt.wantTodo)
+			}
+		})
+	}
+}
+
+func TestTasksSegment_Render_ASCII(t *testing.T) {
+	epics := []model.TaskwarriorEpic{
+		{ID: 1, TotalCount: 2, DoneCount: 2},
+		{ID: 2, TotalCount: 4, DoneCount: 1, Tasks: []model.TaskwarriorTask{{ID: "2.2", Name: "AuthService", Status: model.StatusWip}}},
+	}
+	tests := []struct {
+		name    string
+		project model.TaskwarriorProject
+		want    []string
+	}{
+		{
+			name:    "current task",
+			project: model.TaskwarriorProject{Name: "feat-auth", Epics: epics, CurrentEpic: 2, CurrentTask: "2.2"},
+			want:    []string{"[", "==", "|", "*", "-", "]", "> E2:2.2", "AuthService"},
+		},
+		{
+			name:    "plan mode",
+			project: model.TaskwarriorProject{Name: "feat-auth", Epics: epics, Mode: model.ModePlan},
+			want:    []string{"| ", "PLAN"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seps, _ := LookupSeparatorSet(model.SeparatorsFlat)
+			data := model.StatusLineData{Taskwarrior: model.TaskwarriorInfo{Installed: true, ActiveProject: &tt.project}}
+			got := tasksSegment{}.Render(&RenderContext{Data: data, Config: model.DefaultConfig(), Theme: DefaultTheme(), Icons: ASCIIIcons(), Separators: seps})
+			for _, r := range got.Text {
+				if r > 0x7F {
+					t.Fatalf("Render() = %q, contains non-ASCII rune %q", got.Text, r)
+				}
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got.Text, want) {
+					t.Errorf("Render() = %q, want to contain %q", got.Text, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMCPSegment(t *testing.T) {
+	tests := []struct {
+		name        string
+		servers     model.MCPServers
+		wantEnabled bool
+	}{
+		{name: "with servers", servers: model.MCPServers{{Name: "github", Enabled: true}, {Name: "slack", Enabled: false}}, wantEnabled: true},
+		{name: "empty", servers: model.MCPServers{}, wantEnabled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := model.StatusLineData{MCP: tt.servers}
+			if got := (mcpSegment{}).Enabled(data); got != tt.wantEnabled {
+				t.Errorf("Enabled() = %v, want %v", got, tt.wantEnabled)
+			}
+			if !tt.wantEnabled {
+				return
+			}
+			th := DefaultTheme()
+			got := mcpSegment{}.Render(&RenderContext{Data: data, Theme: th, Icons: NerdIcons(), Separators: PowerlineSeparators()})
+			if !strings.Contains(got.Text, th.Bg(model.RoleMCPEnabledBg)) || !strings.Contains(got.Text, th.Bg(model.RoleMCPDisabledBg)) {
+				t.Errorf("Render() = %q, want enabled and disabled pills", got.Text)
+			}
+		})
+	}
+}
+
+func TestUpdateSegment(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      model.UpdateInfo
+		wantEnabled bool
+	}{
+		{name: "with update available", update: model.UpdateInfo{Available: true, Version: "v1.0.0"}, wantEnabled: true},
+		{name: "no update", update: model.UpdateInfo{Available: false}, wantEnabled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := model.StatusLineData{Update: tt.update}
+			if got := (updateSegment{}).Enabled(data); got != tt.wantEnabled {
+				t.Errorf("Enabled() = %v, want %v", got, tt.wantEnabled)
+			}
+			if !tt.wantEnabled {
+				return
+			}
+			if got := (updateSegment{}).Render(&RenderContext{Data: data, Theme: DefaultTheme(), Icons: NerdIcons(), Separators: PowerlineSeparators()}); !strings.Contains(got.Text, tt.update.Version) {
+				t.Errorf("Render() = %q, want to contain %q", got.Text, tt.update.Version)
+			}
+		})
+	}
+}
+
+func TestTasksSegment_Render_Compact(t *testing.T) {
+	tests := []struct {
+		name    string
+		tw      model.TaskwarriorInfo
+		want    string
+		notWant string
+	}{
+		{
+			name:    "project pill keeps count only",
+			tw:      model.TaskwarriorInfo{Projects: []model.TaskwarriorProject{{Name: "api", Completed: 2, Pending: 2}}},
+			want:    "2/4",
+			notWant: "━",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &RenderContext{Data: model.StatusLineData{Taskwarrior: tt.tw}, Config: model.DefaultConfig(), Theme: DefaultTheme(), Icons: NerdIcons(), Separators: PowerlineSeparators(), Compact: true}
+			got := tasksSegment{}.Render(ctx)
+			if !strings.Contains(got.Text, tt.want) || strings.Contains(got.Text, tt.notWant) {
+				t.Errorf("Render() = %q, want %q without %q", got.Text, tt.want, tt.notWant)
+			}
+		})
+	}
+}
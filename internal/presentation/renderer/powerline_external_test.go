@@ -16,7 +16,7 @@ func TestNewPowerline(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r := renderer.NewPowerline()
+			r := renderer.NewPowerline(model.DefaultConfig())
 			if r == nil {
 				t.Error("NewPowerline() returned nil")
 			}
@@ -53,7 +53,7 @@ func TestPowerline_Render(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r := renderer.NewPowerline()
+			r := renderer.NewPowerline(model.DefaultConfig())
 			result := r.Render(tt.data)
 			if result == "" {
 				t.Error("Render() returned empty string")
@@ -64,3 +64,177 @@ func TestPowerline_Render(t *testing.T) {
 		})
 	}
 }
+
+func TestPowerline_Render_Layout(t *testing.T) {
+	tests := []struct {
+		name      string
+		layout    model.Layout
+		wantLines int
+	}{
+		{name: "default two lines", layout: model.DefaultLayout(), wantLines: 2},
+		{name: "single line", layout: model.Layout{{"os", "model", "path", "mcp"}}, wantLines: 1},
+		{name: "three lines", layout: model.Layout{{"os"}, {"model"}, {"path"}}, wantLines: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Layout = tt.layout
+			result := renderer.NewPowerline(cfg).Render(model.StatusLineData{Model: model.ModelInfo{Name: "Opus"}, Dir: "/w"})
+			if got := strings.Count(result, "\n"); got != tt.wantLines {
+				t.Errorf("Render() lines = %d, want %d", got, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestNewPowerlineWithRegistry(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout model.Layout
+		want   string
+	}{
+		{name: "renders custom segment", layout: model.Layout{{"clock"}}, want: "12:00\n"},
+		{name: "skips unregistered segment", layout: model.Layout{{"os", "clock"}}, want: "12:00\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := renderer.NewRegistry()
+			if err := reg.Register(clockSegment{id: "clock"}); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			cfg := model.DefaultConfig()
+			cfg.Layout = tt.layout
+			if got := renderer.NewPowerlineWithRegistry(cfg, reg).Render(model.StatusLineData{}); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPowerline_Render_ColorDepth(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		detected   model.ColorDepth
+		want       []string
+		notWant    []string
+	}{
+		{name: "undetected uses palette", configured: model.ColorDepthAuto, want: []string{"\033[48;5;111m"}},
+		{name: "detected basic colors", configured: model.ColorDepthAuto, detected: model.ColorDepth16, want: []string{"\033[104m"}, notWant: []string{";5;"}},
+		{name: "detected no colors", configured: model.ColorDepthAuto, detected: model.ColorDepthNone, want: []string{"/w"}, notWant: []string{"\033["}},
+		{name: "configured overrides detected", configured: "none", detected: model.ColorDepthTrueColor, want: []string{"/w"}, notWant: []string{"\033["}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.ColorDepth = tt.configured
+			cfg.Layout = model.Layout{{"path"}}
+			got := renderer.NewPowerline(cfg).Render(model.StatusLineData{Dir: "/w", Terminal: model.TerminalInfo{ColorDepth: tt.detected}})
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Render() = %q, want to contain %q", got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Render() = %q, want not to contain %q", got, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestPowerline_Render_GlyphSets(t *testing.T) {
+	tests := []struct {
+		name       string
+		iconSet    string
+		separators string
+		want       string
+	}{
+		{name: "ascii flat", iconSet: model.IconSetASCII, separators: model.SeparatorsAuto, want: " [dir] /w | [git] main |\n"},
+		{name: "unicode flat", iconSet: model.IconSetUnicode, separators: model.SeparatorsFlat, want: " 📁 /w | ⎇ main |\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.IconSet, cfg.Separators = tt.iconSet, tt.separators
+			cfg.ColorDepth = "none"
+			cfg.Layout = model.Layout{{"path", "git"}}
+			data := model.StatusLineData{
+				Dir:   "/w",
+				Git:   model.GitStatus{Branch: "main"},
+				Icons: model.IconConfig{Path: true, Git: true},
+			}
+			if got := renderer.NewPowerline(cfg).Render(data); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPowerline_Render_Width(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{name: "unknown width keeps everything", width: 0, want: " [linux] | [ai] Opus ──────────────────── 0% | [dir] .../projects/status-line | [git] feature/responsive-layout |  github   sentry \n"},
+		{name: "wide terminal keeps everything", width: 200, want: " [linux] | [ai] Opus ──────────────────── 0% | [dir] .../projects/status-line | [git] feature/responsive-layout |  github   sentry \n"},
+		{name: "lowest priority compacts first", width: 125, want: " [linux] | [ai] Opus ──────────────────── 0% | [dir] .../projects/status-line | [git] feature/responsive-layout |  mcp 1/2 \n"},
+		{name: "all compact", width: 80, want: " [linux] | [ai] Opus 0% | [dir] .../status-line | [git] feature/res… |  mcp 1/2 \n"},
+		{name: "lowest priority dropped", width: 60, want: " [ai] Opus 0% | [dir] .../status-line | [git] feature/res… |\n"},
+		{name: "rightmost dropped among equals", width: 50, want: " [ai] Opus 0% | [dir] .../status-line |\n"},
+		{name: "highest priority kept", width: 5, want: " [ai] Opus 0% |\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.IconSet, cfg.ColorDepth = model.IconSetASCII, "none"
+			cfg.Layout = model.Layout{{"os", "model", "path", "git", "mcp"}}
+			data := model.StatusLineData{
+				Model:    model.ModelInfo{Name: "Opus"},
+				Dir:      "/workspace/projects/status-line",
+				Git:      model.GitStatus{Branch: "feature/responsive-layout"},
+				Icons:    model.IconConfig{OS: true, Model: true, Path: true, Git: true},
+				System:   model.SystemInfo{OS: model.OSLinux},
+				MCP:      []model.MCPServer{{Name: "github", Enabled: true}, {Name: "sentry"}},
+				Terminal: model.TerminalInfo{Width: tt.width},
+			}
+			if got := renderer.NewPowerline(cfg).Render(data); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPowerline_Render_RightGroup(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{name: "unknown width joins groups with a space", width: 0, want: " [ai] Opus ──────────────────── 0% | [dir] /w | | [git] feature/responsive-layout   [update] v1.2.0 \n"},
+		{name: "padded to the right edge", width: 110, want: " [ai] Opus ──────────────────── 0% | [dir] /w |           | [git] feature/responsive-layout   [update] v1.2.0 \n"},
+		{name: "right group compacts first", width: 80, want: " [ai] Opus ──────────────────── 0% | [dir] /w | | [git] feature/res…   [update] \n"},
+		{name: "right group dropped before the left shrinks", width: 60, want: " [ai] Opus ──────────────────── 0% | [dir] /w |\n"},
+		{name: "left group shrinks last", width: 30, want: " [ai] Opus 0% | [dir] /w |\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.IconSet, cfg.ColorDepth = model.IconSetASCII, "none"
+			cfg.Layout = model.Layout{{"model", "path", model.AlignRight, "git", "update"}}
+			data := model.StatusLineData{
+				Model:    model.ModelInfo{Name: "Opus"},
+				Dir:      "/w",
+				Git:      model.GitStatus{Branch: "feature/responsive-layout"},
+				Icons:    model.IconConfig{Model: true, Path: true, Git: true},
+				Update:   model.UpdateInfo{Available: true, Version: "v1.2.0"},
+				Terminal: model.TerminalInfo{Width: tt.width},
+			}
+			if got := renderer.NewPowerline(cfg).Render(data); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,13 +7,15 @@ import (
 	"github.com/florent/status-line/internal/domain/model"
 )
 
-func TestPowerline_renderLine1(t *testing.T) {
+func TestPowerline_renderLine(t *testing.T) {
 	tests := []struct {
 		name string
+		ids  []string
 		data model.StatusLineData
 	}{
 		{
 			name: "with git and changes",
+			ids:  model.DefaultLayout()[0],
 			data: model.StatusLineData{
 				Model:    model.ModelInfo{Name: "Opus", Version: "4.5"},
 				Progress: model.Progress{Percent: 50},
@@ -26,32 +28,16 @@ func TestPowerline_renderLine1(t *testing.T) {
 		},
 		{
 			name: "without git",
+			ids:  model.DefaultLayout()[0],
 			data: model.StatusLineData{
 				Model:  model.ModelInfo{Name: "Sonnet"},
 				System: model.SystemInfo{OS: model.OSDarwin},
 				Dir:    "/Users/test",
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			r.renderLine1(&sb, tt.data)
-			if sb.Len() == 0 {
-				t.Error("renderLine1() produced empty output")
-			}
-		})
-	}
-}
-
-func TestPowerline_renderLine2(t *testing.T) {
-	tests := []struct {
-		name string
-		data model.StatusLineData
-	}{
 		{
 			name: "with taskwarrior",
+			ids:  model.DefaultLayout()[1],
 			data: model.StatusLineData{
 				Taskwarrior: model.TaskwarriorInfo{
 					Installed: true,
@@ -59,19 +45,15 @@ func TestPowerline_renderLine2(t *testing.T) {
 				},
 			},
 		},
-		{
-			name: "empty",
-			data: model.StatusLineData{
-				Taskwarrior: model.TaskwarriorInfo{Installed: false},
-			},
-		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
+			r := NewPowerline(model.DefaultConfig())
 			var sb strings.Builder
-			r.renderLine2(&sb, tt.data)
-			_ = sb.String() // Just verify no panic
+			r.renderLine(&sb, tt.ids, tt.data)
+			if sb.Len() == 0 {
+				t.Error("renderLine() produced empty output")
+			}
 		})
 	}
 }
@@ -95,196 +77,3 @@ func TestItoa(t *testing.T) {
 		})
 	}
 }
-
-func TestPowerline_renderOSSegment(t *testing.T) {
-	tests := []struct {
-		name string
-	}{
-		{name: "renders OS segment"},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			r.renderOSSegment(&sb, model.SystemInfo{OS: model.OSLinux}, true, BgBlue)
-			if sb.Len() == 0 {
-				t.Error("renderOSSegment() produced empty output")
-			}
-		})
-	}
-}
-
-func TestPowerline_renderModelSegment(t *testing.T) {
-	tests := []struct {
-		name string
-	}{
-		{name: "renders model segment"},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			data := &ModelSegmentData{
-				Model:    model.ModelInfo{Name: "Opus"},
-				ShowIcon: true,
-				Progress: model.Progress{Percent: 50},
-				Cursor:   nil,
-				NextBg:   BgBlue,
-			}
-			r.renderModelSegment(&sb, data)
-			if sb.Len() == 0 {
-				t.Error("renderModelSegment() produced empty output")
-			}
-		})
-	}
-}
-
-func TestPowerline_renderPathSegment(t *testing.T) {
-	tests := []struct {
-		name string
-	}{
-		{name: "renders path segment"},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			r.renderPathSegment(&sb, "/workspace", true, true, "")
-			if sb.Len() == 0 {
-				t.Error("renderPathSegment() produced empty output")
-			}
-		})
-	}
-}
-
-func TestPowerline_renderGitSegment(t *testing.T) {
-	tests := []struct {
-		name string
-		git  model.GitStatus
-	}{
-		{name: "with branch", git: model.GitStatus{Branch: "main"}},
-		{name: "not in repo", git: model.GitStatus{}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			r.renderGitSegment(&sb, tt.git, true, "")
-			_ = sb.String() // Just verify no panic
-		})
-	}
-}
-
-func TestPowerline_renderChangesSegment(t *testing.T) {
-	tests := []struct {
-		name    string
-		changes model.CodeChanges
-	}{
-		{name: "with changes", changes: model.CodeChanges{Added: 10, Removed: 5}},
-		{name: "no changes", changes: model.CodeChanges{}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			r.renderChangesSegment(&sb, tt.changes)
-			_ = sb.String() // Just verify no panic
-		})
-	}
-}
-
-func TestPowerline_renderMCPPills(t *testing.T) {
-	tests := []struct {
-		name    string
-		servers model.MCPServers
-	}{
-		{name: "with servers", servers: model.MCPServers{{Name: "test", Enabled: true}}},
-		{name: "empty", servers: model.MCPServers{}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			r.renderMCPPills(&sb, tt.servers)
-			_ = sb.String() // Just verify no panic
-		})
-	}
-}
-
-func TestPowerline_renderMCPPill(t *testing.T) {
-	tests := []struct {
-		name   string
-		server model.MCPServer
-	}{
-		{name: "enabled", server: model.MCPServer{Name: "test", Enabled: true}},
-		{name: "disabled", server: model.MCPServer{Name: "test", Enabled: false}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			r.renderMCPPill(&sb, tt.server)
-			if sb.Len() == 0 {
-				t.Error("renderMCPPill() produced empty output")
-			}
-		})
-	}
-}
-
-func TestPowerline_renderTaskwarriorPill(t *testing.T) {
-	tests := []struct {
-		name string
-		tw   model.TaskwarriorInfo
-	}{
-		{name: "installed with projects", tw: model.TaskwarriorInfo{Installed: true, Projects: []model.TaskwarriorProject{{Name: "test", Pending: 5}}}},
-		{name: "not installed", tw: model.TaskwarriorInfo{Installed: false}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			r.renderTaskwarriorPill(&sb, tt.tw)
-			_ = sb.String() // Just verify no panic
-		})
-	}
-}
-
-func TestPowerline_renderTaskwarriorProjectPill(t *testing.T) {
-	tests := []struct {
-		name    string
-		project model.TaskwarriorProject
-	}{
-		{name: "with tasks", project: model.TaskwarriorProject{Name: "test", Pending: 5, Completed: 3}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			r.renderTaskwarriorProjectPill(&sb, tt.project)
-			if sb.Len() == 0 {
-				t.Error("renderTaskwarriorProjectPill() produced empty output")
-			}
-		})
-	}
-}
-
-func TestPowerline_renderUpdatePill(t *testing.T) {
-	tests := []struct {
-		name   string
-		update model.UpdateInfo
-	}{
-		{name: "with update available", update: model.UpdateInfo{Available: true, Version: "v1.0.0"}},
-		{name: "no update", update: model.UpdateInfo{Available: false}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			r := &Powerline{}
-			var sb strings.Builder
-			r.renderUpdatePill(&sb, tt.update)
-			// Should produce output only if update available
-			if tt.update.Available && sb.Len() == 0 {
-				t.Error("renderUpdatePill() produced empty output for available update")
-			}
-		})
-	}
-}
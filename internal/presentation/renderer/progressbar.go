@@ -1,11 +1,15 @@
 // Package renderer provides status line rendering.
 package renderer
 
-import "github.com/florent/status-line/internal/domain/model"
+import (
+	"strings"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
 
 // Progress bar constants.
 const (
-	// progressBarWidth is the number of characters in the progress bar.
+	// progressBarWidth is the default number of characters in the progress bar.
 	progressBarWidth int = 20
 	// stepsPerChar is the number of granular steps per character.
 	stepsPerChar int = 8
@@ -19,8 +23,6 @@ const (
 	heavyEmpty rune = '\u2500'
 	// cursorChar is the burn-rate cursor indicator character.
 	cursorChar rune = '\u25CF'
-	// noCursor indicates no cursor should be rendered.
-	noCursor int = -1
 )
 
 // ProgressBarStyle represents the visual style of progress bars.
@@ -48,109 +50,130 @@ var (
 	}
 )
 
+// ParseProgressBarStyle converts a configured style name to a ProgressBarStyle.
+// Unknown names fall back to StyleHeavy.
+//
+// Params:
+//   - name: style name (heavy, block, braille)
+//
+// Returns:
+//   - ProgressBarStyle: matching style
+func ParseProgressBarStyle(name string) ProgressBarStyle {
+	// Select style by name
+	switch name {
+	// Block characters
+	case model.ProgressStyleBlock:
+		// Return block style
+		return StyleBlock
+	// Braille characters
+	case model.ProgressStyleBraille:
+		// Return braille style
+		return StyleBraille
+	// Heavy lines and unknown names
+	default:
+		// Return heavy style
+		return StyleHeavy
+	}
+}
+
 // RenderProgressBar generates a progress bar string.
 //
 // Params:
 //   - progress: the progress value (0-100%)
 //   - style: the visual style to use
+//   - width: number of characters (0 uses default)
 //
 // Returns:
 //   - string: rendered progress bar
-func RenderProgressBar(progress model.Progress, style ProgressBarStyle) string {
+func RenderProgressBar(progress model.Progress, style ProgressBarStyle, width int) string {
+	width = barWidth(width)
 	// Handle heavy style separately
 	if style == StyleHeavy {
 		// Render heavy style progress bar without cursor
-		return renderHeavyBar(progress, noCursor)
+		return renderHeavyBar(progress, width)
 	}
 	// Render granular style
-	return renderGranularBar(progress, style)
+	return renderGranularBar(progress, style, width)
 }
 
 // RenderProgressBarWithCursor generates a progress bar with a burn-rate cursor.
 //
 // Params:
 //   - progress: the progress value (0-100%)
+//   - style: the visual style to use
 //   - cursorPos: cursor position as percentage (0-100), or -1 for no cursor
+//   - width: number of characters (0 uses default)
 //   - cursorColor: ANSI color code for the cursor
 //   - bgColor: background color to restore after cursor
 //
 // Returns:
 //   - string: rendered progress bar with colored cursor
-func RenderProgressBarWithCursor(progress model.Progress, cursorPos int, cursorColor, bgColor string) string {
+func RenderProgressBarWithCursor(progress model.Progress, style ProgressBarStyle, cursorPos, width int, cursorColor, bgColor string) string {
+	width = barWidth(width)
 	// Calculate cursor character index
-	cursorIdx := cursorPos * progressBarWidth / percentMax
+	cursorIdx := cursorPos * width / percentMax
 	// Render bar with cursor
-	return renderHeavyBarWithCursor(progress, cursorIdx, cursorColor, bgColor)
+	return renderBarWithCursor(RenderProgressBar(progress, style, width), cursorIdx, cursorColor, bgColor)
+}
+
+// barWidth returns the effective bar width.
+//
+// Params:
+//   - width: requested width
+//
+// Returns:
+//   - int: requested width, or the default when not positive
+func barWidth(width int) int {
+	// Use default for unset width
+	if width <= 0 {
+		// Return default width
+		return progressBarWidth
+	}
+	// Return requested width
+	return width
 }
 
 // renderHeavyBar renders a progress bar using heavy horizontal characters.
 //
 // Params:
 //   - progress: the progress value
-//   - _cursorIdx: index for cursor character (unused, for API compatibility)
+//   - width: number of characters
 //
 // Returns:
 //   - string: rendered progress bar
-func renderHeavyBar(progress model.Progress, _cursorIdx int) string {
+func renderHeavyBar(progress model.Progress, width int) string {
 	// Calculate filled characters
-	filled := progress.Percent * progressBarWidth / percentMax
-	var result [progressBarWidth]rune
-	// Build progress bar
-	for i := range progressBarWidth {
-		// Determine if filled or empty
-		if i < filled {
-			result[i] = heavyFull
-		} else {
-			// Use empty character for unfilled portion
-			result[i] = heavyEmpty
-		}
-	}
-	// Return completed progress bar
-	return string(result[:])
+	filled := progress.Percent * width / percentMax
+	// Return filled and empty portions
+	return strings.Repeat(string(heavyFull), filled) + strings.Repeat(string(heavyEmpty), width-filled)
 }
 
-// renderHeavyBarWithCursor renders a progress bar with a colored cursor.
+// renderBarWithCursor puts a colored cursor over one character of a bar.
 //
 // Params:
-//   - progress: the progress value
-//   - cursorIdx: index for cursor character (0 to progressBarWidth-1)
+//   - bar: rendered progress bar
+//   - cursorIdx: index for cursor character (0 to width-1)
 //   - cursorColor: ANSI foreground color for the cursor
 //   - bgColor: background color to restore after cursor
 //
 // Returns:
 //   - string: rendered progress bar with ANSI color codes for cursor
-func renderHeavyBarWithCursor(progress model.Progress, cursorIdx int, cursorColor, bgColor string) string {
-	// Calculate filled characters
-	filled := progress.Percent * progressBarWidth / percentMax
-	// Pre-convert runes to strings for efficiency
-	fullChar := string(heavyFull)
-	emptyChar := string(heavyEmpty)
-	cursorIndicator := string(cursorChar)
+func renderBarWithCursor(bar string, cursorIdx int, cursorColor, bgColor string) string {
 	// Build result with color codes
-	var result []byte
-	// Build progress bar
-	for i := range progressBarWidth {
+	var sb strings.Builder
+	// Copy the bar, replacing the cursor position
+	for i, char := range []rune(bar) {
 		// Check if this is the cursor position
 		if i == cursorIdx {
 			// Write cursor with special color
-			result = append(result, cursorColor...)
-			result = append(result, cursorIndicator...)
-			result = append(result, Reset...)
-			result = append(result, bgColor...)
-		}
-		// Check if position is within filled portion
-		if i != cursorIdx && i < filled {
-			// Write filled character
-			result = append(result, fullChar...)
-		}
-		// Check if position is in empty portion
-		if i != cursorIdx && i >= filled {
-			// Write empty character
-			result = append(result, emptyChar...)
+			sb.WriteString(cursorColor + string(cursorChar) + Reset + bgColor)
+			continue
 		}
+		// Write bar character
+		sb.WriteRune(char)
 	}
 	// Return completed progress bar
-	return string(result)
+	return sb.String()
 }
 
 // renderGranularBar renders a progress bar using braille or block characters.
@@ -158,10 +181,11 @@ func renderHeavyBarWithCursor(progress model.Progress, cursorIdx int, cursorColo
 // Params:
 //   - progress: the progress value
 //   - style: StyleBraille or StyleBlock
+//   - width: number of characters
 //
 // Returns:
 //   - string: rendered progress bar
-func renderGranularBar(progress model.Progress, style ProgressBarStyle) string {
+func renderGranularBar(progress model.Progress, style ProgressBarStyle, width int) string {
 	chars := blockChars
 	// Select character set
 	if style == StyleBraille {
@@ -169,12 +193,12 @@ func renderGranularBar(progress model.Progress, style ProgressBarStyle) string {
 	}
 
 	// Calculate filled portion
-	totalSteps := progressBarWidth * stepsPerChar
+	totalSteps := width * stepsPerChar
 	filledSteps := progress.Percent * totalSteps / percentMax
 
-	var result [progressBarWidth]rune
+	result := make([]rune, width)
 	// Build progress bar
-	for i := range progressBarWidth {
+	for i := range width {
 		charSteps := filledSteps - (i * stepsPerChar)
 		// Determine character
 		switch {
@@ -190,5 +214,5 @@ func renderGranularBar(progress model.Progress, style ProgressBarStyle) string {
 		}
 	}
 	// Return completed progress bar
-	return string(result[:])
+	return string(result)
 }
@@ -1,6 +1,7 @@
 package renderer_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/florent/status-line/internal/domain/model"
@@ -12,18 +13,21 @@ func TestRenderProgressBar(t *testing.T) {
 		name    string
 		percent int
 		style   renderer.ProgressBarStyle
+		width   int
 		wantLen int
 	}{
-		{name: "heavy 0%", percent: 0, style: renderer.StyleHeavy, wantLen: 20},
-		{name: "heavy 50%", percent: 50, style: renderer.StyleHeavy, wantLen: 20},
-		{name: "heavy 100%", percent: 100, style: renderer.StyleHeavy, wantLen: 20},
-		{name: "block 50%", percent: 50, style: renderer.StyleBlock, wantLen: 20},
-		{name: "braille 75%", percent: 75, style: renderer.StyleBraille, wantLen: 20},
+		{name: "heavy 0%", percent: 0, style: renderer.StyleHeavy, width: 20, wantLen: 20},
+		{name: "heavy 50%", percent: 50, style: renderer.StyleHeavy, width: 20, wantLen: 20},
+		{name: "heavy 100%", percent: 100, style: renderer.StyleHeavy, width: 20, wantLen: 20},
+		{name: "block 50%", percent: 50, style: renderer.StyleBlock, width: 20, wantLen: 20},
+		{name: "braille 75%", percent: 75, style: renderer.StyleBraille, width: 20, wantLen: 20},
+		{name: "custom width", percent: 50, style: renderer.StyleHeavy, width: 8, wantLen: 8},
+		{name: "zero width uses default", percent: 50, style: renderer.StyleBlock, width: 0, wantLen: 20},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			progress := model.Progress{Percent: tt.percent}
-			result := renderer.RenderProgressBar(progress, tt.style)
+			result := renderer.RenderProgressBar(progress, tt.style, tt.width)
 			if len([]rune(result)) != tt.wantLen {
 				t.Errorf("RenderProgressBar() len = %d, want %d", len([]rune(result)), tt.wantLen)
 			}
@@ -35,20 +39,54 @@ func TestRenderProgressBarWithCursor(t *testing.T) {
 	tests := []struct {
 		name      string
 		percent   int
+		style     renderer.ProgressBarStyle
 		cursorPos int
+		wantFull  string
+		wantEmpty string
 	}{
-		{name: "cursor at start", percent: 50, cursorPos: 0},
-		{name: "cursor in middle", percent: 50, cursorPos: 50},
-		{name: "cursor at end", percent: 50, cursorPos: 100},
-		{name: "empty bar with cursor", percent: 0, cursorPos: 50},
-		{name: "full bar with cursor", percent: 100, cursorPos: 50},
+		{name: "cursor at start", percent: 50, style: renderer.StyleHeavy, cursorPos: 0, wantFull: "━", wantEmpty: "─"},
+		{name: "cursor in middle", percent: 50, style: renderer.StyleHeavy, cursorPos: 50, wantFull: "━", wantEmpty: "─"},
+		{name: "cursor at end", percent: 50, style: renderer.StyleHeavy, cursorPos: 100, wantFull: "━", wantEmpty: "─"},
+		{name: "empty bar with cursor", percent: 0, style: renderer.StyleHeavy, cursorPos: 50, wantEmpty: "─"},
+		{name: "full bar with cursor", percent: 100, style: renderer.StyleHeavy, cursorPos: 50, wantFull: "━"},
+		{name: "block style with cursor", percent: 50, style: renderer.StyleBlock, cursorPos: 75, wantFull: "█", wantEmpty: " "},
+		{name: "braille style with cursor", percent: 50, style: renderer.StyleBraille, cursorPos: 75, wantFull: "⣿", wantEmpty: " "},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			progress := model.Progress{Percent: tt.percent}
-			result := renderer.RenderProgressBarWithCursor(progress, tt.cursorPos, "\033[38;5;166m", "\033[0m")
-			if len(result) == 0 {
-				t.Error("RenderProgressBarWithCursor() returned empty string")
+			result := renderer.RenderProgressBarWithCursor(progress, tt.style, tt.cursorPos, 20, "\033[38;5;166m", "\033[0m")
+			if tt.wantFull != "" && !strings.Contains(result, tt.wantFull) {
+				t.Errorf("RenderProgressBarWithCursor() = %q, want filled with %q", result, tt.wantFull)
+			}
+			if tt.wantEmpty != "" && !strings.Contains(result, tt.wantEmpty) {
+				t.Errorf("RenderProgressBarWithCursor() = %q, want empty part %q", result, tt.wantEmpty)
+			}
+			if tt.style != renderer.StyleHeavy && strings.ContainsAny(result, "━─") {
+				t.Errorf("RenderProgressBarWithCursor() = %q, want no heavy glyphs", result)
+			}
+			if tt.cursorPos < 100 && !strings.Contains(result, "●") {
+				t.Errorf("RenderProgressBarWithCursor() = %q, want a cursor", result)
+			}
+		})
+	}
+}
+
+func TestParseProgressBarStyle(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  renderer.ProgressBarStyle
+	}{
+		{name: "heavy", input: "heavy", want: renderer.StyleHeavy},
+		{name: "block", input: "block", want: renderer.StyleBlock},
+		{name: "braille", input: "braille", want: renderer.StyleBraille},
+		{name: "unknown falls back", input: "dots", want: renderer.StyleHeavy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderer.ParseProgressBarStyle(tt.input); got != tt.want {
+				t.Errorf("ParseProgressBarStyle(%q) = %v, want %v", tt.input, got, tt.want)
 			}
 		})
 	}
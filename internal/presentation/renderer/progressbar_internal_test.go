@@ -19,7 +19,7 @@ func TestRenderHeavyBar(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			progress := model.Progress{Percent: tt.percent}
-			result := renderHeavyBar(progress, noCursor)
+			result := renderHeavyBar(progress, progressBarWidth)
 			if len([]rune(result)) != tt.wantLen {
 				t.Errorf("renderHeavyBar() len = %d, want %d", len([]rune(result)), tt.wantLen)
 			}
@@ -42,7 +42,7 @@ func TestRenderGranularBar(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			progress := model.Progress{Percent: tt.percent}
-			result := renderGranularBar(progress, tt.style)
+			result := renderGranularBar(progress, tt.style, progressBarWidth)
 			if len([]rune(result)) != tt.wantLen {
 				t.Errorf("renderGranularBar() len = %d, want %d", len([]rune(result)), tt.wantLen)
 			}
@@ -50,24 +50,24 @@ func TestRenderGranularBar(t *testing.T) {
 	}
 }
 
-func TestRenderHeavyBarWithCursor(t *testing.T) {
+func TestRenderBarWithCursor(t *testing.T) {
+	cursor := "<" + string(cursorChar) + Reset + ">"
 	tests := []struct {
 		name      string
-		percent   int
+		bar       string
 		cursorIdx int
+		want      string
 	}{
-		{name: "cursor at start", percent: 50, cursorIdx: 0},
-		{name: "cursor in middle", percent: 50, cursorIdx: 10},
-		{name: "cursor at end", percent: 50, cursorIdx: 19},
-		{name: "empty bar with cursor", percent: 0, cursorIdx: 10},
-		{name: "full bar with cursor", percent: 100, cursorIdx: 10},
+		{name: "cursor at start", bar: "━━──", cursorIdx: 0, want: cursor + "━──"},
+		{name: "cursor in middle", bar: "━━──", cursorIdx: 2, want: "━━" + cursor + "─"},
+		{name: "cursor at end", bar: "━━──", cursorIdx: 3, want: "━━─" + cursor},
+		{name: "cursor past the end", bar: "━━──", cursorIdx: 4, want: "━━──"},
+		{name: "granular bar", bar: "██▌ ", cursorIdx: 3, want: "██▌" + cursor},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			progress := model.Progress{Percent: tt.percent}
-			result := renderHeavyBarWithCursor(progress, tt.cursorIdx, FgCursorOrange, Reset)
-			if len(result) == 0 {
-				t.Error("renderHeavyBarWithCursor() returned empty string")
+			if got := renderBarWithCursor(tt.bar, tt.cursorIdx, "<", ">"); got != tt.want {
+				t.Errorf("renderBarWithCursor() = %q, want %q", got, tt.want)
 			}
 		})
 	}
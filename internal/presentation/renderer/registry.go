@@ -0,0 +1,132 @@
+// Package renderer provides status line rendering.
+package renderer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// defaultRegistry holds the built-in segments and any registered by other packages.
+var defaultRegistry *Registry = newBuiltinRegistry()
+
+// Registry maps segment identifiers to segments.
+// It is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	segments map[string]Segment
+	ids      []string
+}
+
+// NewRegistry creates an empty segment registry.
+//
+// Returns:
+//   - *Registry: registry without segments
+func NewRegistry() *Registry {
+	// Return empty registry
+	return &Registry{segments: make(map[string]Segment)}
+}
+
+// DefaultRegistry returns the registry used by NewPowerline.
+// It contains every built-in segment.
+//
+// Returns:
+//   - *Registry: shared default registry
+func DefaultRegistry() *Registry {
+	// Return shared registry
+	return defaultRegistry
+}
+
+// Register adds a segment to the default registry.
+//
+// Params:
+//   - seg: segment to add
+//
+// Returns:
+//   - error: if the ID is empty or already registered
+func Register(seg Segment) error {
+	// Delegate to default registry
+	return defaultRegistry.Register(seg)
+}
+
+// Register adds a segment to the registry.
+//
+// Params:
+//   - seg: segment to add
+//
+// Returns:
+//   - error: if the ID is empty or already registered
+func (r *Registry) Register(seg Segment) error {
+	id := seg.ID()
+	// Reject anonymous segments
+	if id == "" {
+		// Return empty ID error
+		return errors.New("segment ID must not be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Reject duplicates so built-ins cannot be replaced by accident
+	if _, exists := r.segments[id]; exists {
+		// Return duplicate error
+		return fmt.Errorf("segment %q already registered", id)
+	}
+	r.segments[id] = seg
+	r.ids = append(r.ids, id)
+	// Return success
+	return nil
+}
+
+// Lookup returns the segment registered under id.
+//
+// Params:
+//   - id: segment identifier
+//
+// Returns:
+//   - Segment: registered segment, nil if unknown
+//   - bool: true if the segment exists
+func (r *Registry) Lookup(id string) (Segment, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	seg, ok := r.segments[id]
+	// Return lookup result
+	return seg, ok
+}
+
+// IDs returns every registered identifier in registration order.
+//
+// Returns:
+//   - []string: copy of registered identifiers
+func (r *Registry) IDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	// Return copy so callers cannot mutate the registry
+	return append([]string(nil), r.ids...)
+}
+
+// newBuiltinRegistry creates a registry holding every built-in segment.
+//
+// Returns:
+//   - *Registry: registry with built-in segments
+func newBuiltinRegistry() *Registry {
+	r := NewRegistry()
+	// Add each built-in segment (IDs are unique by construction)
+	for _, seg := range builtinSegments() {
+		r.segments[seg.ID()] = seg
+		r.ids = append(r.ids, seg.ID())
+	}
+	// Return populated registry
+	return r
+}
+
+// builtinSegments returns the built-in segments in default display order.
+//
+// Returns:
+//   - []Segment: one instance of each built-in segment
+func builtinSegments() []Segment {
+	// Return built-ins
+	return []Segment{
+		osSegment{}, modelSegment{}, weeklySegment{}, sparklineSegment{}, pathSegment{}, gitSegment{}, changesSegment{}, costSegment{},
+		activitySegment{}, tasksSegment{}, mcpSegment{}, updateSegment{},
+	}
+}
@@ -0,0 +1,103 @@
+package renderer_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+	"github.com/florent/status-line/internal/presentation/renderer"
+)
+
+// clockSegment is a minimal third-party segment used by tests.
+type clockSegment struct {
+	id string
+}
+
+func (s clockSegment) ID() string { return s.id }
+
+func (clockSegment) Enabled(model.StatusLineData) bool { return true }
+
+func (clockSegment) Priority() int { return renderer.PriorityNormal }
+
+func (clockSegment) Render(ctx *renderer.RenderContext) renderer.SegmentOutput {
+	if ctx.Compact {
+		return renderer.SegmentOutput{Text: "12h", Pill: true}
+	}
+	return renderer.SegmentOutput{Text: "12:00", Pill: true}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "registers every built-in segment", want: model.KnownSegments()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderer.DefaultRegistry().IDs()
+			for _, id := range tt.want {
+				if !slices.Contains(got, id) {
+					t.Errorf("DefaultRegistry().IDs() = %v, missing %q", got, id)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []string
+		wantErr bool
+		wantIDs []string
+	}{
+		{name: "single segment", ids: []string{"clock"}, wantIDs: []string{"clock"}},
+		{name: "keeps registration order", ids: []string{"clock", "battery"}, wantIDs: []string{"clock", "battery"}},
+		{name: "duplicate is rejected", ids: []string{"clock", "clock"}, wantErr: true, wantIDs: []string{"clock"}},
+		{name: "empty id is rejected", ids: []string{""}, wantErr: true, wantIDs: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := renderer.NewRegistry()
+			var err error
+			for _, id := range tt.ids {
+				if regErr := r.Register(clockSegment{id: id}); regErr != nil {
+					err = regErr
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := r.IDs(); !slices.Equal(got, tt.wantIDs) {
+				t.Errorf("IDs() = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		wantOK bool
+	}{
+		{name: "registered segment", id: "clock", wantOK: true},
+		{name: "unknown segment", id: "battery", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := renderer.NewRegistry()
+			if err := r.Register(clockSegment{id: "clock"}); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			seg, ok := r.Lookup(tt.id)
+			if ok != tt.wantOK {
+				t.Errorf("Lookup(%q) ok = %v, want %v", tt.id, ok, tt.wantOK)
+			}
+			if ok && seg.ID() != tt.id {
+				t.Errorf("Lookup(%q).ID() = %q", tt.id, seg.ID())
+			}
+		})
+	}
+}
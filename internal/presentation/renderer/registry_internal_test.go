@@ -0,0 +1,28 @@
+package renderer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestBuiltinSegments(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "one segment per known identifier", want: model.KnownSegments()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, seg := range builtinSegments() {
+				got = append(got, seg.ID())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("builtinSegments() IDs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,105 @@
 // Package renderer provides status line rendering.
 package renderer
 
-import "github.com/florent/status-line/internal/domain/model"
+import (
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Segment priorities used to fit lines into the terminal width.
+// When a line is too wide, segments switch to their compact form and then
+// disappear from the lowest priority up; ties drop the rightmost first.
+const (
+	// PriorityLowest is for notifications that can be missed.
+	PriorityLowest int = 10
+	// PriorityLow is for secondary context.
+	PriorityLow int = 30
+	// PriorityNormal is the default priority.
+	PriorityNormal int = 50
+	// PriorityHigh is for working context.
+	PriorityHigh int = 70
+	// PriorityHighest is for segments kept as long as anything is shown.
+	PriorityHighest int = 90
+)
+
+// Segment is a unit of the status line that layouts refer to by ID.
+// Built-in segments register into the default registry; other packages
+// can add their own with Register.
+type Segment interface {
+	// ID returns the identifier used in layouts.
+	ID() string
+	// Enabled returns true if the segment has something to show.
+	Enabled(data model.StatusLineData) bool
+	// Priority returns the drop order on narrow terminals, higher is kept longer.
+	Priority() int
+	// Render returns the segment content and its edge colors.
+	// Segments render a shorter form when ctx.Compact is set.
+	Render(ctx *RenderContext) SegmentOutput
+}
+
+// RenderContext carries everything a segment needs to render.
+type RenderContext struct {
+	// Data is the status line data for this render.
+	Data model.StatusLineData
+	// Config is the active user configuration.
+	Config model.Config
+	// Theme resolves color roles to escape sequences.
+	Theme *Theme
+	// Icons is the active icon set.
+	Icons *IconSet
+	// Separators is the active separator set.
+	Separators *SeparatorSet
+	// Compact requests the short form used when the line is too wide.
+	Compact bool
+}
+
+// SegmentOutput is the rendered content of a segment.
+// Powerline segments report the colors at their edges so the line renderer
+// can draw caps and arrow separators from their neighbors. Pills draw their
+// own caps and are separated by spaces.
+type SegmentOutput struct {
+	// Text is the rendered content without separators.
+	Text string
+	// Bg is the background of the first character.
+	Bg string
+	// Fg is the foreground matching Bg, used for the left cap.
+	Fg string
+	// EndFg is the foreground matching the last background; empty means Fg.
+	EndFg string
+	// EndBg is the last background, used by left-facing separators; empty means Bg.
+	EndBg string
+	// Pill is true for self-contained pills.
+	Pill bool
+}
+
+// endFg returns the foreground used for the separator after the segment.
+//
+// Returns:
+//   - string: EndFg, or Fg when the segment has a single background
+func (o SegmentOutput) endFg() string {
+	// Fall back to the start color
+	if o.EndFg == "" {
+		// Return start foreground
+		return o.Fg
+	}
+	// Return explicit end foreground
+	return o.EndFg
+}
+
+// endBg returns the background used for the left-facing separator after the segment.
+//
+// Returns:
+//   - string: EndBg, or Bg when the segment has a single background
+func (o SegmentOutput) endBg() string {
+	// Fall back to the start color
+	if o.EndBg == "" {
+		// Return start background
+		return o.Bg
+	}
+	// Return explicit end background
+	return o.EndBg
+}
 
 // CursorProvider defines the interface for burn-rate cursor position.
 // It abstracts the cursor position calculation from concrete types.
@@ -10,14 +108,16 @@ type CursorProvider interface {
 	CursorPosition() int
 	// IsValid returns true if cursor data is available.
 	IsValid() bool
+	// Forecast projects usage to the end of its window.
+	Forecast(now time.Time) model.Forecast
 }
 
 // ModelSegmentData groups data needed to render the model segment.
-// It reduces the number of parameters for renderModelSegment.
+// It reduces the number of parameters for modelSegment.render.
 type ModelSegmentData struct {
 	Model    model.ModelInfo
 	ShowIcon bool
 	Progress model.Progress
 	Cursor   CursorProvider
-	NextBg   string
+	Stale    bool
 }
@@ -0,0 +1,980 @@
+// Package renderer provides status line rendering.
+package renderer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Compact form limits.
+const (
+	// compactPathLength is the maximum path length in compact form.
+	compactPathLength int = 15
+	// compactBranchLength is the maximum branch name length in compact form.
+	compactBranchLength int = 12
+	// compactActivityLength is the maximum tool call target length in compact form.
+	compactActivityLength int = 12
+)
+
+// Lines-changed labels naming where the counts come from.
+const (
+	// changesLabelSession labels the counts Claude Code reported.
+	changesLabelSession string = "session"
+	// changesLabelWorktree labels uncommitted changes.
+	changesLabelWorktree string = "tree"
+	// changesLabelBranch labels changes since the base branch.
+	changesLabelBranch string = "branch"
+)
+
+// Compile-time interface implementation checks.
+var (
+	_ Segment = osSegment{}
+	_ Segment = modelSegment{}
+	_ Segment = weeklySegment{}
+	_ Segment = sparklineSegment{}
+	_ Segment = pathSegment{}
+	_ Segment = gitSegment{}
+	_ Segment = changesSegment{}
+	_ Segment = costSegment{}
+	_ Segment = activitySegment{}
+)
+
+// osSegment shows the operating system icon.
+type osSegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (osSegment) ID() string {
+	// Return identifier
+	return model.SegmentOS
+}
+
+// Enabled returns true: the OS segment is always shown.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: always true
+func (osSegment) Enabled(model.StatusLineData) bool {
+	// Always visible
+	return true
+}
+
+// Priority returns PriorityLow: the OS icon is secondary context.
+//
+// Returns:
+//   - int: segment priority
+func (osSegment) Priority() int {
+	// Return priority
+	return PriorityLow
+}
+
+// Render renders the operating system segment.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: content with OS colors
+func (s osSegment) Render(ctx *RenderContext) SegmentOutput {
+	t := ctx.Theme
+	var sb strings.Builder
+	s.render(&sb, ctx, ctx.Data.System, ctx.Data.Icons.OS)
+	// Return content with OS colors
+	return SegmentOutput{Text: sb.String(), Bg: t.Bg(model.RoleOSBg), Fg: t.Fg(model.RoleOSBg)}
+}
+
+// render writes the operating system segment content.
+//
+// Params:
+//   - sb: string builder to write to
+//   - ctx: render context (theme and icon set)
+//   - sys: system information
+//   - showIcon: whether to show the OS icon
+func (osSegment) render(sb *strings.Builder, ctx *RenderContext, sys model.SystemInfo, showIcon bool) {
+	t := ctx.Theme
+	// Check if icon should be shown
+	if showIcon {
+		icon := ctx.Icons.OSIcon(sys.OS, sys.IsDocker)
+		// Write icon with background
+		sb.WriteString(t.Bg(model.RoleOSBg) + t.Fg(model.RoleOSFg) + Bold + " " + icon + " " + Reset)
+	} else {
+		// Write empty space without icon
+		sb.WriteString(t.Bg(model.RoleOSBg) + t.Fg(model.RoleOSFg) + Bold + "  " + Reset)
+	}
+}
+
+// modelSegment shows the AI model with session progress.
+type modelSegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (modelSegment) ID() string {
+	// Return identifier
+	return model.SegmentModel
+}
+
+// Enabled returns true: the model segment is always shown.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: always true
+func (modelSegment) Enabled(model.StatusLineData) bool {
+	// Always visible
+	return true
+}
+
+// Priority returns PriorityHighest: the model is kept as long as anything is shown.
+//
+// Returns:
+//   - int: segment priority
+func (modelSegment) Priority() int {
+	// Return priority
+	return PriorityHighest
+}
+
+// Render renders the model segment with its burn-rate cursor when usage data is available.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: content colored by model family
+func (s modelSegment) Render(ctx *RenderContext) SegmentOutput {
+	data, t := ctx.Data, ctx.Theme
+	// Build session cursor provider from API data (nil if no API data)
+	var sessionCursor CursorProvider
+	if data.Session.IsValid() {
+		session := data.Session
+		sessionCursor = &session
+	}
+
+	var sb strings.Builder
+	s.render(&sb, ctx, &ModelSegmentData{
+		Model:    data.Model,
+		ShowIcon: data.Icons.Model,
+		Progress: data.Progress,
+		Cursor:   sessionCursor,
+		Stale:    data.Session.IsValid() && data.Session.Stale,
+	}, ctx.Config.Progress)
+	bg, fg, _ := t.ModelColors(data.Model.Family())
+	// Return content with model colors
+	return SegmentOutput{Text: sb.String(), Bg: bg, Fg: fg}
+}
+
+// render writes the AI model segment with integrated progress bar.
+//
+// Params:
+//   - sb: string builder to write to
+//   - ctx: render context (theme and icon set)
+//   - data: model segment rendering data
+//   - bar: progress bar style and width
+func (modelSegment) render(sb *strings.Builder, ctx *RenderContext, data *ModelSegmentData, bar model.ProgressConfig) {
+	t := ctx.Theme
+	// Detect the family from the model ID, falling back to the display name
+	bgColor, _, textColor := t.ModelColors(data.Model.Family())
+
+	// Render progress bar (with cursor if usage data is valid)
+	var rendered string
+	// Select bar form
+	switch {
+	// Keep only the percentage in compact form
+	case ctx.Compact:
+		rendered = ""
+	// Check if we have valid cursor data
+	case data.Cursor != nil && data.Cursor.IsValid():
+		// Render with burn-rate cursor
+		rendered = RenderProgressBarWithCursor(data.Progress, ParseProgressBarStyle(bar.Style), data.Cursor.CursorPosition(), bar.Width, t.Fg(model.RoleCursor), bgColor+textColor+Bold) + " "
+	// No API data available
+	default:
+		// Render without cursor
+		rendered = RenderProgressBar(data.Progress, ParseProgressBarStyle(bar.Style), bar.Width) + " "
+	}
+
+	// Check if icon should be shown
+	if data.ShowIcon {
+		// Write model name with icon
+		sb.WriteString(bgColor + textColor + Bold + " " + ctx.Icons.Model + " " + data.Model.Name + " " + Reset)
+	} else {
+		// Write model name without icon
+		sb.WriteString(bgColor + textColor + Bold + " " + data.Model.Name + " " + Reset)
+	}
+
+	var forecast string
+	// Project the session limit when usage data is available
+	if data.Cursor != nil && data.Cursor.IsValid() {
+		forecast = forecastLabel(ctx, data.Cursor.Forecast(time.Now()), time.Now(), textColor)
+	}
+
+	// Write progress bar and percentage (using model's text color with bold for consistency)
+	sb.WriteString(bgColor + textColor + Bold + rendered + itoa(data.Progress.Percent) + "% " + forecast + staleMark(ctx, data.Stale) + Reset)
+}
+
+// staleMark returns the stale icon followed by a space for outdated usage data.
+//
+// Params:
+//   - ctx: render context (icon set)
+//   - stale: whether the data comes from an outdated cache
+//
+// Returns:
+//   - string: marker, empty for current data
+func staleMark(ctx *RenderContext, stale bool) string {
+	// Current data needs no marker
+	if !stale {
+		// Return nothing
+		return ""
+	}
+	// Return marker
+	return ctx.Icons.Stale + " "
+}
+
+// paceColor returns the foreground color for a usage pace.
+//
+// Params:
+//   - t: active theme
+//   - pace: usage pace
+//
+// Returns:
+//   - string: foreground escape sequence
+func paceColor(t *Theme, pace model.Pace) string {
+	// Select role by pace
+	switch pace {
+	// Slightly fast
+	case model.PaceAhead:
+		// Return ahead color
+		return t.Fg(model.RolePaceAhead)
+	// Much too fast
+	case model.PaceOver:
+		// Return over color
+		return t.Fg(model.RolePaceOver)
+	// Sustainable
+	default:
+		// Return on-track color
+		return t.Fg(model.RolePaceOnTrack)
+	}
+}
+
+// forecastLabel returns "limit in ~1h20" or "resets in 2h05" colored by pace
+// and followed by a space, when forecasts are enabled.
+//
+// Params:
+//   - ctx: render context (theme, forecast option, compact form)
+//   - forecast: usage forecast
+//   - now: current time
+//   - fg: segment text color restored after the label
+//
+// Returns:
+//   - string: label, empty when disabled, compact or without a reset time
+func forecastLabel(ctx *RenderContext, forecast model.Forecast, now time.Time, fg string) string {
+	// Forecasts are optional and too long for the compact form
+	if !ctx.Config.Progress.Forecast || ctx.Compact || forecast.ResetsAt.IsZero() {
+		// Return nothing
+		return ""
+	}
+	label := "resets in " + formatDuration(forecast.ResetsAt.Sub(now))
+	// Prefer the limit when it comes first
+	if !forecast.LimitAt.IsZero() && forecast.LimitAt.Before(forecast.ResetsAt) {
+		label = "limit in ~" + formatDuration(forecast.LimitAt.Sub(now))
+	}
+	// Return label in the pace color
+	return paceColor(ctx.Theme, forecast.Pace) + label + fg + " "
+}
+
+// formatDuration formats a duration to the minute for display, such as
+// "45m", "2h05" or "3d4h".
+//
+// Params:
+//   - d: duration, negative values count as zero
+//
+// Returns:
+//   - string: short duration
+func formatDuration(d time.Duration) string {
+	minutes := int(max(d, 0) / time.Minute)
+	hours, days := minutes/60, minutes/(24*60)
+	// Pick the two largest units
+	switch {
+	// Days and hours
+	case days > 0:
+		// Return days form
+		return itoa(days) + "d" + itoa(hours%24) + "h"
+	// Hours and minutes
+	case hours > 0:
+		// Return hours form with padded minutes
+		return fmt.Sprintf("%dh%02d", hours, minutes%60)
+	// Minutes only
+	default:
+		// Return minutes form
+		return itoa(minutes) + "m"
+	}
+}
+
+// modelWindow returns the weekly limit of the current model followed by a
+// space, such as "Opus 20% ", when the API reports one.
+//
+// Params:
+//   - ctx: render context (current model and usage windows)
+//
+// Returns:
+//   - string: model limit, empty when the model has no limit of its own
+func modelWindow(ctx *RenderContext) string {
+	window, ok := ctx.Data.UsageWindows.ForModel(ctx.Data.Model)
+	// Only the overall limit applies
+	if !ok {
+		// Return nothing
+		return ""
+	}
+	// Return model name and utilization
+	return ctx.Data.Model.Name + " " + itoa(window.Usage.Utilization) + "% "
+}
+
+// warningMark returns the warning icon followed by a space when the last
+// usage refresh failed.
+//
+// Params:
+//   - ctx: render context (icon set)
+//   - status: usage status of the last refresh
+//
+// Returns:
+//   - string: marker, empty after a successful refresh
+func warningMark(ctx *RenderContext, status model.UsageStatus) string {
+	// Successful refreshes need no marker
+	if status == "" {
+		// Return nothing
+		return ""
+	}
+	// Return marker
+	return ctx.Icons.Warning + " "
+}
+
+// weeklySegment shows the weekly API usage with burn-rate cursor.
+type weeklySegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (weeklySegment) ID() string {
+	// Return identifier
+	return model.SegmentWeekly
+}
+
+// Enabled returns true when weekly usage data is available or the API failed.
+// The segment is auto-hidden without API credentials.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: true if usage data is valid or a failure must be reported
+func (weeklySegment) Enabled(data model.StatusLineData) bool {
+	// Check for valid weekly usage or a failure status
+	return data.Usage.IsValid() || data.UsageStatus != ""
+}
+
+// Priority returns PriorityLow: weekly usage is secondary context.
+//
+// Returns:
+//   - int: segment priority
+func (weeklySegment) Priority() int {
+	// Return priority
+	return PriorityLow
+}
+
+// Render renders the weekly usage segment.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: content with weekly colors
+func (weeklySegment) Render(ctx *RenderContext) SegmentOutput {
+	usage, status, t := ctx.Data.Usage, ctx.Data.UsageStatus, ctx.Theme
+	// Without data, explain why instead
+	if !usage.IsValid() {
+		label := " " + status.Label()
+		// Keep only the marker in compact form
+		if ctx.Compact {
+			label = ""
+		}
+		text := t.Bg(model.RoleWeeklyBg) + t.Fg(model.RoleWeeklyFg) + Bold + " " + ctx.Icons.Weekly + " " + ctx.Icons.Warning + label + " " + Reset
+		// Return failure with weekly colors
+		return SegmentOutput{Text: text, Bg: t.Bg(model.RoleWeeklyBg), Fg: t.Fg(model.RoleWeeklyBg)}
+	}
+	progress, now := usage.Progress(), time.Now()
+	forecast := usage.Forecast(now)
+	var bar string
+	// Keep the burn-rate bar out of the compact form
+	if !ctx.Compact {
+		pace := paceColor(t, forecast.Pace)
+		bar = pace + RenderProgressBarWithCursor(progress, ParseProgressBarStyle(ctx.Config.Progress.Style), usage.CursorPosition(), ctx.Config.Progress.Width, t.Fg(model.RoleCursor), t.Bg(model.RoleWeeklyBg)+pace+Bold) + t.Fg(model.RoleWeeklyFg) + " "
+	}
+	text := t.Bg(model.RoleWeeklyBg) + t.Fg(model.RoleWeeklyFg) + Bold + " " + ctx.Icons.Weekly + " " + bar + itoa(progress.Percent) + "% " + forecastLabel(ctx, forecast, now, t.Fg(model.RoleWeeklyFg)) + modelWindow(ctx) + staleMark(ctx, usage.Stale) + warningMark(ctx, status) + Reset
+	// Return content with weekly colors
+	return SegmentOutput{Text: text, Bg: t.Bg(model.RoleWeeklyBg), Fg: t.Fg(model.RoleWeeklyBg)}
+}
+
+// sparklineSegment shows the recent session usage trend from the history ledger.
+type sparklineSegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (sparklineSegment) ID() string {
+	// Return identifier
+	return model.SegmentSparkline
+}
+
+// Enabled returns true when usage history is available.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: true if samples were recorded
+func (sparklineSegment) Enabled(data model.StatusLineData) bool {
+	// Check for samples
+	return len(data.History) > 0
+}
+
+// Priority returns PriorityLow: the trend is a nicety next to the usage bars.
+//
+// Returns:
+//   - int: segment priority
+func (sparklineSegment) Priority() int {
+	// Return priority
+	return PriorityLow
+}
+
+// Render renders the peak session usage of each period of the span as a sparkline.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: content with sparkline colors
+func (sparklineSegment) Render(ctx *RenderContext) SegmentOutput {
+	t, cfg := ctx.Theme, ctx.Config.History
+	width := cfg.SparklineWidth
+	// Halve the sparkline in compact form
+	if ctx.Compact {
+		width = max(width/2, 1)
+	}
+	trend := ctx.Data.History.Trend(time.Now(), cfg.SparklineSpan.Std(), width)
+	text := t.Bg(model.RoleSparklineBg) + t.Fg(model.RoleSparklineFg) + " " + sparkline(ctx.Icons.Spark, trend) + " " + Reset
+	// Return content with sparkline colors
+	return SegmentOutput{Text: text, Bg: t.Bg(model.RoleSparklineBg), Fg: t.Fg(model.RoleSparklineBg)}
+}
+
+// sparkline draws one level per value.
+//
+// Params:
+//   - levels: characters from lowest to highest
+//   - values: percentages (0-100), negative for gaps
+//
+// Returns:
+//   - string: sparkline with a space for each gap
+func sparkline(levels string, values []int) string {
+	chars := []rune(levels)
+	var sb strings.Builder
+	// Draw each value
+	for _, v := range values {
+		// Leave gaps blank
+		if v < 0 || len(chars) == 0 {
+			sb.WriteByte(' ')
+			continue
+		}
+		sb.WriteRune(chars[min(v, percentMax)*(len(chars)-1)/percentMax])
+	}
+	// Return sparkline
+	return sb.String()
+}
+
+// pathSegment shows the working directory.
+type pathSegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (pathSegment) ID() string {
+	// Return identifier
+	return model.SegmentPath
+}
+
+// Enabled returns true: the path segment is always shown.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: always true
+func (pathSegment) Enabled(model.StatusLineData) bool {
+	// Always visible
+	return true
+}
+
+// Priority returns PriorityHigh: the working directory is working context.
+//
+// Returns:
+//   - int: segment priority
+func (pathSegment) Priority() int {
+	// Return priority
+	return PriorityHigh
+}
+
+// Render renders the current directory segment.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: content with path colors
+func (pathSegment) Render(ctx *RenderContext) SegmentOutput {
+	t := ctx.Theme
+	maxLen := ctx.Config.Path.MaxLength
+	// Shorten further in compact form
+	if ctx.Compact {
+		maxLen = min(maxLen, compactPathLength)
+	}
+	truncated := TruncatePath(ctx.Data.Dir, maxLen)
+	var text string
+	// Check if icon should be shown
+	if ctx.Data.Icons.Path {
+		// Write path with folder icon
+		text = t.Bg(model.RolePathBg) + t.Fg(model.RolePathFg) + Bold + " " + ctx.Icons.Folder + " " + truncated + " " + Reset
+	} else {
+		// Write path without icon
+		text = t.Bg(model.RolePathBg) + t.Fg(model.RolePathFg) + Bold + " " + truncated + " " + Reset
+	}
+	// Return content with path colors
+	return SegmentOutput{Text: text, Bg: t.Bg(model.RolePathBg), Fg: t.Fg(model.RolePathBg)}
+}
+
+// gitSegment shows the git branch and working tree status.
+type gitSegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (gitSegment) ID() string {
+	// Return identifier
+	return model.SegmentGit
+}
+
+// Enabled returns true inside a git repository.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: true if a branch is known
+func (gitSegment) Enabled(data model.StatusLineData) bool {
+	// Check for repository
+	return data.Git.IsInRepo()
+}
+
+// Priority returns PriorityHigh: the branch is working context.
+//
+// Returns:
+//   - int: segment priority
+func (gitSegment) Priority() int {
+	// Return priority
+	return PriorityHigh
+}
+
+// Render renders the git branch and status segment.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: content with git colors
+func (s gitSegment) Render(ctx *RenderContext) SegmentOutput {
+	t := ctx.Theme
+	var sb strings.Builder
+	s.render(&sb, ctx, ctx.Data.Git, ctx.Data.Icons.Git)
+	// Return content with git colors
+	return SegmentOutput{Text: sb.String(), Bg: t.Bg(model.RoleGitBg), Fg: t.Fg(model.RoleGitBg)}
+}
+
+// render writes the git branch and status content.
+//
+// Params:
+//   - sb: string builder to write to
+//   - ctx: render context (theme and icon set)
+//   - git: git status information
+//   - showIcon: whether to show the git branch icon
+func (gitSegment) render(sb *strings.Builder, ctx *RenderContext, git model.GitStatus, showIcon bool) {
+	t := ctx.Theme
+	// Shorten long branch names in compact form
+	if ctx.Compact {
+		git.Branch = truncateText(git.Branch, compactBranchLength)
+	}
+	// Check if icon should be shown
+	if showIcon {
+		// Write branch with icon
+		sb.WriteString(t.Bg(model.RoleGitBg) + t.Fg(model.RoleGitFg) + Bold + " " + ctx.Icons.GitBranch + " " + git.Branch)
+	} else {
+		// Write branch without icon
+		sb.WriteString(t.Bg(model.RoleGitBg) + t.Fg(model.RoleGitFg) + Bold + " " + git.Branch)
+	}
+
+	// Add modified indicator if present
+	if git.Modified > 0 {
+		sb.WriteString(" !" + itoa(git.Modified))
+	}
+	// Add untracked indicator if present
+	if git.Untracked > 0 {
+		sb.WriteString(" ?" + itoa(git.Untracked))
+	}
+
+	// Write segment end
+	sb.WriteString(" " + Reset)
+}
+
+// changesSegment shows the lines added and removed.
+type changesSegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (changesSegment) ID() string {
+	// Return identifier
+	return model.SegmentChanges
+}
+
+// Enabled returns true when lines were added or removed.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: true if there are changes from any source
+func (changesSegment) Enabled(data model.StatusLineData) bool {
+	// Check for changes
+	return data.Changes.HasChanges() || data.SessionChanges.HasChanges()
+}
+
+// Priority returns PriorityNormal: line changes use the default priority.
+//
+// Returns:
+//   - int: segment priority
+func (changesSegment) Priority() int {
+	// Return priority
+	return PriorityNormal
+}
+
+// changesGroup is a set of line counts with the label of its source.
+type changesGroup struct {
+	label   string
+	changes model.CodeChanges
+}
+
+// Render renders the lines added/removed of each source as powerline segments.
+// Each group starts with the added color and ends with the removed color when both parts are shown.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: labeled added and/or removed content
+func (s changesSegment) Render(ctx *RenderContext) SegmentOutput {
+	t := ctx.Theme
+	var sb strings.Builder
+	var out, prev SegmentOutput
+	// Render each source, joined by separators
+	for idx, group := range s.groups(ctx) {
+		edges := changesEdges(t, group.changes)
+		// Start with the first group's colors
+		if idx == 0 {
+			out = edges
+		} else {
+			// Write separator from the previous group
+			sb.WriteString(edges.Bg + prev.endFg() + ctx.Separators.Right + Reset)
+		}
+		s.render(&sb, ctx, group)
+		out.EndFg, out.EndBg = edges.EndFg, edges.EndBg
+		prev = edges
+	}
+	out.Text = sb.String()
+	// Return content with edge colors
+	return out
+}
+
+// groups returns the sources to show, session counts first.
+//
+// Params:
+//   - ctx: render context (changes and configured source)
+//
+// Returns:
+//   - []changesGroup: sources with changes
+func (changesSegment) groups(ctx *RenderContext) []changesGroup {
+	var groups []changesGroup
+	// Add the counts Claude Code reported
+	if ctx.Data.SessionChanges.HasChanges() {
+		groups = append(groups, changesGroup{label: changesLabelSession, changes: ctx.Data.SessionChanges})
+	}
+	// Add the git counts
+	if ctx.Data.Changes.HasChanges() {
+		label := changesLabelWorktree
+		// Name the branch source
+		if ctx.Config.Changes.Source == model.ChangesSourceBranch {
+			label = changesLabelBranch
+		}
+		groups = append(groups, changesGroup{label: label, changes: ctx.Data.Changes})
+	}
+	// Return groups
+	return groups
+}
+
+// render writes one labeled group of added and removed parts.
+// The label goes in the first part; compact form keeps its first letter.
+//
+// Params:
+//   - sb: string builder to write to
+//   - ctx: render context (theme and separators)
+//   - group: counts and label
+func (changesSegment) render(sb *strings.Builder, ctx *RenderContext, group changesGroup) {
+	changes, t := group.changes, ctx.Theme
+	label := group.label
+	// Shorten the label in compact form
+	if ctx.Compact {
+		label = label[:1]
+	}
+
+	// Render added part if any
+	if changes.HasAdded() {
+		// Write added part
+		sb.WriteString(t.Bg(model.RoleChangesAddedBg) + t.Fg(model.RoleChangesAddedFg) + Bold + " " + label + " +" + itoa(changes.Added) + " " + Reset)
+		label = ""
+
+		// Write separator to the removed part if present
+		if changes.HasRemoved() {
+			sb.WriteString(t.Bg(model.RoleChangesRemovedBg) + t.Fg(model.RoleChangesAddedBg) + ctx.Separators.Right + Reset)
+		}
+	}
+
+	// Render removed part if any
+	if changes.HasRemoved() {
+		// Label the removed part when nothing was added
+		if label != "" {
+			label = " " + label
+		}
+		// Write removed part
+		sb.WriteString(t.Bg(model.RoleChangesRemovedBg) + t.Fg(model.RoleChangesRemovedFg) + Bold + label + " -" + itoa(changes.Removed) + " " + Reset)
+	}
+}
+
+// changesEdges returns edge colors for the changes segment.
+//
+// Params:
+//   - t: active theme
+//   - changes: code changes information
+//
+// Returns:
+//   - SegmentOutput: added and/or removed edge colors without text
+func changesEdges(t *Theme, changes model.CodeChanges) SegmentOutput {
+	out := SegmentOutput{
+		Bg:    t.Bg(model.RoleChangesRemovedBg),
+		Fg:    t.Fg(model.RoleChangesRemovedBg),
+		EndFg: t.Fg(model.RoleChangesRemovedBg),
+		EndBg: t.Bg(model.RoleChangesRemovedBg),
+	}
+	// Start with the added color when lines were added
+	if changes.HasAdded() {
+		out.Bg = t.Bg(model.RoleChangesAddedBg)
+		out.Fg = t.Fg(model.RoleChangesAddedBg)
+	}
+	// End with the added color when no lines were removed
+	if !changes.HasRemoved() {
+		out.EndFg = t.Fg(model.RoleChangesAddedBg)
+		out.EndBg = t.Bg(model.RoleChangesAddedBg)
+	}
+	// Return computed edges
+	return out
+}
+
+// costSegment shows the session cost and how long the session has run.
+type costSegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (costSegment) ID() string {
+	// Return identifier
+	return model.SegmentCost
+}
+
+// Enabled returns true once the session has a cost or a duration.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: true after the first API call
+func (costSegment) Enabled(data model.StatusLineData) bool {
+	// Check for cost data
+	return !data.Cost.IsZero()
+}
+
+// Priority returns PriorityNormal: spend uses the default priority.
+//
+// Returns:
+//   - int: segment priority
+func (costSegment) Priority() int {
+	// Return priority
+	return PriorityNormal
+}
+
+// Render renders the amount spent, then the wall-clock and API time.
+// The colors change when the amount reaches the warning or critical threshold.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: content with the colors of the cost level
+func (costSegment) Render(ctx *RenderContext) SegmentOutput {
+	cost, cfg, t := ctx.Data.Cost, ctx.Config.Cost, ctx.Theme
+	bgRole, fgRole := costRoles(cost.Level(cfg))
+	text := t.Bg(bgRole) + t.Fg(fgRole) + Bold + " " + formatCost(cfg, cost.USD)
+	// Keep only the amount in compact form
+	if !ctx.Compact && cost.Duration > 0 {
+		text += " " + formatDuration(cost.Duration.Std())
+		// Add API time when known
+		if cost.APIDuration > 0 {
+			text += " (api " + formatDuration(cost.APIDuration.Std()) + ")"
+		}
+	}
+	text += " " + Reset
+	// Return content with level colors
+	return SegmentOutput{Text: text, Bg: t.Bg(bgRole), Fg: t.Fg(bgRole)}
+}
+
+// costRoles returns the color roles of a cost level.
+//
+// Params:
+//   - level: cost level
+//
+// Returns:
+//   - bgRole: background role
+//   - fgRole: text role
+func costRoles(level model.CostLevel) (bgRole, fgRole string) {
+	// Select roles by level
+	switch level {
+	// Over the critical threshold
+	case model.CostCritical:
+		// Return critical roles
+		return model.RoleCostCriticalBg, model.RoleCostCriticalFg
+	// Over the warning threshold
+	case model.CostWarning:
+		// Return warning roles
+		return model.RoleCostWarningBg, model.RoleCostWarningFg
+	// Below both thresholds
+	default:
+		// Return normal roles
+		return model.RoleCostBg, model.RoleCostFg
+	}
+}
+
+// formatCost formats a US dollar amount in the configured currency, such as
+// "$1.25" or "1,35€".
+//
+// Params:
+//   - cfg: cost options (symbol and its position, rate, decimals, decimal mark)
+//   - usd: amount in US dollars
+//
+// Returns:
+//   - string: formatted amount
+func formatCost(cfg model.CostConfig, usd float64) string {
+	amount := strconv.FormatFloat(cfg.Convert(usd), 'f', cfg.Decimals, 64)
+	amount = strings.Replace(amount, ".", cfg.DecimalMark, 1)
+	// Place the symbol after the amount when configured
+	if cfg.SymbolAfter {
+		// Return amount then symbol
+		return amount + cfg.Symbol
+	}
+	// Return symbol then amount
+	return cfg.Symbol + amount
+}
+
+// activitySegment shows the last tool Claude called and the running sub-agents.
+type activitySegment struct{}
+
+// ID returns the segment identifier.
+//
+// Returns:
+//   - string: segment identifier
+func (activitySegment) ID() string {
+	// Return identifier
+	return model.SegmentActivity
+}
+
+// Enabled returns true once the transcript shows a tool call.
+//
+// Params:
+//   - data: status line data
+//
+// Returns:
+//   - bool: true with a tool call or a running sub-agent
+func (activitySegment) Enabled(data model.StatusLineData) bool {
+	// Check for activity
+	return !data.Transcript.Activity.IsZero()
+}
+
+// Priority returns PriorityLow: the activity changes at every call and goes first.
+//
+// Returns:
+//   - int: segment priority
+func (activitySegment) Priority() int {
+	// Return priority
+	return PriorityLow
+}
+
+// Render renders the tool name and its shortened target, then the number of
+// running sub-agents.
+//
+// Params:
+//   - ctx: render context
+//
+// Returns:
+//   - SegmentOutput: content with activity colors
+func (activitySegment) Render(ctx *RenderContext) SegmentOutput {
+	activity, t := ctx.Data.Transcript.Activity, ctx.Theme
+	maxLen := ctx.Config.Activity.MaxLength
+	// Shorten further in compact form
+	if ctx.Compact {
+		maxLen = min(maxLen, compactActivityLength)
+	}
+	var parts []string
+	// Name the tool with its target when known
+	if activity.Tool != "" {
+		parts = append(parts, activity.ToolName())
+		// Add the target when the tool has one
+		if target := activity.ShortTarget(maxLen); target != "" {
+			parts = append(parts, target)
+		}
+	}
+	// Count sub-agents still running
+	if activity.Agents > 0 {
+		parts = append(parts, ctx.Icons.Agents+" "+itoa(activity.Agents))
+	}
+	text := t.Bg(model.RoleActivityBg) + t.Fg(model.RoleActivityFg) + Bold + " " + strings.Join(parts, " ") + " " + Reset
+	// Return content with activity colors
+	return SegmentOutput{Text: text, Bg: t.Bg(model.RoleActivityBg), Fg: t.Fg(model.RoleActivityBg)}
+}
@@ -0,0 +1,585 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+func TestOSSegment_Render(t *testing.T) {
+	tests := []struct {
+		name     string
+		showIcon bool
+	}{
+		{name: "with icon", showIcon: true},
+		{name: "without icon", showIcon: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := DefaultTheme()
+			ctx := &RenderContext{Theme: th, Icons: NerdIcons(), Separators: PowerlineSeparators(), Data: model.StatusLineData{
+				System: model.SystemInfo{OS: model.OSLinux},
+				Icons:  model.IconConfig{OS: tt.showIcon},
+			}}
+			got := osSegment{}.Render(ctx)
+			if got.Text == "" {
+				t.Error("Render() produced empty output")
+			}
+			if got.Bg != th.Bg(model.RoleOSBg) || got.Fg != th.Fg(model.RoleOSBg) || got.Pill {
+				t.Errorf("Render() edges = %+v, want OS powerline segment", got)
+			}
+		})
+	}
+}
+
+func TestModelSegment_Render(t *testing.T) {
+	tests := []struct {
+		name     string
+		session  model.Usage
+		style    string
+		wantFill string
+	}{
+		{name: "without usage data", wantFill: "━"},
+		{name: "with session cursor", session: model.NewSessionUsage(40, time.Now().Add(time.Hour)), wantFill: "━"},
+		{name: "block style with session cursor", session: model.NewSessionUsage(40, time.Now().Add(time.Hour)), style: model.ProgressStyleBlock, wantFill: "█"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			if tt.style != "" {
+				cfg.Progress.Style = tt.style
+			}
+			ctx := &RenderContext{
+				Theme:      DefaultTheme(),
+				Icons:      NerdIcons(),
+				Separators: PowerlineSeparators(),
+				Data: model.StatusLineData{
+					Model:    model.ModelInfo{Name: "Opus"},
+					Icons:    model.IconConfig{Model: true},
+					Progress: model.Progress{Percent: 50},
+					Session:  tt.session,
+				},
+				Config: cfg,
+			}
+			got := modelSegment{}.Render(ctx)
+			if !strings.Contains(got.Text, "Opus") || !strings.Contains(got.Text, "50%") {
+				t.Errorf("Render() = %q, want model name and percent", got.Text)
+			}
+			if !strings.Contains(got.Text, tt.wantFill) {
+				t.Errorf("Render() = %q, want bar filled with %q", got.Text, tt.wantFill)
+			}
+			bg, fg, _ := DefaultTheme().ModelColors("Opus")
+			if got.Bg != bg || got.Fg != fg {
+				t.Errorf("Render() edges = %q/%q, want %q/%q", got.Bg, got.Fg, bg, fg)
+			}
+		})
+	}
+}
+
+func TestWeeklySegment_Enabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		usage  model.Usage
+		status model.UsageStatus
+		want   bool
+	}{
+		{name: "no usage data", usage: model.Usage{}, want: false},
+		{name: "with usage data", usage: model.NewWeeklyUsage(30, time.Now().Add(time.Hour)), want: true},
+		{name: "failed without data", usage: model.Usage{}, status: model.UsageAuthExpired, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (weeklySegment{}).Enabled(model.StatusLineData{Usage: tt.usage, UsageStatus: tt.status}); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeeklySegment_Render_Stale(t *testing.T) {
+	stale := model.NewWeeklyUsage(30, time.Now().Add(time.Hour))
+	stale.Stale = true
+	tests := []struct {
+		name      string
+		usage     model.Usage
+		wantStale bool
+	}{
+		{name: "current data", usage: model.NewWeeklyUsage(30, time.Now().Add(time.Hour)), wantStale: false},
+		{name: "stale data", usage: stale, wantStale: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &RenderContext{
+				Theme:      DefaultTheme(),
+				Icons:      ASCIIIcons(),
+				Separators: PowerlineSeparators(),
+				Data:       model.StatusLineData{Usage: tt.usage},
+				Config:     model.DefaultConfig(),
+			}
+			got := weeklySegment{}.Render(ctx)
+			if strings.Contains(got.Text, "(old)") != tt.wantStale {
+				t.Errorf("Render() = %q, want stale marker %v", got.Text, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestWeeklySegment_Render_Status(t *testing.T) {
+	tests := []struct {
+		name    string
+		usage   model.Usage
+		status  model.UsageStatus
+		compact bool
+		want    string
+		absent  string
+	}{
+		{name: "ok", usage: model.NewWeeklyUsage(30, time.Now().Add(time.Hour)), want: "30%", absent: "(!)"},
+		{name: "failed with cached data", usage: model.NewWeeklyUsage(30, time.Now().Add(time.Hour)), status: model.UsageUnavailable, want: "30% (!)", absent: "unavailable"},
+		{name: "auth expired", status: model.UsageAuthExpired, want: "(!) auth expired"},
+		{name: "rate limited compact", status: model.UsageRateLimited, compact: true, want: "(!)", absent: "rate limited"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &RenderContext{
+				Theme:      DefaultTheme(),
+				Icons:      ASCIIIcons(),
+				Separators: PowerlineSeparators(),
+				Data:       model.StatusLineData{Usage: tt.usage, UsageStatus: tt.status},
+				Config:     model.DefaultConfig(),
+				Compact:    tt.compact,
+			}
+			got := weeklySegment{}.Render(ctx)
+			if !strings.Contains(got.Text, tt.want) {
+				t.Errorf("Render() = %q, want %q", got.Text, tt.want)
+			}
+			if tt.absent != "" && strings.Contains(got.Text, tt.absent) {
+				t.Errorf("Render() = %q, want no %q", got.Text, tt.absent)
+			}
+		})
+	}
+}
+
+func TestWeeklySegment_Render_ModelWindow(t *testing.T) {
+	windows := model.UsageWindows{{Name: "seven_day_opus", Usage: model.NewWeeklyUsage(20, time.Now().Add(time.Hour))}}
+	tests := []struct {
+		name   string
+		model  string
+		want   string
+		absent string
+	}{
+		{name: "model with own limit", model: "Opus", want: "30% Opus 20%"},
+		{name: "model without own limit", model: "Sonnet", want: "30%", absent: "20%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &RenderContext{
+				Theme:      DefaultTheme(),
+				Icons:      ASCIIIcons(),
+				Separators: PowerlineSeparators(),
+				Data: model.StatusLineData{
+					Model:        model.ModelInfo{Name: tt.model},
+					Usage:        model.NewWeeklyUsage(30, time.Now().Add(time.Hour)),
+					UsageWindows: windows,
+				},
+				Config: model.DefaultConfig(),
+			}
+			got := weeklySegment{}.Render(ctx)
+			if !strings.Contains(got.Text, tt.want) {
+				t.Errorf("Render() = %q, want %q", got.Text, tt.want)
+			}
+			if tt.absent != "" && strings.Contains(got.Text, tt.absent) {
+				t.Errorf("Render() = %q, want no %q", got.Text, tt.absent)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		d    time.Duration
+		want string
+	}{
+		{name: "minutes", d: 45*time.Minute + 30*time.Second, want: "45m"},
+		{name: "hours", d: 2*time.Hour + 5*time.Minute, want: "2h05"},
+		{name: "days", d: 76 * time.Hour, want: "3d4h"},
+		{name: "negative", d: -time.Minute, want: "0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDuration(tt.d); got != tt.want {
+				t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeeklySegment_Render_Forecast(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		usage    model.Usage
+		enabled  bool
+		compact  bool
+		style    string
+		want     string
+		wantPace string
+	}{
+		{name: "disabled", usage: model.NewSessionUsage(30, now.Add(3*time.Hour)), want: "30% ", wantPace: model.RolePaceOnTrack},
+		{name: "on track", usage: model.NewSessionUsage(30, now.Add(3*time.Hour)), enabled: true, want: "resets in 2h59", wantPace: model.RolePaceOnTrack},
+		{name: "ahead", usage: model.NewSessionUsage(45, now.Add(3*time.Hour)), enabled: true, want: "limit in ~2h26", wantPace: model.RolePaceAhead},
+		{name: "over", usage: model.NewSessionUsage(80, now.Add(3*time.Hour)), enabled: true, want: "limit in ~30m", wantPace: model.RolePaceOver},
+		{name: "compact", usage: model.NewSessionUsage(80, now.Add(3*time.Hour)), enabled: true, compact: true, want: "80% "},
+		{name: "braille style", usage: model.NewSessionUsage(30, now.Add(3*time.Hour)), style: model.ProgressStyleBraille, want: "⣿"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Progress.Forecast = tt.enabled
+			if tt.style != "" {
+				cfg.Progress.Style = tt.style
+			}
+			ctx := &RenderContext{
+				Theme:      DefaultTheme(),
+				Icons:      ASCIIIcons(),
+				Separators: PowerlineSeparators(),
+				Data:       model.StatusLineData{Usage: tt.usage},
+				Config:     cfg,
+				Compact:    tt.compact,
+			}
+			got := weeklySegment{}.Render(ctx)
+			if !strings.Contains(got.Text, tt.want) {
+				t.Errorf("Render() = %q, want %q", got.Text, tt.want)
+			}
+			if !tt.enabled && strings.Contains(got.Text, " in ") {
+				t.Errorf("Render() = %q, want no forecast", got.Text)
+			}
+			if tt.wantPace != "" && !strings.Contains(got.Text, ctx.Theme.Fg(tt.wantPace)) {
+				t.Errorf("Render() = %q, want %s color", got.Text, tt.wantPace)
+			}
+		})
+	}
+}
+
+func TestForecastLabel(t *testing.T) {
+	now := time.Now()
+	resets := now.Add(2 * time.Hour)
+	tests := []struct {
+		name     string
+		forecast model.Forecast
+		want     string
+	}{
+		{name: "no limit", forecast: model.Forecast{Pace: model.PaceOnTrack, ResetsAt: resets}, want: "resets in 2h00"},
+		{name: "limit first", forecast: model.Forecast{Pace: model.PaceOver, ResetsAt: resets, LimitAt: now.Add(30 * time.Minute)}, want: "limit in ~30m"},
+		{name: "reset first", forecast: model.Forecast{Pace: model.PaceAhead, ResetsAt: resets, LimitAt: now.Add(3 * time.Hour)}, want: "resets in 2h00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Progress.Forecast = true
+			ctx := &RenderContext{Theme: DefaultTheme(), Config: cfg}
+			if got := forecastLabel(ctx, tt.forecast, now, ""); !strings.Contains(got, tt.want) {
+				t.Errorf("forecastLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels string
+		values []int
+		want   string
+	}{
+		{name: "levels", levels: "▁▂▃▄▅▆▇█", values: []int{0, 50, 100, 150}, want: "▁▄██"},
+		{name: "gaps", levels: "_.,-=+*#", values: []int{-1, 30, -1}, want: " , "},
+		{name: "empty", levels: "_#", values: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.levels, tt.values); got != tt.want {
+				t.Errorf("sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSparklineSegment_Render(t *testing.T) {
+	now := time.Now()
+	history := model.UsageHistory{
+		{Time: now.Add(-23 * time.Hour), Session: 0},
+		{Time: now.Add(-time.Minute), Session: 100},
+	}
+	tests := []struct {
+		name    string
+		compact bool
+		want    string
+	}{
+		{name: "full width", want: " _          # "},
+		{name: "compact", compact: true, want: " _    # "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &RenderContext{
+				Theme:      DefaultTheme(),
+				Icons:      ASCIIIcons(),
+				Separators: PowerlineSeparators(),
+				Data:       model.StatusLineData{History: history},
+				Config:     model.DefaultConfig(),
+				Compact:    tt.compact,
+			}
+			if !(sparklineSegment{}).Enabled(ctx.Data) {
+				t.Fatal("Enabled() = false with history")
+			}
+			got := sparklineSegment{}.Render(ctx)
+			if !strings.Contains(got.Text, tt.want) {
+				t.Errorf("Render() = %q, want %q", got.Text, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathSegment_Render(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  string
+	}{
+		{name: "renders path segment", dir: "/workspace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &RenderContext{
+				Theme:      DefaultTheme(),
+				Icons:      NerdIcons(),
+				Separators: PowerlineSeparators(),
+				Data:       model.StatusLineData{Dir: tt.dir, Icons: model.IconConfig{Path: true}},
+				Config:     model.DefaultConfig(),
+			}
+			got := pathSegment{}.Render(ctx)
+			if !strings.Contains(got.Text, tt.dir) {
+				t.Errorf("Render() = %q, want to contain %q", got.Text, tt.dir)
+			}
+		})
+	}
+}
+
+func TestGitSegment(t *testing.T) {
+	tests := []struct {
+		name        string
+		git         model.GitStatus
+		wantEnabled bool
+		wantText    string
+	}{
+		{name: "with branch", git: model.GitStatus{Branch: "main", Modified: 2, Untracked: 1}, wantEnabled: true, wantText: "main !2 ?1"},
+		{name: "not in repo", git: model.GitStatus{}, wantEnabled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := model.StatusLineData{Git: tt.git}
+			if got := (gitSegment{}).Enabled(data); got != tt.wantEnabled {
+				t.Errorf("Enabled() = %v, want %v", got, tt.wantEnabled)
+			}
+			if !tt.wantEnabled {
+				return
+			}
+			if got := (gitSegment{}).Render(&RenderContext{Data: data, Theme: DefaultTheme(), Icons: NerdIcons(), Separators: PowerlineSeparators()}); !strings.Contains(got.Text, tt.wantText) {
+				t.Errorf("Render() = %q, want to contain %q", got.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestChangesSegment(t *testing.T) {
+	tests := []struct {
+		name        string
+		changes     model.CodeChanges
+		wantEnabled bool
+	}{
+		{name: "with changes", changes: model.CodeChanges{Added: 10, Removed: 5}, wantEnabled: true},
+		{name: "no changes", changes: model.CodeChanges{}, wantEnabled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := model.StatusLineData{Changes: tt.changes}
+			if got := (changesSegment{}).Enabled(data); got != tt.wantEnabled {
+				t.Errorf("Enabled() = %v, want %v", got, tt.wantEnabled)
+			}
+			if !tt.wantEnabled {
+				return
+			}
+			got := changesSegment{}.Render(&RenderContext{Data: data, Config: model.DefaultConfig(), Theme: DefaultTheme(), Icons: NerdIcons(), Separators: PowerlineSeparators()})
+			if !strings.Contains(got.Text, "+10") || !strings.Contains(got.Text, "-5") {
+				t.Errorf("Render() = %q, want added and removed counts", got.Text)
+			}
+		})
+	}
+}
+
+func TestChangesSegment_Render_Sources(t *testing.T) {
+	th := DefaultTheme()
+	tests := []struct {
+		name      string
+		source    string
+		git       model.CodeChanges
+		session   model.CodeChanges
+		compact   bool
+		want      []string
+		wantStart string
+		wantEnd   string
+	}{
+		{name: "worktree", source: model.ChangesSourceWorktree, git: model.CodeChanges{Added: 10, Removed: 5}, want: []string{" tree +10 ", " -5 "}, wantStart: th.Bg(model.RoleChangesAddedBg), wantEnd: th.Fg(model.RoleChangesRemovedBg)},
+		{name: "branch", source: model.ChangesSourceBranch, git: model.CodeChanges{Added: 40}, want: []string{" branch +40 "}, wantStart: th.Bg(model.RoleChangesAddedBg), wantEnd: th.Fg(model.RoleChangesAddedBg)},
+		{name: "session removed only", source: model.ChangesSourceSession, session: model.CodeChanges{Removed: 2}, want: []string{" session -2 "}, wantStart: th.Bg(model.RoleChangesRemovedBg), wantEnd: th.Fg(model.RoleChangesRemovedBg)},
+		{name: "combined", source: model.ChangesSourceCombined, session: model.CodeChanges{Added: 3, Removed: 1}, git: model.CodeChanges{Added: 10}, want: []string{" session +3 ", " -1 ", " tree +10 "}, wantStart: th.Bg(model.RoleChangesAddedBg), wantEnd: th.Fg(model.RoleChangesAddedBg)},
+		{name: "compact", source: model.ChangesSourceCombined, session: model.CodeChanges{Added: 3}, git: model.CodeChanges{Removed: 4}, compact: true, want: []string{" s +3 ", " t -4 "}, wantStart: th.Bg(model.RoleChangesAddedBg), wantEnd: th.Fg(model.RoleChangesRemovedBg)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Changes.Source = tt.source
+			ctx := &RenderContext{
+				Data:       model.StatusLineData{Changes: tt.git, SessionChanges: tt.session},
+				Config:     cfg,
+				Theme:      th,
+				Icons:      NerdIcons(),
+				Separators: PowerlineSeparators(),
+				Compact:    tt.compact,
+			}
+			if !(changesSegment{}).Enabled(ctx.Data) {
+				t.Fatal("Enabled() = false with changes")
+			}
+			got := changesSegment{}.Render(ctx)
+			last := 0
+			for _, want := range tt.want {
+				idx := strings.Index(got.Text[last:], want)
+				if idx < 0 {
+					t.Fatalf("Render() = %q, want %q in order", got.Text, tt.want)
+				}
+				last += idx + len(want)
+			}
+			if got.Bg != tt.wantStart || got.endFg() != tt.wantEnd {
+				t.Errorf("Render() edges = %q, %q, want %q, %q", got.Bg, got.endFg(), tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestChangesEdges(t *testing.T) {
+	th := DefaultTheme()
+	tests := []struct {
+		name      string
+		changes   model.CodeChanges
+		wantStart string
+		wantEnd   string
+	}{
+		{name: "added only", changes: model.CodeChanges{Added: 1}, wantStart: th.Bg(model.RoleChangesAddedBg), wantEnd: th.Fg(model.RoleChangesAddedBg)},
+		{name: "removed only", changes: model.CodeChanges{Removed: 1}, wantStart: th.Bg(model.RoleChangesRemovedBg), wantEnd: th.Fg(model.RoleChangesRemovedBg)},
+		{name: "both", changes: model.CodeChanges{Added: 1, Removed: 1}, wantStart: th.Bg(model.RoleChangesAddedBg), wantEnd: th.Fg(model.RoleChangesRemovedBg)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := changesEdges(th, tt.changes)
+			if got.Bg != tt.wantStart || got.endFg() != tt.wantEnd {
+				t.Errorf("changesEdges() = %+v, want start %q end %q", got, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestFormatCost(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  model.CostConfig
+		usd  float64
+		want string
+	}{
+		{name: "default", cfg: model.DefaultConfig().Cost, usd: 1.254, want: "$1.25"},
+		{name: "symbol after", cfg: model.CostConfig{Symbol: "€", SymbolAfter: true, Rate: 0.9, Decimals: 2, DecimalMark: ","}, usd: 1.5, want: "1,35€"},
+		{name: "no decimals", cfg: model.CostConfig{Symbol: "¥", Rate: 150, Decimals: 0}, usd: 2, want: "¥300"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatCost(tt.cfg, tt.usd); got != tt.want {
+				t.Errorf("formatCost(%v) = %q, want %q", tt.usd, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCostSegment_Render(t *testing.T) {
+	cost := model.SessionCost{USD: 6.5, Duration: model.Duration(75 * time.Minute), APIDuration: model.Duration(20 * time.Minute)}
+	tests := []struct {
+		name     string
+		warning  float64
+		critical float64
+		compact  bool
+		want     string
+		wantBg   string
+	}{
+		{name: "normal", want: " $6.50 1h15 (api 20m) ", wantBg: model.RoleCostBg},
+		{name: "warning", warning: 5, critical: 10, want: " $6.50 1h15 (api 20m) ", wantBg: model.RoleCostWarningBg},
+		{name: "critical", warning: 2, critical: 5, want: " $6.50 1h15 (api 20m) ", wantBg: model.RoleCostCriticalBg},
+		{name: "compact", compact: true, want: " $6.50 ", wantBg: model.RoleCostBg},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Cost.Warning, cfg.Cost.Critical = tt.warning, tt.critical
+			ctx := &RenderContext{
+				Theme:      DefaultTheme(),
+				Icons:      ASCIIIcons(),
+				Separators: PowerlineSeparators(),
+				Data:       model.StatusLineData{Cost: cost},
+				Config:     cfg,
+				Compact:    tt.compact,
+			}
+			if !(costSegment{}).Enabled(ctx.Data) {
+				t.Fatal("Enabled() = false with a cost")
+			}
+			got := costSegment{}.Render(ctx)
+			if !strings.Contains(got.Text, tt.want) {
+				t.Errorf("Render() = %q, want %q", got.Text, tt.want)
+			}
+			if want := ctx.Theme.Bg(tt.wantBg); got.Bg != want {
+				t.Errorf("Render().Bg = %q, want %q", got.Bg, want)
+			}
+		})
+	}
+}
+
+func TestActivitySegment_Render(t *testing.T) {
+	edit := model.Activity{Tool: "Edit", Target: "/repo/internal/presentation/renderer/segments_internal.go", Kind: model.ActivityTargetPath}
+	tests := []struct {
+		name     string
+		activity model.Activity
+		compact  bool
+		want     string
+	}{
+		{name: "tool and target", activity: edit, want: " Edit segments_internal.go "},
+		{name: "compact target", activity: edit, compact: true, want: " Edit …internal.go "},
+		{name: "with sub-agents", activity: model.Activity{Tool: "Task", Target: "Explore", Agents: 2}, want: " Task Explore [agents] 2 "},
+		{name: "sub-agents only", activity: model.Activity{Agents: 1}, want: " [agents] 1 "},
+		{name: "tool without target", activity: model.Activity{Tool: "mcp__github__get_issue"}, want: " github:get_issue "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &RenderContext{
+				Theme:      DefaultTheme(),
+				Icons:      ASCIIIcons(),
+				Separators: PowerlineSeparators(),
+				Data:       model.StatusLineData{Transcript: model.TranscriptStats{Activity: tt.activity}},
+				Config:     model.DefaultConfig(),
+				Compact:    tt.compact,
+			}
+			if !(activitySegment{}).Enabled(ctx.Data) {
+				t.Fatal("Enabled() = false with activity")
+			}
+			if got := (activitySegment{}).Render(ctx); !strings.Contains(got.Text, tt.want) {
+				t.Errorf("Render() = %q, want %q", got.Text, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Package renderer provides status line rendering.
+package renderer
+
+import "github.com/florent/status-line/internal/domain/model"
+
+// SeparatorSet holds the glyphs joining powerline segments and closing pills.
+type SeparatorSet struct {
+	// Right separates segments flowing left to right.
+	Right string
+	// Left separates segments flowing right to left.
+	Left string
+	// CapLeft opens a pill.
+	CapLeft string
+	// CapRight closes a pill.
+	CapRight string
+}
+
+// LookupSeparatorSet returns a built-in separator set by name.
+//
+// Params:
+//   - name: separator set name (auto is resolved by the configuration)
+//
+// Returns:
+//   - *SeparatorSet: the separator set, nil if unknown
+//   - bool: true if the set exists
+func LookupSeparatorSet(name string) (*SeparatorSet, bool) {
+	// Match by name
+	switch name {
+	// Powerline arrows
+	case model.SeparatorsPowerline:
+		// Return powerline set
+		return PowerlineSeparators(), true
+	// Half circles
+	case model.SeparatorsRounded:
+		// Return rounded set
+		return &SeparatorSet{Right: "\uE0B4", Left: "\uE0B6", CapLeft: "\uE0B6", CapRight: "\uE0B4"}, true
+	// Diagonals
+	case model.SeparatorsSlanted:
+		// Return slanted set
+		return &SeparatorSet{Right: "\uE0BC", Left: "\uE0BA", CapLeft: "\uE0BA", CapRight: "\uE0BC"}, true
+	// Plain pipes without caps
+	case model.SeparatorsFlat:
+		// Return flat set
+		return &SeparatorSet{Right: "|", Left: "|"}, true
+	// Unknown name
+	default:
+		// Return not found
+		return nil, false
+	}
+}
+
+// NewSeparatorSet returns the separator set selected by the configuration.
+// Unknown names fall back to powerline arrows, since the configuration has
+// been validated beforehand.
+//
+// Params:
+//   - cfg: user configuration
+//
+// Returns:
+//   - *SeparatorSet: resolved separator set
+func NewSeparatorSet(cfg model.Config) *SeparatorSet {
+	set, ok := LookupSeparatorSet(cfg.EffectiveSeparators())
+	// Fall back to powerline arrows
+	if !ok {
+		// Return default set
+		return PowerlineSeparators()
+	}
+	// Return configured set
+	return set
+}
+
+// PowerlineSeparators returns the powerline arrows with rounded pill caps.
+//
+// Returns:
+//   - *SeparatorSet: separators requiring a patched font
+func PowerlineSeparators() *SeparatorSet {
+	// Return powerline glyphs
+	return &SeparatorSet{Right: "\uE0B0", Left: "\uE0B2", CapLeft: "\uE0B6", CapRight: "\uE0B4"}
+}
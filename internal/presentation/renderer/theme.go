@@ -0,0 +1,194 @@
+// Package renderer provides status line rendering.
+package renderer
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/florent/status-line/internal/domain/model"
+)
+
+// Theme maps semantic color roles to colors.
+// Segments ask the theme for escape sequences instead of using fixed codes.
+type Theme struct {
+	name   string
+	colors map[string]model.Color
+	depth  model.ColorDepth
+}
+
+// LookupTheme returns a built-in theme by name.
+//
+// Params:
+//   - name: theme name
+//
+// Returns:
+//   - *Theme: the theme, nil if unknown
+//   - bool: true if the theme exists
+func LookupTheme(name string) (*Theme, bool) {
+	palette, ok := builtinPalettes()[name]
+	// Check for unknown theme
+	if !ok {
+		// Return not found
+		return nil, false
+	}
+	// Return built-in theme
+	return &Theme{name: name, colors: palette}, true
+}
+
+// DefaultTheme returns the original pastel theme.
+//
+// Returns:
+//   - *Theme: default theme
+func DefaultTheme() *Theme {
+	// Return default palette
+	return &Theme{name: model.ThemeDefault, colors: defaultPalette()}
+}
+
+// NewTheme builds the theme selected by the configuration.
+// The named built-in theme is used as base and the configured colors override
+// individual roles. Unknown names and invalid values fall back to the base,
+// since the configuration has been validated beforehand.
+//
+// Params:
+//   - cfg: user configuration
+//
+// Returns:
+//   - *Theme: resolved theme
+func NewTheme(cfg model.Config) *Theme {
+	theme, ok := LookupTheme(cfg.Theme)
+	// Fall back to the default theme
+	if !ok {
+		theme = DefaultTheme()
+	}
+	// Apply role overrides
+	for role, value := range cfg.Colors {
+		// Skip invalid values
+		if c, err := model.ParseColor(value); err == nil {
+			theme.colors[role] = c
+		}
+	}
+	// Return resolved theme
+	return theme
+}
+
+// Name returns the base theme name.
+//
+// Returns:
+//   - string: theme name
+func (t *Theme) Name() string {
+	// Return name
+	return t.name
+}
+
+// Depth returns the color depth escape sequences are downsampled to.
+//
+// Returns:
+//   - model.ColorDepth: output depth, unknown when colors are emitted as defined
+func (t *Theme) Depth() model.ColorDepth {
+	// Return depth
+	return t.depth
+}
+
+// WithDepth returns a copy of the theme emitting colors for a terminal depth.
+//
+// Params:
+//   - depth: terminal color depth
+//
+// Returns:
+//   - *Theme: theme sharing the same colors
+func (t *Theme) WithDepth(depth model.ColorDepth) *Theme {
+	// Return copy with depth
+	return &Theme{name: t.name, colors: t.colors, depth: depth}
+}
+
+// Color returns the color of a role.
+//
+// Params:
+//   - role: semantic role
+//
+// Returns:
+//   - model.Color: role color (palette index 0 if unset)
+func (t *Theme) Color(role string) model.Color {
+	// Return role color
+	return t.colors[role]
+}
+
+// Fg returns the escape sequence using a role color as foreground.
+// For background roles this is the color of caps and separators.
+//
+// Params:
+//   - role: semantic role
+//
+// Returns:
+//   - string: ANSI escape sequence
+func (t *Theme) Fg(role string) string {
+	// Build foreground sequence
+	return fgCode(t.Color(role), t.depth)
+}
+
+// Bg returns the escape sequence using a role color as background.
+//
+// Params:
+//   - role: semantic role
+//
+// Returns:
+//   - string: ANSI escape sequence
+func (t *Theme) Bg(role string) string {
+	// Build background sequence
+	return bgCode(t.Color(role), t.depth)
+}
+
+// Colors returns a copy of the role to color map.
+//
+// Returns:
+//   - map[string]model.Color: theme colors
+func (t *Theme) Colors() map[string]model.Color {
+	// Return copy so callers cannot mutate the theme
+	return maps.Clone(t.colors)
+}
+
+// ModelColors returns colors for the model segment.
+//
+// Params:
+//   - modelName: the model family or display name of the AI model
+//
+// Returns:
+//   - bgColor: background color for the segment
+//   - fgColor: foreground color for caps and separators
+//   - textColor: foreground color for text on the segment
+func (t *Theme) ModelColors(modelName string) (bgColor, fgColor, textColor string) {
+	bgRole, textRole := modelRoles(modelName)
+	// Return themed colors
+	return t.Bg(bgRole), t.Fg(bgRole), t.Fg(textRole)
+}
+
+// modelRoles returns the color roles of a model family.
+//
+// Params:
+//   - modelName: the model family or display name of the AI model
+//
+// Returns:
+//   - bgRole: background role
+//   - textRole: text role
+func modelRoles(modelName string) (bgRole, textRole string) {
+	nameLower := strings.ToLower(modelName)
+	// Detect model type and return appropriate roles
+	switch {
+	// Haiku model
+	case strings.Contains(nameLower, "haiku"):
+		// Return Haiku roles
+		return model.RoleModelHaikuBg, model.RoleModelHaikuFg
+	// Sonnet model
+	case strings.Contains(nameLower, "sonnet"):
+		// Return Sonnet roles
+		return model.RoleModelSonnetBg, model.RoleModelSonnetFg
+	// Opus model
+	case strings.Contains(nameLower, "opus"):
+		// Return Opus roles
+		return model.RoleModelOpusBg, model.RoleModelOpusFg
+	// Unknown models
+	default:
+		// Return fallback roles
+		return model.RoleModelOtherBg, model.RoleModelOtherFg
+	}
+}
@@ -0,0 +1,122 @@
+package renderer_test
+
+import (
+	"testing"
+
+	"github.com/florent/status-line/internal/domain/model"
+	"github.com/florent/status-line/internal/presentation/renderer"
+)
+
+func TestTheme_ModelColors(t *testing.T) {
+	tests := []struct {
+		name      string
+		modelName string
+		wantBg    string
+		wantFg    string
+		wantText  string
+	}{
+		{name: "haiku model", modelName: "Haiku 3.5", wantBg: "\033[48;5;218m", wantFg: "\033[38;5;218m", wantText: "\033[38;5;168m"},
+		{name: "sonnet model", modelName: "Sonnet 3.5", wantBg: "\033[48;5;183m", wantFg: "\033[38;5;183m", wantText: "\033[38;5;97m"},
+		{name: "opus model", modelName: "Opus 4.5", wantBg: "\033[48;5;222m", wantFg: "\033[38;5;222m", wantText: "\033[38;5;172m"},
+		{name: "unknown model", modelName: "Unknown", wantBg: "\033[48;5;255m", wantFg: "\033[38;5;255m", wantText: "\033[38;5;232m"},
+		{name: "model family", modelName: "sonnet", wantBg: "\033[48;5;183m", wantFg: "\033[38;5;183m", wantText: "\033[38;5;97m"},
+		{name: "case insensitive", modelName: "OPUS", wantBg: "\033[48;5;222m", wantFg: "\033[38;5;222m", wantText: "\033[38;5;172m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bg, fg, text := renderer.DefaultTheme().ModelColors(tt.modelName)
+			if bg != tt.wantBg || fg != tt.wantFg || text != tt.wantText {
+				t.Errorf("ModelColors(%q) = (%q, %q, %q), want (%q, %q, %q)", tt.modelName, bg, fg, text, tt.wantBg, tt.wantFg, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestTheme_ModelColors_Roles(t *testing.T) {
+	nord, _ := renderer.LookupTheme(model.ThemeNord)
+	tests := []struct {
+		name      string
+		theme     *renderer.Theme
+		modelName string
+		wantBg    string
+		wantText  string
+	}{
+		{name: "haiku model", theme: renderer.DefaultTheme(), modelName: "Haiku 3.5", wantBg: model.RoleModelHaikuBg, wantText: model.RoleModelHaikuFg},
+		{name: "sonnet model", theme: renderer.DefaultTheme(), modelName: "Sonnet 3.5", wantBg: model.RoleModelSonnetBg, wantText: model.RoleModelSonnetFg},
+		{name: "opus model", theme: renderer.DefaultTheme(), modelName: "Opus 4.5", wantBg: model.RoleModelOpusBg, wantText: model.RoleModelOpusFg},
+		{name: "unknown model", theme: renderer.DefaultTheme(), modelName: "Unknown", wantBg: model.RoleModelOtherBg, wantText: model.RoleModelOtherFg},
+		{name: "case insensitive", theme: renderer.DefaultTheme(), modelName: "OPUS", wantBg: model.RoleModelOpusBg, wantText: model.RoleModelOpusFg},
+		{name: "active theme", theme: nord, modelName: "Opus 4.5", wantBg: model.RoleModelOpusBg, wantText: model.RoleModelOpusFg},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bg, fg, text := tt.theme.ModelColors(tt.modelName)
+			wantBg, wantFg, wantText := tt.theme.Bg(tt.wantBg), tt.theme.Fg(tt.wantBg), tt.theme.Fg(tt.wantText)
+			if bg != wantBg || fg != wantFg || text != wantText {
+				t.Errorf("ModelColors(%q) = (%q, %q, %q), want (%q, %q, %q)", tt.modelName, bg, fg, text, wantBg, wantFg, wantText)
+			}
+		})
+	}
+}
+
+func TestLookupTheme(t *testing.T) {
+	tests := []struct {
+		name   string
+		theme  string
+		wantOK bool
+	}{
+		{name: "default", theme: model.ThemeDefault, wantOK: true},
+		{name: "solarized", theme: model.ThemeSolarized, wantOK: true},
+		{name: "nord", theme: model.ThemeNord, wantOK: true},
+		{name: "high contrast", theme: model.ThemeHighContrast, wantOK: true},
+		{name: "monochrome", theme: model.ThemeMonochrome, wantOK: true},
+		{name: "unknown", theme: "dracula", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme, ok := renderer.LookupTheme(tt.theme)
+			if ok != tt.wantOK {
+				t.Fatalf("LookupTheme(%q) ok = %v, want %v", tt.theme, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			colors := theme.Colors()
+			for _, role := range model.ThemeRoles() {
+				if _, found := colors[role]; !found {
+					t.Errorf("theme %q has no color for role %q", tt.theme, role)
+				}
+			}
+		})
+	}
+}
+
+func TestNewTheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		theme    string
+		colors   map[string]string
+		role     string
+		wantName string
+		wantFg   string
+	}{
+		{name: "built-in theme", theme: model.ThemeNord, role: model.RoleGitBg, wantName: "nord", wantFg: "\033[38;2;136;192;208m"},
+		{name: "override by hex", theme: model.ThemeDefault, colors: map[string]string{"git.bg": "#102030"}, role: model.RoleGitBg, wantName: "default", wantFg: "\033[38;2;16;32;48m"},
+		{name: "override by index", theme: model.ThemeDefault, colors: map[string]string{"git.bg": "33"}, role: model.RoleGitBg, wantName: "default", wantFg: "\033[38;5;33m"},
+		{name: "unknown theme falls back", theme: "dracula", role: model.RoleGitBg, wantName: "default", wantFg: "\033[38;5;116m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := model.DefaultConfig()
+			cfg.Theme = tt.theme
+			cfg.Colors = tt.colors
+			theme := renderer.NewTheme(cfg)
+			if theme.Name() != tt.wantName {
+				t.Errorf("NewTheme().Name() = %q, want %q", theme.Name(), tt.wantName)
+			}
+			if got := theme.Fg(tt.role); got != tt.wantFg {
+				t.Errorf("NewTheme().Fg(%q) = %q, want %q", tt.role, got, tt.wantFg)
+			}
+		})
+	}
+}
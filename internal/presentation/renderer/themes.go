@@ -0,0 +1,357 @@
+// Package renderer provides status line rendering.
+package renderer
+
+import "github.com/florent/status-line/internal/domain/model"
+
+// builtinPalettes returns fresh copies of every built-in palette by theme name.
+//
+// Returns:
+//   - map[string]map[string]model.Color: palettes keyed by theme name
+func builtinPalettes() map[string]map[string]model.Color {
+	// Return palettes
+	return map[string]map[string]model.Color{
+		model.ThemeDefault:      defaultPalette(),
+		model.ThemeSolarized:    solarizedPalette(),
+		model.ThemeNord:         nordPalette(),
+		model.ThemeHighContrast: highContrastPalette(),
+		model.ThemeMonochrome:   monochromePalette(),
+	}
+}
+
+// defaultPalette returns the original pastel 256-color palette.
+//
+// Returns:
+//   - map[string]model.Color: role colors
+func defaultPalette() map[string]model.Color {
+	ix := model.IndexedColor
+	// Return palette
+	return map[string]model.Color{
+		model.RoleOSBg:             ix(255),
+		model.RoleOSFg:             ix(232),
+		model.RoleModelHaikuBg:     ix(218),
+		model.RoleModelHaikuFg:     ix(168),
+		model.RoleModelSonnetBg:    ix(183),
+		model.RoleModelSonnetFg:    ix(97),
+		model.RoleModelOpusBg:      ix(222),
+		model.RoleModelOpusFg:      ix(172),
+		model.RoleModelOtherBg:     ix(255),
+		model.RoleModelOtherFg:     ix(232),
+		model.RoleWeeklyBg:         ix(252),
+		model.RoleWeeklyFg:         ix(240),
+		model.RoleSparklineBg:      ix(254),
+		model.RoleSparklineFg:      ix(60),
+		model.RolePathBg:           ix(111),
+		model.RolePathFg:           ix(25),
+		model.RoleGitBg:            ix(116),
+		model.RoleGitFg:            ix(30),
+		model.RoleChangesAddedBg:   ix(114),
+		model.RoleChangesAddedFg:   ix(28),
+		model.RoleChangesRemovedBg: ix(174),
+		model.RoleChangesRemovedFg: ix(124),
+		model.RoleMCPEnabledBg:     ix(116),
+		model.RoleMCPEnabledFg:     ix(30),
+		model.RoleMCPDisabledBg:    ix(250),
+		model.RoleMCPDisabledFg:    ix(240),
+		model.RoleCostBg:           ix(253),
+		model.RoleCostFg:           ix(238),
+		model.RoleCostWarningBg:    ix(223),
+		model.RoleCostWarningFg:    ix(130),
+		model.RoleCostCriticalBg:   ix(217),
+		model.RoleCostCriticalFg:   ix(124),
+		model.RoleActivityBg:       ix(180),
+		model.RoleActivityFg:       ix(94),
+		model.RoleTasksBg:          ix(147),
+		model.RoleTasksFg:          ix(55),
+		model.RoleTasksBarBg:       ix(255),
+		model.RoleTasksBarFg:       ix(232),
+		model.RoleTasksProgress:    ix(245),
+		model.RoleTasksMuted:       ix(245),
+		model.RoleTasksDone:        ix(34),
+		model.RoleTasksWip:         ix(214),
+		model.RoleTasksTodo:        ix(240),
+		model.RoleTasksCurrent:     ix(44),
+		model.RoleUpdateBg:         ix(255),
+		model.RoleUpdateFg:         ix(232),
+		model.RoleCursor:           ix(166),
+		model.RolePaceOnTrack:      ix(28),
+		model.RolePaceAhead:        ix(130),
+		model.RolePaceOver:         ix(124),
+	}
+}
+
+// solarizedPalette returns a palette built on the Solarized accent colors.
+//
+// Returns:
+//   - map[string]model.Color: role colors
+func solarizedPalette() map[string]model.Color {
+	var (
+		base03  = model.HexColor(0x002b36)
+		base02  = model.HexColor(0x073642)
+		base01  = model.HexColor(0x586e75)
+		base1   = model.HexColor(0x93a1a1)
+		base2   = model.HexColor(0xeee8d5)
+		base3   = model.HexColor(0xfdf6e3)
+		yellow  = model.HexColor(0xb58900)
+		orange  = model.HexColor(0xcb4b16)
+		red     = model.HexColor(0xdc322f)
+		magenta = model.HexColor(0xd33682)
+		violet  = model.HexColor(0x6c71c4)
+		blue    = model.HexColor(0x268bd2)
+		cyan    = model.HexColor(0x2aa198)
+		green   = model.HexColor(0x859900)
+	)
+	// Return palette
+	return map[string]model.Color{
+		model.RoleOSBg:             base2,
+		model.RoleOSFg:             base02,
+		model.RoleModelHaikuBg:     magenta,
+		model.RoleModelHaikuFg:     base3,
+		model.RoleModelSonnetBg:    violet,
+		model.RoleModelSonnetFg:    base3,
+		model.RoleModelOpusBg:      orange,
+		model.RoleModelOpusFg:      base3,
+		model.RoleModelOtherBg:     base1,
+		model.RoleModelOtherFg:     base03,
+		model.RoleWeeklyBg:         base01,
+		model.RoleWeeklyFg:         base2,
+		model.RoleSparklineBg:      base02,
+		model.RoleSparklineFg:      cyan,
+		model.RolePathBg:           blue,
+		model.RolePathFg:           base3,
+		model.RoleGitBg:            cyan,
+		model.RoleGitFg:            base3,
+		model.RoleChangesAddedBg:   green,
+		model.RoleChangesAddedFg:   base3,
+		model.RoleChangesRemovedBg: red,
+		model.RoleChangesRemovedFg: base3,
+		model.RoleMCPEnabledBg:     cyan,
+		model.RoleMCPEnabledFg:     base3,
+		model.RoleMCPDisabledBg:    base02,
+		model.RoleMCPDisabledFg:    base1,
+		model.RoleCostBg:           base2,
+		model.RoleCostFg:           base01,
+		model.RoleCostWarningBg:    yellow,
+		model.RoleCostWarningFg:    base03,
+		model.RoleCostCriticalBg:   red,
+		model.RoleCostCriticalFg:   base3,
+		model.RoleActivityBg:       cyan,
+		model.RoleActivityFg:       base03,
+		model.RoleTasksBg:          violet,
+		model.RoleTasksFg:          base3,
+		model.RoleTasksBarBg:       base3,
+		model.RoleTasksBarFg:       base03,
+		model.RoleTasksProgress:    base1,
+		model.RoleTasksMuted:       base2,
+		model.RoleTasksDone:        green,
+		model.RoleTasksWip:         yellow,
+		model.RoleTasksTodo:        base02,
+		model.RoleTasksCurrent:     base3,
+		model.RoleUpdateBg:         yellow,
+		model.RoleUpdateFg:         base03,
+		model.RoleCursor:           red,
+		model.RolePaceOnTrack:      green,
+		model.RolePaceAhead:        yellow,
+		model.RolePaceOver:         red,
+	}
+}
+
+// nordPalette returns a palette built on the Nord colors.
+//
+// Returns:
+//   - map[string]model.Color: role colors
+func nordPalette() map[string]model.Color {
+	var (
+		nord0  = model.HexColor(0x2e3440)
+		nord2  = model.HexColor(0x434c5e)
+		nord3  = model.HexColor(0x4c566a)
+		nord4  = model.HexColor(0xd8dee9)
+		nord6  = model.HexColor(0xeceff4)
+		nord7  = model.HexColor(0x8fbcbb)
+		nord8  = model.HexColor(0x88c0d0)
+		nord9  = model.HexColor(0x81a1c1)
+		nord10 = model.HexColor(0x5e81ac)
+		nord11 = model.HexColor(0xbf616a)
+		nord12 = model.HexColor(0xd08770)
+		nord13 = model.HexColor(0xebcb8b)
+		nord14 = model.HexColor(0xa3be8c)
+		nord15 = model.HexColor(0xb48ead)
+	)
+	// Return palette
+	return map[string]model.Color{
+		model.RoleOSBg:             nord6,
+		model.RoleOSFg:             nord0,
+		model.RoleModelHaikuBg:     nord15,
+		model.RoleModelHaikuFg:     nord0,
+		model.RoleModelSonnetBg:    nord9,
+		model.RoleModelSonnetFg:    nord0,
+		model.RoleModelOpusBg:      nord12,
+		model.RoleModelOpusFg:      nord0,
+		model.RoleModelOtherBg:     nord4,
+		model.RoleModelOtherFg:     nord0,
+		model.RoleWeeklyBg:         nord4,
+		model.RoleWeeklyFg:         nord3,
+		model.RoleSparklineBg:      nord2,
+		model.RoleSparklineFg:      nord8,
+		model.RolePathBg:           nord10,
+		model.RolePathFg:           nord6,
+		model.RoleGitBg:            nord8,
+		model.RoleGitFg:            nord0,
+		model.RoleChangesAddedBg:   nord14,
+		model.RoleChangesAddedFg:   nord0,
+		model.RoleChangesRemovedBg: nord11,
+		model.RoleChangesRemovedFg: nord6,
+		model.RoleMCPEnabledBg:     nord7,
+		model.RoleMCPEnabledFg:     nord0,
+		model.RoleMCPDisabledBg:    nord2,
+		model.RoleMCPDisabledFg:    nord4,
+		model.RoleCostBg:           nord3,
+		model.RoleCostFg:           nord6,
+		model.RoleCostWarningBg:    nord13,
+		model.RoleCostWarningFg:    nord0,
+		model.RoleCostCriticalBg:   nord11,
+		model.RoleCostCriticalFg:   nord6,
+		model.RoleActivityBg:       nord8,
+		model.RoleActivityFg:       nord0,
+		model.RoleTasksBg:          nord15,
+		model.RoleTasksFg:          nord0,
+		model.RoleTasksBarBg:       nord6,
+		model.RoleTasksBarFg:       nord0,
+		model.RoleTasksProgress:    nord3,
+		model.RoleTasksMuted:       nord2,
+		model.RoleTasksDone:        nord14,
+		model.RoleTasksWip:         nord13,
+		model.RoleTasksTodo:        nord3,
+		model.RoleTasksCurrent:     nord0,
+		model.RoleUpdateBg:         nord13,
+		model.RoleUpdateFg:         nord0,
+		model.RoleCursor:           nord11,
+		model.RolePaceOnTrack:      nord14,
+		model.RolePaceAhead:        nord13,
+		model.RolePaceOver:         nord11,
+	}
+}
+
+// highContrastPalette returns saturated backgrounds with black or white text.
+//
+// Returns:
+//   - map[string]model.Color: role colors
+func highContrastPalette() map[string]model.Color {
+	ix := model.IndexedColor
+	black, white := ix(16), ix(231)
+	// Return palette
+	return map[string]model.Color{
+		model.RoleOSBg:             white,
+		model.RoleOSFg:             black,
+		model.RoleModelHaikuBg:     ix(201),
+		model.RoleModelHaikuFg:     black,
+		model.RoleModelSonnetBg:    ix(141),
+		model.RoleModelSonnetFg:    black,
+		model.RoleModelOpusBg:      ix(214),
+		model.RoleModelOpusFg:      black,
+		model.RoleModelOtherBg:     white,
+		model.RoleModelOtherFg:     black,
+		model.RoleWeeklyBg:         ix(250),
+		model.RoleWeeklyFg:         black,
+		model.RoleSparklineBg:      black,
+		model.RoleSparklineFg:      ix(51),
+		model.RolePathBg:           ix(21),
+		model.RolePathFg:           white,
+		model.RoleGitBg:            ix(51),
+		model.RoleGitFg:            black,
+		model.RoleChangesAddedBg:   ix(46),
+		model.RoleChangesAddedFg:   black,
+		model.RoleChangesRemovedBg: ix(196),
+		model.RoleChangesRemovedFg: white,
+		model.RoleMCPEnabledBg:     ix(51),
+		model.RoleMCPEnabledFg:     black,
+		model.RoleMCPDisabledBg:    ix(240),
+		model.RoleMCPDisabledFg:    white,
+		model.RoleCostBg:           white,
+		model.RoleCostFg:           black,
+		model.RoleCostWarningBg:    ix(226),
+		model.RoleCostWarningFg:    black,
+		model.RoleCostCriticalBg:   ix(196),
+		model.RoleCostCriticalFg:   white,
+		model.RoleActivityBg:       ix(51),
+		model.RoleActivityFg:       black,
+		model.RoleTasksBg:          ix(141),
+		model.RoleTasksFg:          black,
+		model.RoleTasksBarBg:       white,
+		model.RoleTasksBarFg:       black,
+		model.RoleTasksProgress:    ix(240),
+		model.RoleTasksMuted:       black,
+		model.RoleTasksDone:        ix(22),
+		model.RoleTasksWip:         ix(202),
+		model.RoleTasksTodo:        ix(238),
+		model.RoleTasksCurrent:     ix(17),
+		model.RoleUpdateBg:         ix(226),
+		model.RoleUpdateFg:         black,
+		model.RoleCursor:           ix(196),
+		model.RolePaceOnTrack:      ix(22),
+		model.RolePaceAhead:        ix(94),
+		model.RolePaceOver:         ix(88),
+	}
+}
+
+// monochromePalette returns a palette using shades of gray only.
+// Neighboring segments use different shades so separators stay visible.
+//
+// Returns:
+//   - map[string]model.Color: role colors
+func monochromePalette() map[string]model.Color {
+	ix := model.IndexedColor
+	black, white := ix(232), ix(255)
+	// Return palette
+	return map[string]model.Color{
+		model.RoleOSBg:             white,
+		model.RoleOSFg:             black,
+		model.RoleModelHaikuBg:     ix(250),
+		model.RoleModelHaikuFg:     black,
+		model.RoleModelSonnetBg:    ix(250),
+		model.RoleModelSonnetFg:    black,
+		model.RoleModelOpusBg:      ix(250),
+		model.RoleModelOpusFg:      black,
+		model.RoleModelOtherBg:     ix(250),
+		model.RoleModelOtherFg:     black,
+		model.RoleWeeklyBg:         ix(246),
+		model.RoleWeeklyFg:         black,
+		model.RoleSparklineBg:      ix(240),
+		model.RoleSparklineFg:      white,
+		model.RolePathBg:           ix(253),
+		model.RolePathFg:           black,
+		model.RoleGitBg:            ix(248),
+		model.RoleGitFg:            black,
+		model.RoleChangesAddedBg:   ix(252),
+		model.RoleChangesAddedFg:   black,
+		model.RoleChangesRemovedBg: ix(244),
+		model.RoleChangesRemovedFg: black,
+		model.RoleMCPEnabledBg:     ix(250),
+		model.RoleMCPEnabledFg:     black,
+		model.RoleMCPDisabledBg:    ix(242),
+		model.RoleMCPDisabledFg:    ix(252),
+		model.RoleCostBg:           ix(250),
+		model.RoleCostFg:           black,
+		model.RoleCostWarningBg:    ix(240),
+		model.RoleCostWarningFg:    white,
+		model.RoleCostCriticalBg:   white,
+		model.RoleCostCriticalFg:   black,
+		model.RoleActivityBg:       ix(245),
+		model.RoleActivityFg:       black,
+		model.RoleTasksBg:          ix(248),
+		model.RoleTasksFg:          black,
+		model.RoleTasksBarBg:       white,
+		model.RoleTasksBarFg:       black,
+		model.RoleTasksProgress:    ix(244),
+		model.RoleTasksMuted:       ix(240),
+		model.RoleTasksDone:        black,
+		model.RoleTasksWip:         white,
+		model.RoleTasksTodo:        ix(242),
+		model.RoleTasksCurrent:     black,
+		model.RoleUpdateBg:         white,
+		model.RoleUpdateFg:         black,
+		model.RoleCursor:           white,
+		model.RolePaceOnTrack:      black,
+		model.RolePaceAhead:        ix(240),
+		model.RolePaceOver:         white,
+	}
+}
@@ -0,0 +1,100 @@
+// Package renderer provides status line rendering.
+package renderer
+
+import "unicode"
+
+// wideRange is an inclusive range of code points taking two terminal columns.
+type wideRange struct {
+	lo rune
+	hi rune
+}
+
+// wideRanges lists East Asian wide characters and emoji blocks.
+var wideRanges []wideRange = []wideRange{
+	{0x1100, 0x115F},   // Hangul Jamo initials
+	{0x231A, 0x231B},   // watch, hourglass
+	{0x23E9, 0x23EC},   // media control emoji
+	{0x23F0, 0x23F3},   // alarm clock, hourglass
+	{0x25FD, 0x25FE},   // small squares emoji
+	{0x2614, 0x2615},   // umbrella, hot beverage
+	{0x2648, 0x2653},   // zodiac
+	{0x26A1, 0x26A1},   // high voltage
+	{0x26AA, 0x26AB},   // circles
+	{0x26BD, 0x26BE},   // sports balls
+	{0x26C4, 0x26C5},   // snowman, sun
+	{0x26D4, 0x26D4},   // no entry
+	{0x26EA, 0x26EA},   // church
+	{0x26F2, 0x26F5},   // fountain to sailboat
+	{0x26FA, 0x26FD},   // tent to fuel pump
+	{0x2705, 0x2705},   // check mark button
+	{0x270A, 0x270B},   // raised fists
+	{0x2728, 0x2728},   // sparkles
+	{0x274C, 0x274C},   // cross mark
+	{0x2753, 0x2755},   // question marks
+	{0x2795, 0x2797},   // math emoji
+	{0x2B1B, 0x2B1C},   // large squares
+	{0x2B50, 0x2B50},   // star
+	{0x2E80, 0x303E},   // CJK radicals and punctuation
+	{0x3041, 0x33FF},   // Kana and CJK compatibility
+	{0x3400, 0x4DBF},   // CJK extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE30, 0xFE4F},   // CJK compatibility forms
+	{0xFF00, 0xFF60},   // fullwidth forms
+	{0xFFE0, 0xFFE6},   // fullwidth signs
+	{0x1F300, 0x1F64F}, // pictographs and emoticons
+	{0x1F680, 0x1F6FF}, // transport and map symbols
+	{0x1F900, 0x1F9FF}, // supplemental pictographs
+	{0x1FA70, 0x1FAFF}, // extended pictographs
+	{0x20000, 0x3FFFD}, // CJK extensions B and later
+}
+
+// visibleWidth returns the number of terminal columns s occupies.
+// SGR escape sequences take no space, combining marks and zero-width
+// characters take none, and wide characters take two columns.
+//
+// Params:
+//   - s: rendered text with escape sequences
+//
+// Returns:
+//   - int: display width in columns
+func visibleWidth(s string) int {
+	width := 0
+	// Measure each character outside escape sequences
+	for _, r := range stripSGR(s) {
+		width += runeWidth(r)
+	}
+	// Return display width
+	return width
+}
+
+// runeWidth returns the number of terminal columns of a character.
+//
+// Params:
+//   - r: character to measure
+//
+// Returns:
+//   - int: 0, 1 or 2 columns
+func runeWidth(r rune) int {
+	// Zero-width characters: combining marks, joiners and variation selectors
+	if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r) || (r >= 0xFE00 && r <= 0xFE0F) {
+		// Return no width
+		return 0
+	}
+	// Check wide ranges
+	for _, wr := range wideRanges {
+		// Stop once past the character, ranges are sorted
+		if r < wr.lo {
+			break
+		}
+		// Check membership
+		if r <= wr.hi {
+			// Return double width
+			return 2
+		}
+	}
+	// Return single width
+	return 1
+}
@@ -0,0 +1,28 @@
+package renderer
+
+import "testing"
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{name: "empty", input: "", want: 0},
+		{name: "ascii", input: "main", want: 4},
+		{name: "escape sequences ignored", input: "\033[48;5;111m\033[1m path \033[0m", want: 6},
+		{name: "nerd glyph", input: "", want: 1},
+		{name: "box drawing", input: "━━─", want: 3},
+		{name: "emoji", input: "📁 dir", want: 6},
+		{name: "cjk", input: "日本", want: 4},
+		{name: "combining mark", input: "é", want: 1},
+		{name: "variation selector", input: "⬇️", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := visibleWidth(tt.input); got != tt.want {
+				t.Errorf("visibleWidth(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}